@@ -0,0 +1,55 @@
+// Command automod checks a rules file against a sample post or comment
+// without touching any database:
+//
+//	automod -rules rules.json -sample post.json
+//
+// The sample is read from stdin when -sample is not set.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/automod"
+	"io"
+	"os"
+)
+
+func main() {
+	rulesPath := flag.String("rules", "", "rules file")
+	samplePath := flag.String("sample", "", "sample post or comment, stdin if empty")
+	flag.Parse()
+	if err := run(*rulesPath, *samplePath, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(rulesPath, samplePath string, out io.Writer) error {
+	if rulesPath == "" {
+		return fmt.Errorf("-rules is required")
+	}
+	buf, err := os.ReadFile(rulesPath)
+	if err != nil {
+		return err
+	}
+	rules, err := automod.Parse(buf)
+	if err != nil {
+		return err
+	}
+	if samplePath == "" {
+		buf, err = io.ReadAll(os.Stdin)
+	} else {
+		buf, err = os.ReadFile(samplePath)
+	}
+	if err != nil {
+		return err
+	}
+	item := automod.Item{Kind: automod.KindPost}
+	if err = json.Unmarshal(buf, &item); err != nil {
+		return fmt.Errorf("invalid sample: %w", err)
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(automod.NewStaticEngine(rules).Evaluate(item))
+}
@@ -3,12 +3,27 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/gorilla/mux"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/automod"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/blob"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/hasher"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/keyring"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/middleware"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData/communityDataMySQL"
 	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
 	itemdatamongo "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData/itemDataMongo"
+	messagedata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/messageData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/repository/messageData/messageDataMySQL"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData/notificationDataMySQL"
+	reportdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData/reportDataMySQL"
+	spamdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/spamData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/repository/spamData/spamDataMySQL"
 	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData/userDataMySQL"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/server"
@@ -18,48 +33,174 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"golang.org/x/crypto/bcrypt"
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 )
 
 func main() {
 	logger := log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile)
-	key := []byte("super secret")
+	keys, err := keyring.LoadEnv()
+	if errors.Is(err, keyring.ErrNoKeys) {
+		logger.Println("JWT_KEYS_FILE and JWT_KEYS are empty, using an ephemeral signing key")
+		keys, err = keyring.NewEphemeral()
+	}
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
 	ctx := context.Background()
 	db := mySQLInit("root", "123", "dbMySQL", "3306", "webDB", logger)
 	collection, client := mongoInit(ctx, "dbMongo", "27017", "webDB", "posts")
 	defer closeDB(client)
 	var usData userdata.UserData = userdatamysql.NewUserDataMySql(db)
-	var itmData itemdata.ItemData = itemdatamongo.NewItemDataMongo(collection, ctx)
+	posts := itemdatamongo.NewItemDataMongo(collection, ctx)
+	if err = posts.EnsureIndexes(); err != nil {
+		logger.Fatal(err.Error())
+	}
+	var itmData itemdata.ItemData = posts
+	var commData communitydata.CommunityData = communitydatamysql.NewCommunityDataMySQL(db)
+	var repData reportdata.ReportData = reportdatamysql.NewReportDataMySQL(db)
+	var spamDat spamdata.SpamData = spamdatamysql.NewSpamDataMySQL(db)
+	var noteData notificationdata.NotificationData = notificationdatamysql.NewNotificationDataMySQL(db)
+	var msgData messagedata.MessageData = messagedatamysql.NewMessageDataMySQL(db)
 	var sesManager session.SesManager = sessionmanagermysql.NewSessionManagerMySQL(db)
-	serv := service.NewService(usData, itmData, sesManager, key)
+	passHasher := hasher.NewChain(hasher.NewArgon2id(), hasher.NewBcrypt(bcrypt.DefaultCost), hasher.NewMD5())
+	var rules *automod.Engine
+	if path := os.Getenv("AUTOMOD_RULES"); path != "" {
+		if rules, err = automod.NewEngine(path); err != nil {
+			logger.Fatal(err.Error())
+		}
+		stop := rules.Watch(5*time.Second, logger)
+		defer stop()
+	}
+	mediaDir := os.Getenv("MEDIA_DIR")
+	if mediaDir == "" {
+		mediaDir = "./media/"
+	}
+	media, err := blob.NewLocalStore(mediaDir, "/media/")
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+	// ADMIN_LOGINS is a comma separated list of the accounts made admins at startup,
+	// it is the only way to get the first admin who can then grant roles. The
+	// accounts have to be registered before the start.
+	adminLogins := strings.FieldsFunc(os.Getenv("ADMIN_LOGINS"), func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	if err = service.GrantAdmins(usData, adminLogins); err != nil {
+		logger.Fatal(err.Error())
+	}
+	serv := service.NewService(usData, itmData, commData, repData, spamDat, noteData, msgData, sesManager,
+		passHasher, keys, rules, media, logger)
 	srv := server.NewServer(serv, logger)
-	mid := middleware.NewMiddleware(key, sesManager, logger)
+	mid := middleware.NewMiddleware(keys, sesManager, logger)
 	r := mux.NewRouter()
 
 	r.Handle("/", http.FileServer(http.Dir("./static/html/")))
+	r.HandleFunc("/.well-known/jwks.json", srv.GetJWKS).Methods("GET")
 	r.PathPrefix("/static/").Handler(http.StripPrefix("/static/", http.FileServer(http.Dir("./static/"))))
+	r.PathPrefix("/media/").Handler(http.StripPrefix("/media/", media.Handler()))
 	r.Use(mid.Panic)
 	r.Use(mid.AccessLog)
 
 	routerSub := r.PathPrefix("/api").Subrouter()
+	routerSub.Use(mid.OptionalAuth)
 	routerSub.HandleFunc("/register", srv.Register).Methods("POST")
 	routerSub.HandleFunc("/login", srv.Login).Methods("POST")
+	routerSub.HandleFunc("/token/refresh", srv.RefreshToken).Methods("POST")
 	routerSub.HandleFunc("/posts/", srv.GetPosts).Methods("GET")
+	routerSub.HandleFunc("/feed", srv.GetFeed).Methods("GET")
 	routerSub.HandleFunc("/posts/{category}", srv.GetCategory).Methods("GET")
 	routerSub.HandleFunc("/user/{user_login}", srv.GetUser).Methods("GET")
+	routerSub.HandleFunc("/user/{user_login}/profile", srv.GetProfile).Methods("GET")
+	routerSub.HandleFunc("/user/{user_login}/comments", srv.GetUserComments).Methods("GET")
+	routerSub.HandleFunc("/user/{user_login}/overview", srv.GetOverview).Methods("GET")
 	routerSub.HandleFunc("/post/{post_id}", srv.GetPostID).Methods("GET")
+	routerSub.HandleFunc("/search", srv.Search).Methods("GET")
+	routerSub.HandleFunc("/communities", srv.GetCommunities).Methods("GET")
+	routerSub.HandleFunc("/community/{community}", srv.GetCommunity).Methods("GET")
+	routerSub.HandleFunc("/community/{community}/moderators", srv.GetModerators).Methods("GET")
+	routerSub.HandleFunc("/community/{community}/modlog", srv.GetModLog).Methods("GET")
 
 	routerPost := r.PathPrefix("/api").Subrouter()
 	routerPost.Use(mid.Auth)
 	routerPost.HandleFunc("/posts", srv.CreatePost).Methods("POST")
 	routerPost.HandleFunc("/post/{post_id}", srv.CreateComment).Methods("POST")
 	routerPost.HandleFunc("/post/{post_id}/{comment_id}", srv.DeleteComment).Methods("DELETE")
+	routerPost.HandleFunc("/post/{post_id}/{comment_id}", srv.EditComment).Methods("PUT")
 	routerPost.HandleFunc("/post/{post_id}/upvote", srv.Upvote).Methods("GET")
 	routerPost.HandleFunc("/post/{post_id}/downvote", srv.Downvote).Methods("GET")
 	routerPost.HandleFunc("/post/{post_id}/unvote", srv.Unvote).Methods("GET")
+	routerPost.HandleFunc("/post/{post_id}/{comment_id}/upvote", srv.UpvoteComment).Methods("GET")
+	routerPost.HandleFunc("/post/{post_id}/{comment_id}/downvote", srv.DownvoteComment).Methods("GET")
+	routerPost.HandleFunc("/post/{post_id}/{comment_id}/unvote", srv.UnvoteComment).Methods("GET")
 	routerPost.HandleFunc("/post/{post_id}", srv.DeletePost).Methods("DELETE")
+	routerPost.HandleFunc("/post/{post_id}", srv.EditPost).Methods("PUT")
+	routerPost.HandleFunc("/post/{post_id}/history", srv.GetPostHistory).Methods("GET")
+	routerPost.HandleFunc("/post/{post_id}/{comment_id}/history", srv.GetCommentHistory).Methods("GET")
+	routerPost.HandleFunc("/communities", srv.CreateCommunity).Methods("POST")
+	routerPost.HandleFunc("/community/{community}", srv.EditCommunity).Methods("PUT")
+	routerPost.HandleFunc("/community/{community}/subscribe", srv.Subscribe).Methods("POST")
+	routerPost.HandleFunc("/community/{community}/subscribe", srv.Unsubscribe).Methods("DELETE")
+	routerPost.HandleFunc("/subscriptions", srv.GetSubscriptions).Methods("GET")
+	routerPost.HandleFunc("/community/{community}/moderators/{user_login}", srv.AddModerator).Methods("PUT")
+	routerPost.HandleFunc("/community/{community}/moderators/{user_login}", srv.RemoveModerator).Methods("DELETE")
+	routerPost.HandleFunc("/community/{community}/bans/{user_login}", srv.BanUser).Methods("PUT")
+	routerPost.HandleFunc("/community/{community}/bans/{user_login}", srv.UnbanUser).Methods("DELETE")
+	routerPost.HandleFunc("/post/{post_id}/report", srv.ReportPost).Methods("POST")
+	routerPost.HandleFunc("/post/{post_id}/{comment_id}/report", srv.ReportComment).Methods("POST")
+	routerPost.HandleFunc("/reports", srv.GetMyReports).Methods("GET")
+	routerPost.HandleFunc("/notifications", srv.GetNotifications).Methods("GET")
+	routerPost.HandleFunc("/notifications/unread", srv.GetUnreadCount).Methods("GET")
+	routerPost.HandleFunc("/notifications/read", srv.MarkAllNotificationsRead).Methods("POST")
+	routerPost.HandleFunc("/notifications/{notification_id}/read", srv.MarkNotificationRead).Methods("POST")
+	routerPost.HandleFunc("/messages", srv.SendMessage).Methods("POST")
+	routerPost.HandleFunc("/messages/inbox", srv.GetInbox).Methods("GET")
+	routerPost.HandleFunc("/messages/sent", srv.GetSent).Methods("GET")
+	routerPost.HandleFunc("/messages/conversations/{conversation_id}", srv.GetConversation).Methods("GET")
+	routerPost.HandleFunc("/blocks", srv.GetBlocked).Methods("GET")
+	routerPost.HandleFunc("/blocks/{user_login}", srv.BlockUser).Methods("PUT")
+	routerPost.HandleFunc("/blocks/{user_login}", srv.UnblockUser).Methods("DELETE")
+	routerPost.HandleFunc("/follows", srv.GetFollowing).Methods("GET")
+	routerPost.HandleFunc("/follows/{user_login}", srv.FollowUser).Methods("PUT")
+	routerPost.HandleFunc("/follows/{user_login}", srv.UnfollowUser).Methods("DELETE")
+	routerPost.HandleFunc("/community/{community}/reports", srv.GetReportQueue).Methods("GET")
+	routerPost.HandleFunc("/community/{community}/reports/approve", srv.ApproveReport).Methods("POST")
+	routerPost.HandleFunc("/community/{community}/reports/remove", srv.RemoveReport).Methods("POST")
+	routerPost.HandleFunc("/post/{post_id}/lock", srv.LockPost).Methods("POST")
+	routerPost.HandleFunc("/post/{post_id}/unlock", srv.UnlockPost).Methods("POST")
+	routerPost.HandleFunc("/post/{post_id}/pin", srv.PinPost).Methods("POST")
+	routerPost.HandleFunc("/post/{post_id}/unpin", srv.UnpinPost).Methods("POST")
+	routerPost.HandleFunc("/community/{community}/held", srv.GetHeldItems).Methods("GET")
+	routerPost.HandleFunc("/post/{post_id}/approve", srv.ApprovePost).Methods("POST")
+	routerPost.HandleFunc("/post/{post_id}/{comment_id}/approve", srv.ApproveComment).Methods("POST")
+	routerPost.HandleFunc("/post/{post_id}/save", srv.Save).Methods("POST")
+	routerPost.HandleFunc("/post/{post_id}/unsave", srv.Unsave).Methods("POST")
+	routerPost.HandleFunc("/post/{post_id}/hide", srv.Hide).Methods("POST")
+	routerPost.HandleFunc("/post/{post_id}/unhide", srv.Unhide).Methods("POST")
+	routerPost.HandleFunc("/post/{post_id}/{comment_id}/save", srv.Save).Methods("POST")
+	routerPost.HandleFunc("/post/{post_id}/{comment_id}/unsave", srv.Unsave).Methods("POST")
+	routerPost.HandleFunc("/post/{post_id}/{comment_id}/hide", srv.Hide).Methods("POST")
+	routerPost.HandleFunc("/post/{post_id}/{comment_id}/unhide", srv.Unhide).Methods("POST")
+	routerPost.HandleFunc("/user/{user_login}/saved", srv.GetSaved).Methods("GET")
+	routerPost.HandleFunc("/logout", srv.Logout).Methods("POST")
+	routerPost.HandleFunc("/sessions", srv.GetSessions).Methods("GET")
+	routerPost.HandleFunc("/sessions", srv.DeleteSessions).Methods("DELETE")
+	routerPost.HandleFunc("/sessions/{session_id}", srv.DeleteSession).Methods("DELETE")
+
+	routerAdmin := r.PathPrefix("/api/admin").Subrouter()
+	routerAdmin.Use(mid.Auth)
+	routerAdmin.Use(mid.RequireRole(userdata.RoleModerator))
+	routerAdmin.HandleFunc("/post/{post_id}", srv.AdminDeletePost).Methods("DELETE")
+	routerAdmin.HandleFunc("/post/{post_id}/{comment_id}", srv.AdminDeleteComment).Methods("DELETE")
+	adminOnly := mid.RequireRole(userdata.RoleAdmin)
+	routerAdmin.Handle("/users", adminOnly(http.HandlerFunc(srv.ListUsers))).Methods("GET")
+	routerAdmin.Handle("/users/{user_id}/role", adminOnly(http.HandlerFunc(srv.SetRole))).Methods("PUT")
+	routerAdmin.Handle("/users/{user_id}/suspend", adminOnly(http.HandlerFunc(srv.SuspendUser))).Methods("POST")
+	routerAdmin.Handle("/users/{user_id}/unsuspend", adminOnly(http.HandlerFunc(srv.UnsuspendUser))).Methods("POST")
 
 	r.NotFoundHandler = http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		http.ServeFile(writer, request, "static/html/index.html")
@@ -70,7 +211,7 @@ func main() {
 }
 
 func mySQLInit(login, password, host, port, dataBase string, logger *log.Logger) *sql.DB {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", login, password, host, port, dataBase)
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", login, password, host, port, dataBase)
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
 		logger.Fatal(err.Error())
@@ -0,0 +1,124 @@
+package automod
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testRules = `{"rules": [
+	{"name": "shorteners", "domains": ["bit.ly"], "action": "remove", "reason": "link shorteners are not allowed"},
+	{"name": "new accounts", "kind": "post", "account_age_under": "24h", "karma_under": 10, "action": "hold"},
+	{"name": "questions", "kind": "post", "title": "(?i)^how (do|to)\\b", "categories": ["programming"], "action": "flair", "flair": "question"},
+	{"name": "welcome", "kind": "post", "karma_under": 1, "action": "reply", "reply": "Welcome!"}
+]}`
+
+func TestEvaluate(t *testing.T) {
+	rules, err := Parse([]byte(testRules))
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	old := Duration{48 * time.Hour}
+	testCases := []struct {
+		name string
+		item Item
+		want Decision
+	}{
+		{
+			name: "nothing",
+			item: Item{Kind: KindPost, Category: "music", Title: "Song", Body: "text", AccountAge: old, Karma: 50},
+			want: Decision{Matched: []string{}},
+		},
+		{
+			name: "shortener in a comment",
+			item: Item{Kind: KindComment, Body: "see https://www.Bit.ly/x", AccountAge: old, Karma: 50},
+			want: Decision{Remove: true, Reason: "link shorteners are not allowed", Matched: []string{"shorteners"}},
+		},
+		{
+			name: "new account removed",
+			item: Item{Kind: KindPost, Body: "http://sub.bit.ly/x", Karma: 5},
+			want: Decision{Remove: true, Reason: "link shorteners are not allowed", Matched: []string{"shorteners", "new accounts"}},
+		},
+		{
+			name: "new account held",
+			item: Item{Kind: KindPost, Category: "programming", Title: "How to exit vim", Body: "help", Karma: 0},
+			want: Decision{Hold: true, Flair: "question", Replies: []string{"Welcome!"}, Reason: "new accounts",
+				Matched: []string{"new accounts", "questions", "welcome"}},
+		},
+		{
+			name: "other domain",
+			item: Item{Kind: KindPost, Category: "programming", Title: "Howto", Body: "https://notbit.ly", AccountAge: old, Karma: 50},
+			want: Decision{Matched: []string{}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if res := rules.Evaluate(tc.item); !reflect.DeepEqual(res, tc.want) {
+				t.Errorf("results not match, want %+v, have %+v", tc.want, res)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := map[string]string{
+		"unknown field": `{"rules": [{"name": "a", "titel": "x", "action": "remove"}]}`,
+		"bad regexp":    `{"rules": [{"name": "a", "title": "(", "action": "remove"}]}`,
+		"bad action":    `{"rules": [{"name": "a", "action": "ban"}]}`,
+		"no flair":      `{"rules": [{"name": "a", "kind": "post", "action": "flair"}]}`,
+		"bad duration":  `{"rules": [{"name": "a", "account_age_under": "a day", "action": "hold"}]}`,
+		"duplicate":     `{"rules": [{"name": "a", "action": "hold"}, {"name": "a", "action": "remove"}]}`,
+	}
+	for name, rules := range testCases {
+		if _, err := Parse([]byte(rules)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestEngineReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	write := func(rules string, modTime time.Time) {
+		if err := os.WriteFile(path, []byte(rules), 0o600); err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+	}
+	start := time.Now().Add(-time.Hour)
+	write(`{"rules": [{"name": "spam", "body": "spam", "action": "remove"}]}`, start)
+	engine, err := NewEngine(path)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	item := Item{Kind: KindComment, Body: "spam and eggs"}
+	if !engine.Evaluate(item).Remove {
+		t.Fatalf("rule not applied")
+	}
+	stop := engine.Watch(10*time.Millisecond, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+	defer stop()
+
+	write(`{"rules": [{"name": "spam", "body": "spam", "action": "hold"}]}`, start.Add(time.Minute))
+	waitFor(t, func() bool { return engine.Evaluate(item).Hold })
+
+	write(`{"rules": [{"name": "spam", "body": "(", "action": "hold"}]}`, start.Add(2*time.Minute))
+	time.Sleep(50 * time.Millisecond)
+	if !engine.Evaluate(item).Hold {
+		t.Errorf("broken file replaced the rules")
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("rules not reloaded")
+}
@@ -0,0 +1,97 @@
+package automod
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Engine evaluates the rules of a file and picks up changes to it, a broken
+// file is logged and the rules loaded before are kept.
+type Engine struct {
+	path    string
+	mux     *sync.RWMutex
+	rules   *Rules
+	modTime time.Time
+}
+
+func NewEngine(path string) (*Engine, error) {
+	e := &Engine{
+		path:  path,
+		mux:   &sync.RWMutex{},
+		rules: &Rules{},
+	}
+	if err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// NewStaticEngine serves fixed rules, it is what tests and the dry run use.
+func NewStaticEngine(rules *Rules) *Engine {
+	return &Engine{mux: &sync.RWMutex{}, rules: rules}
+}
+
+func (e *Engine) Reload() error {
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return err
+	}
+	buf, err := os.ReadFile(e.path)
+	if err != nil {
+		return err
+	}
+	rules, err := Parse(buf)
+	if err != nil {
+		return err
+	}
+	e.mux.Lock()
+	e.rules = rules
+	e.modTime = info.ModTime()
+	e.mux.Unlock()
+	return nil
+}
+
+// Watch checks the file every interval and reloads it when it was modified.
+func (e *Engine) Watch(interval time.Duration, logger *log.Logger) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			info, err := os.Stat(e.path)
+			if err != nil {
+				logger.Printf("automod: %s\n", err)
+				continue
+			}
+			e.mux.RLock()
+			changed := !info.ModTime().Equal(e.modTime)
+			e.mux.RUnlock()
+			if !changed {
+				continue
+			}
+			if err = e.Reload(); err != nil {
+				logger.Printf("automod: keeping the old rules: %s\n", err)
+				e.mux.Lock()
+				e.modTime = info.ModTime()
+				e.mux.Unlock()
+				continue
+			}
+			logger.Printf("automod: reloaded %s\n", e.path)
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+func (e *Engine) Evaluate(item Item) Decision {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+	return e.rules.Evaluate(item)
+}
@@ -0,0 +1,240 @@
+package automod
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	ActionRemove = "remove"
+	ActionHold   = "hold"
+	ActionFlair  = "flair"
+	ActionReply  = "reply"
+
+	KindPost    = "post"
+	KindComment = "comment"
+)
+
+// Duration reads "72h" style strings from the rules and sample files.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(buf []byte) error {
+	var s string
+	if err := json.Unmarshal(buf, &s); err != nil {
+		return errors.New("invalid duration")
+	}
+	res, err := time.ParseDuration(s)
+	if err != nil {
+		return errors.New("invalid duration")
+	}
+	d.Duration = res
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Rule matches when all of its conditions hold, an empty condition matches everything.
+// Kind is "post", "comment" or empty for both.
+type Rule struct {
+	Name            string    `json:"name"`
+	Kind            string    `json:"kind,omitempty"`
+	Title           string    `json:"title,omitempty"`
+	Body            string    `json:"body,omitempty"`
+	Domains         []string  `json:"domains,omitempty"`
+	AccountAgeUnder *Duration `json:"account_age_under,omitempty"`
+	KarmaUnder      *int      `json:"karma_under,omitempty"`
+	Categories      []string  `json:"categories,omitempty"`
+	Action          string    `json:"action"`
+	Flair           string    `json:"flair,omitempty"`
+	Reply           string    `json:"reply,omitempty"`
+	Reason          string    `json:"reason,omitempty"`
+
+	title *regexp.Regexp
+	body  *regexp.Regexp
+}
+
+type Rules struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Item is a new post or comment as the rules see it. Body is the text of a
+// text post, the url of a link post or the comment itself.
+type Item struct {
+	Kind       string   `json:"kind"`
+	Category   string   `json:"category"`
+	Title      string   `json:"title,omitempty"`
+	Body       string   `json:"body"`
+	AccountAge Duration `json:"account_age"`
+	Karma      int      `json:"karma"`
+}
+
+// Decision sums up the actions of all the matched rules. Removal wins over
+// holding, the first matched flair is used and every reply is posted.
+type Decision struct {
+	Remove  bool     `json:"remove,omitempty"`
+	Hold    bool     `json:"hold,omitempty"`
+	Flair   string   `json:"flair,omitempty"`
+	Replies []string `json:"replies,omitempty"`
+	Reason  string   `json:"reason,omitempty"`
+	Matched []string `json:"matched"`
+}
+
+var linkRe = regexp.MustCompile(`https?://[^\s/?#"'<>]+`)
+
+// Parse reads and compiles a rules file, unknown fields are an error so that a
+// typo does not silently turn a condition off.
+func Parse(buf []byte) (*Rules, error) {
+	var rules Rules
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("invalid rules: %w", err)
+	}
+	names := make(map[string]bool, len(rules.Rules))
+	for i := range rules.Rules {
+		el := &rules.Rules[i]
+		if err := el.compile(); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", el.Name, err)
+		}
+		if names[el.Name] {
+			return nil, fmt.Errorf("rule %q: duplicate name", el.Name)
+		}
+		names[el.Name] = true
+	}
+	return &rules, nil
+}
+
+func (r *Rule) compile() error {
+	if r.Name == "" {
+		return errors.New("empty name")
+	}
+	if r.Kind != "" && r.Kind != KindPost && r.Kind != KindComment {
+		return errors.New("invalid kind")
+	}
+	switch r.Action {
+	case ActionRemove, ActionHold:
+	case ActionFlair:
+		if r.Flair == "" || r.Kind != KindPost {
+			return errors.New("flair needs a flair and kind post")
+		}
+	case ActionReply:
+		if r.Reply == "" {
+			return errors.New("reply needs a reply")
+		}
+	default:
+		return errors.New("invalid action")
+	}
+	var err error
+	if r.Title != "" {
+		if r.title, err = regexp.Compile(r.Title); err != nil {
+			return err
+		}
+	}
+	if r.Body != "" {
+		if r.body, err = regexp.Compile(r.Body); err != nil {
+			return err
+		}
+	}
+	for i, el := range r.Domains {
+		r.Domains[i] = strings.TrimPrefix(strings.ToLower(el), "www.")
+	}
+	return nil
+}
+
+func (r *Rule) Matches(item Item) bool {
+	if r.Kind != "" && r.Kind != item.Kind {
+		return false
+	}
+	if len(r.Categories) != 0 && !contains(r.Categories, item.Category) {
+		return false
+	}
+	if r.title != nil && (item.Kind != KindPost || !r.title.MatchString(item.Title)) {
+		return false
+	}
+	if r.body != nil && !r.body.MatchString(item.Body) {
+		return false
+	}
+	if r.AccountAgeUnder != nil && item.AccountAge.Duration >= r.AccountAgeUnder.Duration {
+		return false
+	}
+	if r.KarmaUnder != nil && item.Karma >= *r.KarmaUnder {
+		return false
+	}
+	return len(r.Domains) == 0 || r.linksTo(item.Body)
+}
+
+// linksTo reports whether the text has a link to one of the domains or their subdomains.
+func (r *Rule) linksTo(text string) bool {
+	for _, link := range linkRe.FindAllString(text, -1) {
+		u, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+		host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+		for _, el := range r.Domains {
+			if host == el || strings.HasSuffix(host, "."+el) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (rules *Rules) Evaluate(item Item) Decision {
+	res := Decision{Matched: []string{}}
+	for i := range rules.Rules {
+		el := &rules.Rules[i]
+		if !el.Matches(item) {
+			continue
+		}
+		res.Matched = append(res.Matched, el.Name)
+		switch el.Action {
+		case ActionRemove:
+			if !res.Remove {
+				res.Reason = el.reason()
+			}
+			res.Remove = true
+		case ActionHold:
+			if !res.Remove && !res.Hold {
+				res.Reason = el.reason()
+			}
+			res.Hold = true
+		case ActionFlair:
+			if res.Flair == "" {
+				res.Flair = el.Flair
+			}
+		case ActionReply:
+			res.Replies = append(res.Replies, el.Reply)
+		}
+	}
+	if res.Remove {
+		res.Hold = false
+	}
+	return res
+}
+
+func (r *Rule) reason() string {
+	if r.Reason != "" {
+		return r.Reason
+	}
+	return r.Name
+}
+
+func contains(list []string, s string) bool {
+	for _, el := range list {
+		if el == s {
+			return true
+		}
+	}
+	return false
+}
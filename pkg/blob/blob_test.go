@@ -0,0 +1,49 @@
+package blob
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "/media")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Put("abc.png", []byte("png")); err != nil {
+		t.Fatal(err)
+	}
+	if url := store.URL("abc.png"); url != "/media/abc.png" {
+		t.Errorf("results not match, want %v, have %v", "/media/abc.png", url)
+	}
+	handler := http.StripPrefix("/media/", store.Handler())
+	get := func(path string) (int, string) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		body, _ := io.ReadAll(w.Result().Body)
+		return w.Result().StatusCode, string(body)
+	}
+	if code, body := get("/media/abc.png"); code != 200 || body != "png" {
+		t.Errorf("results not match, want %v, have %v %v", "200 png", code, body)
+	}
+	if code, _ := get("/media/"); code != 404 {
+		t.Errorf("directory listed with %v", code)
+	}
+	for _, key := range []string{"", "../abc.png", ".hidden", "a/b.png"} {
+		if err = store.Put(key, nil); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("results not match, want %v, have %v", ErrInvalidKey, err)
+		}
+	}
+	if err = store.Delete("abc.png"); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Delete("abc.png"); err != nil {
+		t.Errorf("deleting a missing blob: %v", err)
+	}
+	if code, _ := get("/media/abc.png"); code != 404 {
+		t.Errorf("results not match, want %v, have %v", 404, code)
+	}
+}
@@ -0,0 +1,52 @@
+package hasher
+
+import (
+	"errors"
+)
+
+var ErrUnknownFormat = errors.New("unknown password hash format")
+
+// PasswordHasher produces self-describing hashes: the encoded string carries
+// the algorithm and its parameters, so it can be verified without extra state.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify reports whether password matches encoded and whether encoded
+	// should be replaced by a fresh Hash of the same password.
+	Verify(password, encoded string) (ok bool, rehash bool, err error)
+}
+
+type Algorithm interface {
+	PasswordHasher
+	Match(encoded string) bool
+}
+
+var _ PasswordHasher = (*Chain)(nil)
+
+// Chain hashes with the primary algorithm and still accepts hashes made by
+// the legacy ones, asking for a rehash whenever such a hash is verified.
+type Chain struct {
+	primary Algorithm
+	legacy  []Algorithm
+}
+
+func NewChain(primary Algorithm, legacy ...Algorithm) *Chain {
+	return &Chain{primary: primary, legacy: legacy}
+}
+
+func (ch *Chain) Hash(password string) (string, error) {
+	return ch.primary.Hash(password)
+}
+
+func (ch *Chain) Verify(password, encoded string) (bool, bool, error) {
+	if ch.primary.Match(encoded) {
+		return ch.primary.Verify(password, encoded)
+	}
+	for _, alg := range ch.legacy {
+		if !alg.Match(encoded) {
+			continue
+		}
+		ok, _, err := alg.Verify(password, encoded)
+		return ok, ok, err
+	}
+	return false, false, ErrUnknownFormat
+}
@@ -0,0 +1,92 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChain_Verify(t *testing.T) {
+	fast := &Argon2id{Time: 1, Memory: 1024, Threads: 1, KeyLen: 32, SaltLen: 16}
+	chain := NewChain(fast, NewBcrypt(4), NewMD5())
+	argonHash, _ := fast.Hash("12345678")
+	bcryptHash, _ := NewBcrypt(4).Hash("12345678")
+	oldArgonHash, _ := (&Argon2id{Time: 2, Memory: 1024, Threads: 1, KeyLen: 32, SaltLen: 16}).Hash("12345678")
+	testCases := []struct {
+		name       string
+		password   string
+		encoded    string
+		wantOk     bool
+		wantRehash bool
+		wantErr    bool
+	}{
+		{
+			name:     "argon2id",
+			password: "12345678",
+			encoded:  argonHash,
+			wantOk:   true,
+		},
+		{
+			name:     "argon2id wrong password",
+			password: "87654321",
+			encoded:  argonHash,
+		},
+		{
+			name:       "argon2id old parameters",
+			password:   "12345678",
+			encoded:    oldArgonHash,
+			wantOk:     true,
+			wantRehash: true,
+		},
+		{
+			name:       "legacy bcrypt",
+			password:   "12345678",
+			encoded:    bcryptHash,
+			wantOk:     true,
+			wantRehash: true,
+		},
+		{
+			name:       "legacy md5",
+			password:   "12345678",
+			encoded:    "25d55ad283aa400af464c76d713c07ad",
+			wantOk:     true,
+			wantRehash: true,
+		},
+		{
+			name:     "legacy md5 wrong password",
+			password: "87654321",
+			encoded:  "25d55ad283aa400af464c76d713c07ad",
+		},
+		{
+			name:     "unknown format",
+			password: "12345678",
+			encoded:  "plain",
+			wantErr:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ok, rehash, err := chain.Verify(tc.password, tc.encoded)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tc.wantOk || rehash != tc.wantRehash {
+				t.Errorf("results not match, want %v %v, have %v %v", tc.wantOk, tc.wantRehash, ok, rehash)
+			}
+		})
+	}
+}
+
+func TestChain_Hash(t *testing.T) {
+	chain := NewChain(&Argon2id{Time: 1, Memory: 1024, Threads: 1, KeyLen: 32, SaltLen: 16}, NewMD5())
+	first, err := chain.Hash("12345678")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _ := chain.Hash("12345678")
+	if !strings.HasPrefix(first, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("unexpected encoding %s", first)
+	}
+	if first == second {
+		t.Errorf("hashes are not salted")
+	}
+}
@@ -0,0 +1,35 @@
+package hasher
+
+import (
+	"crypto/md5"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+var _ Algorithm = (*MD5)(nil)
+
+// MD5 understands the unsalted hex digests stored before password hashing
+// became pluggable. It is meant to be used only as a legacy algorithm of a Chain.
+type MD5 struct{}
+
+func NewMD5() *MD5 {
+	return &MD5{}
+}
+
+func (m *MD5) Match(encoded string) bool {
+	if len(encoded) != hex.EncodedLen(md5.Size) {
+		return false
+	}
+	_, err := hex.DecodeString(encoded)
+	return err == nil
+}
+
+func (m *MD5) Hash(password string) (string, error) {
+	sum := md5.Sum([]byte(password))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (m *MD5) Verify(password, encoded string) (bool, bool, error) {
+	hash, _ := m.Hash(password)
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(encoded)) == 1, false, nil
+}
@@ -0,0 +1,373 @@
+// Package imaging checks uploaded images and prepares them for serving.
+//
+// An upload is accepted by its content, not by its name or the declared
+// content type: JPEG, PNG and GIF are recognised by their magic bytes. Every
+// image is decoded and encoded again, which drops EXIF, XMP, text chunks and
+// anything else riding along with the pixels. The EXIF orientation of a JPEG
+// is applied to the pixels before it is lost.
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	// MaxUploadSize is the largest accepted upload in bytes.
+	MaxUploadSize = 10 << 20
+	// MaxPixels bounds width*height, a small file can declare a huge image.
+	MaxPixels = 24_000_000
+	// ThumbnailSize is the largest side of a thumbnail.
+	ThumbnailSize = 320
+
+	maxGIFPixels = 4 * MaxPixels
+	maxGIFFrames = 1000
+	jpegQuality  = 90
+)
+
+var (
+	ErrEmpty       = errors.New("empty image")
+	ErrTooLarge    = errors.New("image is too large")
+	ErrUnsupported = errors.New("unsupported image type")
+	ErrDimensions  = errors.New("image dimensions are too large")
+	ErrInvalid     = errors.New("invalid image")
+)
+
+// Encoded is an image ready to be stored.
+type Encoded struct {
+	Data        []byte
+	ContentType string
+	Ext         string
+}
+
+type Image struct {
+	Original  Encoded
+	Thumbnail Encoded
+	Width     int
+	Height    int
+}
+
+func Process(data []byte) (Image, error) {
+	if len(data) == 0 {
+		return Image{}, ErrEmpty
+	}
+	if len(data) > MaxUploadSize {
+		return Image{}, ErrTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || "image/"+format != contentType {
+		if contentType != "image/jpeg" && contentType != "image/png" && contentType != "image/gif" {
+			return Image{}, ErrUnsupported
+		}
+		return Image{}, ErrInvalid
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return Image{}, ErrInvalid
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+		return Image{}, ErrDimensions
+	}
+	switch format {
+	case "jpeg":
+		return processJPEG(data)
+	case "png":
+		return processPNG(data)
+	case "gif":
+		return processGIF(data)
+	}
+	return Image{}, ErrUnsupported
+}
+
+func processJPEG(data []byte) (Image, error) {
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, ErrInvalid
+	}
+	pixels := orient(toRGBA(img), jpegOrientation(data))
+	original, err := encodeJPEG(pixels)
+	if err != nil {
+		return Image{}, err
+	}
+	thumb, err := encodeJPEG(thumbnail(pixels, ThumbnailSize))
+	if err != nil {
+		return Image{}, err
+	}
+	return newImage(original, thumb, pixels.Bounds()), nil
+}
+
+func processPNG(data []byte) (Image, error) {
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, ErrInvalid
+	}
+	original, err := encodePNG(img)
+	if err != nil {
+		return Image{}, err
+	}
+	thumb, err := encodePNG(thumbnail(toRGBA(img), ThumbnailSize))
+	if err != nil {
+		return Image{}, err
+	}
+	return newImage(original, thumb, img.Bounds()), nil
+}
+
+// processGIF keeps the animation, the thumbnail is the first frame. The frames
+// are counted before decoding, every one of them is decoded into memory.
+func processGIF(data []byte) (Image, error) {
+	frames, pixels, err := gifFrames(data)
+	if err != nil {
+		return Image{}, err
+	}
+	if frames > maxGIFFrames || pixels > maxGIFPixels {
+		return Image{}, ErrDimensions
+	}
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil || len(g.Image) == 0 {
+		return Image{}, ErrInvalid
+	}
+	canvas := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	var buf bytes.Buffer
+	clean := &gif.GIF{Image: g.Image, Delay: g.Delay, LoopCount: g.LoopCount, Disposal: g.Disposal,
+		Config: g.Config, BackgroundIndex: g.BackgroundIndex}
+	if err = gif.EncodeAll(&buf, clean); err != nil {
+		return Image{}, err
+	}
+	first := image.NewRGBA(canvas)
+	draw.Draw(first, g.Image[0].Bounds(), g.Image[0], g.Image[0].Bounds().Min, draw.Over)
+	thumb, err := encodePNG(thumbnail(first, ThumbnailSize))
+	if err != nil {
+		return Image{}, err
+	}
+	original := Encoded{Data: buf.Bytes(), ContentType: "image/gif", Ext: ".gif"}
+	return newImage(original, thumb, canvas), nil
+}
+
+func newImage(original, thumb Encoded, bounds image.Rectangle) Image {
+	return Image{Original: original, Thumbnail: thumb, Width: bounds.Dx(), Height: bounds.Dy()}
+}
+
+func encodeJPEG(img image.Image) (Encoded, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return Encoded{}, err
+	}
+	return Encoded{Data: buf.Bytes(), ContentType: "image/jpeg", Ext: ".jpg"}, nil
+}
+
+func encodePNG(img image.Image) (Encoded, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return Encoded{}, err
+	}
+	return Encoded{Data: buf.Bytes(), ContentType: "image/png", Ext: ".png"}, nil
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	res := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(res, res.Bounds(), img, b.Min, draw.Src)
+	return res
+}
+
+// thumbnail scales the image down to fit a size by size box, averaging the
+// pixels that fall into each pixel of the result. Small images keep their size.
+func thumbnail(src *image.RGBA, size int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, h*size/w
+		} else {
+			tw, th = w*size/h, size
+		}
+	}
+	if tw < 1 {
+		tw = 1
+	}
+	if th < 1 {
+		th = 1
+	}
+	sums := make([]uint64, tw*th*4)
+	counts := make([]uint64, tw*th)
+	for y := 0; y < h; y++ {
+		row := src.Pix[y*src.Stride:]
+		ty := y * th / h
+		for x := 0; x < w; x++ {
+			i := ty*tw + x*tw/w
+			for c := 0; c < 4; c++ {
+				sums[i*4+c] += uint64(row[x*4+c])
+			}
+			counts[i]++
+		}
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for i, n := range counts {
+		if n == 0 {
+			continue
+		}
+		for c := 0; c < 4; c++ {
+			dst.Pix[i*4+c] = uint8(sums[i*4+c] / n)
+		}
+	}
+	return dst
+}
+
+// orient turns the pixels the way the EXIF orientation tag says they are meant to be seen.
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[y*dst.Stride+x*4:y*dst.Stride+x*4+4], src.Pix[sy*src.Stride+sx*4:sy*src.Stride+sx*4+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the orientation tag from the EXIF segment of a JPEG, 1 when there is none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+size]
+		if marker == 0xE1 && len(segment) >= 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[offset:]))
+	for k := 0; k < entries; k++ {
+		entry := offset + 2 + k*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if v := int(order.Uint16(tiff[entry+8:])); v >= 1 && v <= 8 {
+				return v
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// gifFrames walks the blocks of a GIF without decoding the pixels and returns
+// the number of frames and the sum of their areas.
+func gifFrames(data []byte) (int, int64, error) {
+	if len(data) < 13 {
+		return 0, 0, ErrInvalid
+	}
+	i := 13
+	if data[10]&0x80 != 0 {
+		i += 3 << (data[10]&7 + 1)
+	}
+	skipBlocks := func() bool {
+		for i < len(data) {
+			size := int(data[i])
+			i += 1 + size
+			if size == 0 {
+				return true
+			}
+		}
+		return false
+	}
+	frames, pixels := 0, int64(0)
+	for i < len(data) {
+		switch data[i] {
+		case 0x21:
+			i += 2
+			if !skipBlocks() {
+				return 0, 0, ErrInvalid
+			}
+		case 0x2C:
+			if i+10 > len(data) {
+				return 0, 0, ErrInvalid
+			}
+			w := int64(binary.LittleEndian.Uint16(data[i+5:]))
+			h := int64(binary.LittleEndian.Uint16(data[i+7:]))
+			flags := data[i+9]
+			i += 10
+			if flags&0x80 != 0 {
+				i += 3 << (flags&7 + 1)
+			}
+			i++
+			if !skipBlocks() {
+				return 0, 0, ErrInvalid
+			}
+			frames++
+			pixels += w * h
+		case 0x3B:
+			if frames == 0 {
+				return 0, 0, ErrInvalid
+			}
+			return frames, pixels, nil
+		default:
+			return 0, 0, ErrInvalid
+		}
+	}
+	return 0, 0, ErrInvalid
+}
@@ -0,0 +1,174 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	return img
+}
+
+// withExif puts an APP1 segment with the orientation tag right after the SOI marker.
+func withExif(data []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x00\x00\x00\x00\x00\x00\x00")
+	binary.BigEndian.PutUint16(tiff[18:], orientation)
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+	res := append([]byte{}, data[:2]...)
+	res = append(res, app1...)
+	res = append(res, segment...)
+	return append(res, data[2:]...)
+}
+
+func TestProcess_JPEG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(800, 400), nil); err != nil {
+		t.Fatal(err)
+	}
+	data := withExif(buf.Bytes(), 6)
+	if o := jpegOrientation(data); o != 6 {
+		t.Fatalf("results not match, want %v, have %v", 6, o)
+	}
+	img, err := Process(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Width != 400 || img.Height != 800 {
+		t.Errorf("results not match, want %v, have %v", "400x800", []int{img.Width, img.Height})
+	}
+	if img.Original.ContentType != "image/jpeg" || img.Original.Ext != ".jpg" {
+		t.Errorf("unexpected type %v %v", img.Original.ContentType, img.Original.Ext)
+	}
+	if bytes.Contains(img.Original.Data, []byte("Exif")) {
+		t.Errorf("exif is not stripped")
+	}
+	if o := jpegOrientation(img.Original.Data); o != 1 {
+		t.Errorf("results not match, want %v, have %v", 1, o)
+	}
+	thumb, err := jpeg.DecodeConfig(bytes.NewReader(img.Thumbnail.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thumb.Width != 160 || thumb.Height != ThumbnailSize {
+		t.Errorf("results not match, want %v, have %v", "160x320", []int{thumb.Width, thumb.Height})
+	}
+}
+
+func TestProcess_PNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(100, 50)); err != nil {
+		t.Fatal(err)
+	}
+	img, err := Process(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Thumbnail.ContentType != "image/png" || img.Width != 100 || img.Height != 50 {
+		t.Errorf("unexpected image %v %vx%v", img.Thumbnail.ContentType, img.Width, img.Height)
+	}
+	thumb, err := png.Decode(bytes.NewReader(img.Thumbnail.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thumb.Bounds().Dx() != 100 || thumb.Bounds().Dy() != 50 {
+		t.Errorf("small image was resized to %v", thumb.Bounds())
+	}
+	if r, g, _, _ := thumb.At(10, 20).RGBA(); r>>8 != 10 || g>>8 != 20 {
+		t.Errorf("pixels changed")
+	}
+}
+
+func TestProcess_Invalid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(1, 1)); err != nil {
+		t.Fatal(err)
+	}
+	huge := append([]byte{}, buf.Bytes()...)
+	binary.BigEndian.PutUint32(huge[16:], 100000)
+	binary.BigEndian.PutUint32(huge[20:], 100000)
+	binary.BigEndian.PutUint32(huge[29:], crc32.ChecksumIEEE(huge[12:29]))
+
+	testingTable := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{name: "empty", data: nil, err: ErrEmpty},
+		{name: "too large", data: make([]byte, MaxUploadSize+1), err: ErrTooLarge},
+		{name: "text", data: []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"), err: ErrUnsupported},
+		{name: "dimensions", data: huge, err: ErrDimensions},
+		{name: "broken", data: buf.Bytes()[:40], err: ErrInvalid},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, err := Process(testCase.data); !errors.Is(err, testCase.err) {
+				t.Errorf("results not match, want %v, have %v", testCase.err, err)
+			}
+		})
+	}
+}
+
+// bombGIF declares frames of the full size whose pixel data is a bare clear
+// and end code, decoding them all would take gigabytes.
+func bombGIF(frames int) []byte {
+	res := []byte("GIF89a\xd0\x07\xd0\x07\x80\x00\x00\x00\x00\x00\xff\xff\xff")
+	for i := 0; i < frames; i++ {
+		res = append(res, "\x2c\x00\x00\x00\x00\xd0\x07\xd0\x07\x00\x02\x01\x2c\x00"...)
+	}
+	return append(res, 0x3B)
+}
+
+func TestProcess_GIF(t *testing.T) {
+	anim := &gif.GIF{}
+	for i := 0; i < 3; i++ {
+		frame := image.NewPaletted(image.Rect(0, 0, 40, 20), palette.Plan9)
+		frame.SetColorIndex(i, i, uint8(i+1))
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, 10)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatal(err)
+	}
+	img, err := Process(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := gif.DecodeAll(bytes.NewReader(img.Original.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Image) != 3 || img.Width != 40 || img.Height != 20 {
+		t.Errorf("unexpected gif %v frames %vx%v", len(res.Image), img.Width, img.Height)
+	}
+	if img.Thumbnail.ContentType != "image/png" {
+		t.Errorf("results not match, want %v, have %v", "image/png", img.Thumbnail.ContentType)
+	}
+
+	if frames, pixels, err := gifFrames(bombGIF(2)); err != nil || frames != 2 || pixels != 2*2000*2000 {
+		t.Errorf("results not match, want %v, have %v %v %v", "2 frames", frames, pixels, err)
+	}
+	if _, err = Process(bombGIF(maxGIFPixels/(2000*2000) + 1)); !errors.Is(err, ErrDimensions) {
+		t.Errorf("results not match, want %v, have %v", ErrDimensions, err)
+	}
+	if _, err = Process(bombGIF(2)[:40]); !errors.Is(err, ErrInvalid) {
+		t.Errorf("results not match, want %v, have %v", ErrInvalid, err)
+	}
+}
@@ -0,0 +1,164 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/dgrijalva/jwt-go"
+	"testing"
+	"time"
+)
+
+func claims() jwt.StandardClaims {
+	return jwt.StandardClaims{Subject: "1", ExpiresAt: time.Now().Add(time.Hour).Unix()}
+}
+
+func TestKeyRing_SignVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("cant generate rsa key: %s", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("cant generate ed25519 key: %s", err)
+	}
+	testCases := []struct {
+		name string
+		key  *Key
+	}{
+		{
+			name: "HS256",
+			key:  NewHMACKey("hs", []byte("0123456789abcdef0123456789abcdef"), StatusActive),
+		},
+		{
+			name: "RS256",
+			key:  NewRSAKey("rs", rsaKey, nil, StatusActive),
+		},
+		{
+			name: "EdDSA",
+			key:  NewEdDSAKey("ed", edKey, nil, StatusActive),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ring, err := New(tc.key)
+			if err != nil {
+				t.Fatalf("cant create key ring: %s", err)
+			}
+			token, err := ring.Sign(claims())
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			parsed, err := jwt.ParseWithClaims(token, &jwt.StandardClaims{}, ring.Keyfunc)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if parsed.Header["kid"] != tc.key.ID || parsed.Header["alg"] != tc.name {
+				t.Errorf("unexpected header %v", parsed.Header)
+			}
+		})
+	}
+}
+
+func TestKeyRing_Rotation(t *testing.T) {
+	first := NewHMACKey("first", []byte("0123456789abcdef0123456789abcdef"), StatusActive)
+	oldRing, _ := New(first)
+	token, _ := oldRing.Sign(claims())
+
+	first.Status = StatusVerify
+	second := NewHMACKey("second", []byte("fedcba9876543210fedcba9876543210"), StatusActive)
+	ring, err := New(first, second)
+	if err != nil {
+		t.Fatalf("cant create key ring: %s", err)
+	}
+	if _, err = jwt.Parse(token, ring.Keyfunc); err != nil {
+		t.Errorf("token of previous key rejected: %s", err)
+	}
+	fresh, _ := ring.Sign(claims())
+	parsed, _ := jwt.Parse(fresh, ring.Keyfunc)
+	if parsed.Header["kid"] != "second" {
+		t.Errorf("signed with %v instead of active key", parsed.Header["kid"])
+	}
+
+	first.Status = StatusRetired
+	ring, _ = New(first, second)
+	if _, err = jwt.Parse(token, ring.Keyfunc); err == nil {
+		t.Errorf("token of retired key accepted")
+	}
+}
+
+func TestKeyRing_AlgorithmConfusion(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ring, _ := New(NewRSAKey("rs", rsaKey, nil, StatusActive))
+	public := x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+	token.Header["kid"] = "rs"
+	forged, _ := token.SignedString(public)
+	if _, err := jwt.Parse(forged, ring.Keyfunc); err == nil {
+		t.Errorf("HS256 token accepted by RS256 key")
+	}
+}
+
+func TestParse(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	privateDER, _ := x509.MarshalPKCS8PrivateKey(private)
+	publicDER, _ := x509.MarshalPKIXPublicKey(public)
+	conf, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kid":         "ed",
+				"alg":         "EdDSA",
+				"status":      "active",
+				"private_key": string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+			},
+			{
+				"kid":        "partner",
+				"alg":        "EdDSA",
+				"status":     "verify",
+				"public_key": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+			},
+			{
+				"kid":    "hs",
+				"alg":    "HS256",
+				"status": "retired",
+				"secret": "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+			},
+		},
+	})
+	ring, err := Parse(conf)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ring.Active().ID != "ed" {
+		t.Errorf("results not match, want ed, have %s", ring.Active().ID)
+	}
+	set := ring.PublicJWKS()
+	if len(set.Keys) != 2 || set.Keys[0].Kty != "OKP" || set.Keys[0].Crv != "Ed25519" {
+		t.Errorf("unexpected key set %v", set)
+	}
+
+	invalid := []string{
+		`{"keys": []}`,
+		`{"keys": [{"kid": "a", "alg": "HS256", "status": "active", "secret": "c2hvcnQ="}]}`,
+		`{"keys": [{"kid": "a", "alg": "none", "status": "active"}]}`,
+	}
+	publicOnly, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kid":        "partner",
+				"alg":        "EdDSA",
+				"status":     "active",
+				"public_key": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+			},
+		},
+	})
+	invalid = append(invalid, string(publicOnly))
+	for _, el := range invalid {
+		if _, err = Parse([]byte(el)); err == nil {
+			t.Errorf("config accepted: %s", el)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"github.com/dgrijalva/jwt-go"
+)
+
+func NewHMACKey(id string, secret []byte, status Status) *Key {
+	return &Key{ID: id, Status: status, method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}
+}
+
+// NewRSAKey accepts a nil private key for keys that only verify.
+func NewRSAKey(id string, private *rsa.PrivateKey, public *rsa.PublicKey, status Status) *Key {
+	key := &Key{ID: id, Status: status, method: jwt.SigningMethodRS256, verifyKey: public}
+	if private != nil {
+		key.signKey = private
+		if public == nil {
+			key.verifyKey = &private.PublicKey
+		}
+	}
+	return key
+}
+
+// NewEdDSAKey accepts a nil private key for keys that only verify.
+func NewEdDSAKey(id string, private ed25519.PrivateKey, public ed25519.PublicKey, status Status) *Key {
+	key := &Key{ID: id, Status: status, method: SigningMethodEdDSA, verifyKey: public}
+	if private != nil {
+		key.signKey = private
+		if public == nil {
+			key.verifyKey = private.Public().(ed25519.PublicKey)
+		}
+	}
+	return key
+}
+
+// NewEphemeral builds a ring with a random HS256 key. Tokens signed with it
+// do not survive a restart, so it only suits local development.
+func NewEphemeral() (*KeyRing, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return New(NewHMACKey("ephemeral", secret, StatusActive))
+}
@@ -0,0 +1,129 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+type keyConfig struct {
+	ID         string `json:"kid"`
+	Alg        string `json:"alg"`
+	Status     Status `json:"status"`
+	Secret     string `json:"secret"`
+	PrivateKey string `json:"private_key"`
+	PublicKey  string `json:"public_key"`
+}
+
+// LoadEnv reads the key set from the file named by JWT_KEYS_FILE or, when it
+// is not set, from the JSON in JWT_KEYS.
+func LoadEnv() (*KeyRing, error) {
+	if path := os.Getenv("JWT_KEYS_FILE"); path != "" {
+		return LoadFile(path)
+	}
+	if data := os.Getenv("JWT_KEYS"); data != "" {
+		return Parse([]byte(data))
+	}
+	return nil, ErrNoKeys
+}
+
+func LoadFile(path string) (*KeyRing, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) (*KeyRing, error) {
+	conf := struct {
+		Keys []keyConfig `json:"keys"`
+	}{}
+	if err := json.Unmarshal(data, &conf); err != nil {
+		return nil, err
+	}
+	keys := make([]*Key, 0, len(conf.Keys))
+	for _, el := range conf.Keys {
+		key, err := el.build()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", el.ID, err)
+		}
+		keys = append(keys, key)
+	}
+	return New(keys...)
+}
+
+func (conf keyConfig) build() (*Key, error) {
+	switch conf.Alg {
+	case "HS256":
+		secret, err := base64.StdEncoding.DecodeString(conf.Secret)
+		if err != nil {
+			return nil, err
+		}
+		if len(secret) < 32 {
+			return nil, errors.New("HS256 secret must be at least 32 bytes")
+		}
+		return NewHMACKey(conf.ID, secret, conf.Status), nil
+	case "RS256":
+		private, public, err := conf.parsePEM()
+		if err != nil {
+			return nil, err
+		}
+		rsaPrivate, _ := private.(*rsa.PrivateKey)
+		rsaPublic, _ := public.(*rsa.PublicKey)
+		if (private != nil && rsaPrivate == nil) || (public != nil && rsaPublic == nil) {
+			return nil, errors.New("not an RSA key")
+		}
+		return NewRSAKey(conf.ID, rsaPrivate, rsaPublic, conf.Status), nil
+	case "EdDSA":
+		private, public, err := conf.parsePEM()
+		if err != nil {
+			return nil, err
+		}
+		edPrivate, _ := private.(ed25519.PrivateKey)
+		edPublic, _ := public.(ed25519.PublicKey)
+		if (private != nil && edPrivate == nil) || (public != nil && edPublic == nil) {
+			return nil, errors.New("not an Ed25519 key")
+		}
+		return NewEdDSAKey(conf.ID, edPrivate, edPublic, conf.Status), nil
+	}
+	return nil, fmt.Errorf("unsupported alg %q", conf.Alg)
+}
+
+func (conf keyConfig) parsePEM() (interface{}, interface{}, error) {
+	var private, public interface{}
+	if conf.PrivateKey != "" {
+		block, _ := pem.Decode([]byte(conf.PrivateKey))
+		if block == nil {
+			return nil, nil, errors.New("invalid private key PEM")
+		}
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			if key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+				return nil, nil, err
+			}
+		}
+		private = key
+	}
+	if conf.PublicKey != "" {
+		block, _ := pem.Decode([]byte(conf.PublicKey))
+		if block == nil {
+			return nil, nil, errors.New("invalid public key PEM")
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		public = key
+	}
+	if private == nil && public == nil {
+		return nil, nil, errors.New("no key material")
+	}
+	return private, public, nil
+}
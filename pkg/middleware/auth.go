@@ -1,7 +1,9 @@
 package middleware
 
 import (
-	"context"
+	"errors"
+	"github.com/dgrijalva/jwt-go"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
 	"net/http"
 	"strings"
@@ -9,22 +11,60 @@ import (
 
 func (mid *Middleware) Auth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
-		list := strings.Split(request.Header.Get("Authorization"), " ")
-		if len(list) != 2 {
-			utils.NewRespError(writer, "not enough arg in token", 400, mid.logger)
-			return
-		}
-		header := list[1]
-		if header == "" {
-			utils.NewRespError(writer, "empty token", 400, mid.logger)
-			return
-		}
-		id, err := mid.session.Check(header)
+		claims, err := mid.authenticate(request)
 		if err != nil {
-			utils.NewRespError(writer, err.Error(), 400, mid.logger)
+			utils.NewRespError(writer, err.Error(), http.StatusUnauthorized, mid.logger)
 			return
 		}
-		ctx := context.WithValue(request.Context(), "id", id)
+		ctx := session.ContextWithClaims(request.Context(), claims)
 		next.ServeHTTP(writer, request.WithContext(ctx))
 	})
 }
+
+// OptionalAuth is Auth for public routes: a request without a valid token is
+// served as an anonymous one instead of being rejected.
+func (mid *Middleware) OptionalAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("Authorization") != "" {
+			if claims, err := mid.authenticate(request); err == nil {
+				request = request.WithContext(session.ContextWithClaims(request.Context(), claims))
+			}
+		}
+		next.ServeHTTP(writer, request)
+	})
+}
+
+func (mid *Middleware) authenticate(request *http.Request) (*session.Claims, error) {
+	list := strings.Split(request.Header.Get("Authorization"), " ")
+	if len(list) != 2 {
+		return nil, errors.New("not enough arg in token")
+	}
+	if !strings.EqualFold(list[0], "Bearer") {
+		return nil, errors.New("invalid auth scheme")
+	}
+	header := list[1]
+	if header == "" {
+		return nil, errors.New("empty token")
+	}
+	claims, err := mid.parseToken(header)
+	if err != nil {
+		return nil, errors.New("invalid token")
+	}
+	id, err := mid.session.Check(header)
+	if err != nil || id != claims.User.ID {
+		return nil, errors.New("invalid session")
+	}
+	return claims, nil
+}
+
+func (mid *Middleware) parseToken(token string) (*session.Claims, error) {
+	claims := &session.Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, mid.keys.Keyfunc)
+	if err != nil {
+		return nil, err
+	}
+	if claims.User.ID == "" || claims.ExpiresAt == 0 {
+		return nil, errors.New("missing claims")
+	}
+	return claims, nil
+}
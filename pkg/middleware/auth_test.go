@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/dgrijalva/jwt-go"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/keyring"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+type sessionStub map[string]string
+
+func (s sessionStub) Check(token string) (string, error) {
+	id, ok := s[token]
+	if !ok {
+		return "", errors.New("invalid token")
+	}
+	return id, nil
+}
+
+func (s sessionStub) Create(ses *session.Session) error {
+	return nil
+}
+
+func (s sessionStub) Rotate(sessionID, token string, expires time.Time) error {
+	return nil
+}
+
+func (s sessionStub) CreateRefresh(token *session.RefreshToken) error {
+	return nil
+}
+
+func (s sessionStub) UseRefresh(token string) (session.RefreshToken, error) {
+	return session.RefreshToken{}, session.ErrInvalidRefresh
+}
+
+func (s sessionStub) Delete(sessionID, userID string) error {
+	return nil
+}
+
+func (s sessionStub) DeleteAllForUser(userID string) error {
+	return nil
+}
+
+func (s sessionStub) ListForUser(userID string) ([]session.Session, error) {
+	return nil, nil
+}
+
+func signToken(key []byte, kid string, method jwt.SigningMethod, userID string, exp time.Time) string {
+	token := jwt.NewWithClaims(method, session.Claims{
+		User: session.User{ID: userID, Login: "test"},
+		StandardClaims: jwt.StandardClaims{
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: exp.Unix(),
+		},
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	res, _ := token.SignedString(key)
+	return res
+}
+
+func TestMiddleware_Auth(t *testing.T) {
+	key, oldKey, goneKey := []byte("key"), []byte("old key"), []byte("gone key")
+	keys, err := keyring.New(
+		keyring.NewHMACKey("new", key, keyring.StatusActive),
+		keyring.NewHMACKey("old", oldKey, keyring.StatusVerify),
+		keyring.NewHMACKey("gone", goneKey, keyring.StatusRetired),
+	)
+	if err != nil {
+		t.Fatalf("cant create key ring: %s", err)
+	}
+	valid := signToken(key, "new", jwt.SigningMethodHS256, "1", time.Now().Add(time.Hour))
+	rotated := signToken(oldKey, "old", jwt.SigningMethodHS256, "1", time.Now().Add(time.Hour))
+	retired := signToken(goneKey, "gone", jwt.SigningMethodHS256, "1", time.Now().Add(time.Hour))
+	noKid := signToken(key, "", jwt.SigningMethodHS256, "1", time.Now().Add(time.Hour))
+	expired := signToken(key, "new", jwt.SigningMethodHS256, "1", time.Now().Add(-time.Hour))
+	tampered := signToken([]byte("other key"), "new", jwt.SigningMethodHS256, "1", time.Now().Add(time.Hour))
+	otherAlg := signToken(key, "new", jwt.SigningMethodHS512, "1", time.Now().Add(time.Hour))
+	foreign := signToken(key, "new", jwt.SigningMethodHS256, "2", time.Now().Add(time.Hour))
+	sessions := sessionStub{valid: "1", rotated: "1", retired: "1", noKid: "1", expired: "1", tampered: "1",
+		otherAlg: "1", foreign: "1"}
+	testCases := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantUserID string
+	}{
+		{
+			name:       "ok",
+			header:     "Bearer " + valid,
+			wantStatus: http.StatusOK,
+			wantUserID: "1",
+		},
+		{
+			name:       "signed with previous key",
+			header:     "Bearer " + rotated,
+			wantStatus: http.StatusOK,
+			wantUserID: "1",
+		},
+		{
+			name:       "signed with retired key",
+			header:     "Bearer " + retired,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "no kid",
+			header:     "Bearer " + noKid,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "no header",
+			header:     "",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid scheme",
+			header:     "Basic " + valid,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "expired",
+			header:     "Bearer " + expired,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "tampered",
+			header:     "Bearer " + tampered,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "unexpected algorithm",
+			header:     "Bearer " + otherAlg,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "session of another user",
+			header:     "Bearer " + foreign,
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mid := NewMiddleware(keys, sessions, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+			var gotUserID string
+			handler := mid.Auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				claims, ok := session.ClaimsFromContext(r.Context())
+				if ok {
+					gotUserID = claims.User.ID
+				}
+			}))
+			r := httptest.NewRequest("GET", "/api/posts", nil)
+			r.Header.Set("Authorization", tc.header)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tc.wantStatus {
+				t.Errorf("results not match, want %v, have %v", tc.wantStatus, w.Code)
+			}
+			if gotUserID != tc.wantUserID {
+				t.Errorf("results not match, want %v, have %v", tc.wantUserID, gotUserID)
+			}
+		})
+	}
+}
+
+func TestMiddleware_OptionalAuth(t *testing.T) {
+	key := []byte("key")
+	keys, err := keyring.New(keyring.NewHMACKey("new", key, keyring.StatusActive))
+	if err != nil {
+		t.Fatalf("cant create key ring: %s", err)
+	}
+	valid := signToken(key, "new", jwt.SigningMethodHS256, "1", time.Now().Add(time.Hour))
+	expired := signToken(key, "new", jwt.SigningMethodHS256, "1", time.Now().Add(-time.Hour))
+	sessions := sessionStub{valid: "1", expired: "1"}
+	testCases := []struct {
+		name       string
+		header     string
+		wantUserID string
+	}{
+		{name: "ok", header: "Bearer " + valid, wantUserID: "1"},
+		{name: "anonymous", header: ""},
+		{name: "expired", header: "Bearer " + expired},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mid := NewMiddleware(keys, sessions, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+			var gotUserID string
+			handler := mid.OptionalAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				claims, ok := session.ClaimsFromContext(r.Context())
+				if ok {
+					gotUserID = claims.User.ID
+				}
+			}))
+			r := httptest.NewRequest("GET", "/api/posts/", nil)
+			r.Header.Set("Authorization", tc.header)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != http.StatusOK {
+				t.Errorf("results not match, want %v, have %v", http.StatusOK, w.Code)
+			}
+			if gotUserID != tc.wantUserID {
+				t.Errorf("results not match, want %v, have %v", tc.wantUserID, gotUserID)
+			}
+		})
+	}
+}
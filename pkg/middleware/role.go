@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"net/http"
+)
+
+// RequireRole lets through users whose role grants at least the given one,
+// it has to run after Auth.
+func (mid *Middleware) RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			claims, ok := session.ClaimsFromContext(request.Context())
+			if !ok {
+				utils.NewRespError(writer, "invalid user id", http.StatusUnauthorized, mid.logger)
+				return
+			}
+			if !userdata.HasRole(claims.User.Role, role) {
+				utils.NewRespError(writer, "forbidden", http.StatusForbidden, mid.logger)
+				return
+			}
+			next.ServeHTTP(writer, request)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestMiddleware_RequireRole(t *testing.T) {
+	testCases := []struct {
+		name       string
+		claims     *session.Claims
+		required   string
+		wantStatus int
+	}{
+		{
+			name:       "admin",
+			claims:     &session.Claims{User: session.User{ID: "1", Role: userdata.RoleAdmin}},
+			required:   userdata.RoleAdmin,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "admin is a moderator too",
+			claims:     &session.Claims{User: session.User{ID: "1", Role: userdata.RoleAdmin}},
+			required:   userdata.RoleModerator,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "moderator",
+			claims:     &session.Claims{User: session.User{ID: "1", Role: userdata.RoleModerator}},
+			required:   userdata.RoleAdmin,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "token without role",
+			claims:     &session.Claims{User: session.User{ID: "1"}},
+			required:   userdata.RoleModerator,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "unknown role",
+			claims:     &session.Claims{User: session.User{ID: "1", Role: "root"}},
+			required:   userdata.RoleUser,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "no claims",
+			required:   userdata.RoleUser,
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mid := NewMiddleware(nil, sessionStub{}, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+			handler := mid.RequireRole(tc.required)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			r := httptest.NewRequest("GET", "/api/admin/users", nil)
+			if tc.claims != nil {
+				r = r.WithContext(session.ContextWithClaims(r.Context(), tc.claims))
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tc.wantStatus {
+				t.Errorf("results not match, want %v, have %v", tc.wantStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package ranking
+
+import (
+	"math"
+	"time"
+)
+
+// z is the quantile of the standard normal distribution for 95% confidence.
+const z = 1.96
+
+// Wilson returns the lower bound of the Wilson score interval for the share of
+// upvotes, so a few votes rank below many votes with the same ratio.
+func Wilson(ups, downs int) float64 {
+	n := float64(ups + downs)
+	if n == 0 {
+		return 0
+	}
+	p := float64(ups) / n
+	return (p + z*z/(2*n) - z*math.Sqrt((p*(1-p)+z*z/(4*n))/n)) / (1 + z*z/n)
+}
+
+// Controversy is high for items with many votes split evenly between up and down.
+func Controversy(ups, downs int) float64 {
+	if ups <= 0 || downs <= 0 {
+		return 0
+	}
+	balance := float64(downs) / float64(ups)
+	if ups < downs {
+		balance = float64(ups) / float64(downs)
+	}
+	return math.Pow(float64(ups+downs), balance)
+}
+
+// epoch is the reference point of the hot ranking, same as on Reddit.
+const epoch = 1134028003
+
+// Hot ranks by the order of magnitude of the score and adds the creation time,
+// so every 12.5 hours a post needs ten times the score to stay on the same place.
+func Hot(score int, created time.Time) float64 {
+	order := math.Log10(math.Max(math.Abs(float64(score)), 1))
+	sign := 0.0
+	if score > 0 {
+		sign = 1
+	} else if score < 0 {
+		sign = -1
+	}
+	return sign*order + float64(created.Unix()-epoch)/45000
+}
+
+// Rising is the score gained per hour of age with a gravity, it favours young
+// posts that are collecting votes fast.
+func Rising(score int, age time.Duration) float64 {
+	return float64(score) / math.Pow(age.Hours()+2, 1.5)
+}
@@ -0,0 +1,64 @@
+package ranking
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWilson(t *testing.T) {
+	testCases := []struct {
+		name       string
+		ups, downs int
+		want       float64
+	}{
+		{name: "no votes", want: 0},
+		{name: "single upvote", ups: 1, want: 0.2065},
+		{name: "many upvotes", ups: 100, downs: 10, want: 0.8408},
+		{name: "only downvotes", downs: 5, want: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if res := Wilson(tc.ups, tc.downs); math.Abs(res-tc.want) > 1e-4 {
+				t.Errorf("results not match, want %v, have %v", tc.want, res)
+			}
+		})
+	}
+	if Wilson(1, 0) >= Wilson(90, 10) {
+		t.Errorf("single vote ranked above a well voted item")
+	}
+}
+
+func TestControversy(t *testing.T) {
+	if Controversy(10, 0) != 0 {
+		t.Errorf("one-sided votes are controversial")
+	}
+	if Controversy(50, 50) <= Controversy(90, 10) {
+		t.Errorf("even split ranked below one-sided votes")
+	}
+	if Controversy(10, 50) != Controversy(50, 10) {
+		t.Errorf("controversy is not symmetric")
+	}
+}
+
+func TestHot(t *testing.T) {
+	created := time.Date(2022, 11, 4, 17, 55, 14, 0, time.UTC)
+	if Hot(10, created) <= Hot(1, created) {
+		t.Errorf("higher score ranked lower")
+	}
+	if Hot(10, created.Add(12*time.Hour+30*time.Minute))-Hot(100, created) > 1e-9 {
+		t.Errorf("12.5 hours are not worth a tenfold score")
+	}
+	if Hot(-10, created) >= Hot(0, created) {
+		t.Errorf("negative score ranked above zero")
+	}
+}
+
+func TestRising(t *testing.T) {
+	if Rising(10, time.Hour) <= Rising(10, 10*time.Hour) {
+		t.Errorf("older post ranked above a younger one with the same score")
+	}
+	if Rising(0, time.Hour) != 0 {
+		t.Errorf("post without score is rising")
+	}
+}
@@ -0,0 +1,24 @@
+package communitydata
+
+import "regexp"
+
+// DefaultCommunities are the categories the site started with, every backend seeds them.
+var DefaultCommunities = []string{"music", "funny", "videos", "programming", "news", "fashion"}
+
+// AllCommunities is the pseudo community listing the posts of every community.
+const AllCommunities = "all"
+
+var nameRe = regexp.MustCompile(`^[a-z0-9_]{3,21}$`)
+
+type Community struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	OwnerID     string `json:"owner_id,omitempty"`
+	Created     string `json:"created"`
+	Subscribers int    `json:"subscribers"`
+}
+
+// ValidName reports whether name can be used for a new community.
+func ValidName(name string) bool {
+	return name != AllCommunities && nameRe.MatchString(name)
+}
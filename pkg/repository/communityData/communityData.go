@@ -0,0 +1,23 @@
+package communitydata
+
+type CommunityData interface {
+	CreateCommunity(community Community) (Community, error)
+	GetCommunity(name string) (Community, error)
+	GetCommunities() ([]Community, error)
+	UpdateDescription(name, description string) error
+	Subscribe(name, userID string) error
+	Unsubscribe(name, userID string) error
+	Subscriptions(userID string) ([]string, error)
+	AddModerator(name, userID string) error
+	RemoveModerator(name, userID string) error
+	Moderators(name string) ([]string, error)
+	IsModerator(name, userID string) (bool, error)
+	Ban(name, userID string) error
+	Unban(name, userID string) error
+	IsBanned(name, userID string) (bool, error)
+	AddPin(name, postID string, limit int) error
+	RemovePin(name, postID string) error
+	Pins(name string) ([]string, error)
+	AddModAction(action ModAction) error
+	ModLog(name string, limit int) ([]ModAction, error)
+}
@@ -0,0 +1,41 @@
+package communitydatamap
+
+import (
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	"sync"
+)
+
+var _ communitydata.CommunityData = (*communityDataMap)(nil)
+
+type communityDataMap struct {
+	data        map[string]communitydata.Community
+	subscribers map[string]map[string]struct{}
+	moderators  map[string]map[string]struct{}
+	banned      map[string]map[string]struct{}
+	pins        map[string][]string
+	modLog      []communitydata.ModAction
+	mux         *sync.RWMutex
+}
+
+func NewCommunityDataMap() *communityDataMap {
+	dt := &communityDataMap{
+		data:        make(map[string]communitydata.Community, 10),
+		subscribers: make(map[string]map[string]struct{}, 10),
+		moderators:  make(map[string]map[string]struct{}, 10),
+		banned:      make(map[string]map[string]struct{}, 10),
+		pins:        make(map[string][]string, 10),
+		modLog:      make([]communitydata.ModAction, 0, 10),
+		mux:         &sync.RWMutex{},
+	}
+	for _, name := range communitydata.DefaultCommunities {
+		dt.data[name] = communitydata.Community{Name: name}
+		dt.addSets(name)
+	}
+	return dt
+}
+
+func (dt *communityDataMap) addSets(name string) {
+	dt.subscribers[name] = make(map[string]struct{})
+	dt.moderators[name] = make(map[string]struct{})
+	dt.banned[name] = make(map[string]struct{})
+}
@@ -0,0 +1,89 @@
+package communitydatamap
+
+import (
+	"errors"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	"sort"
+)
+
+func (dt *communityDataMap) CreateCommunity(community communitydata.Community) (communitydata.Community, error) {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	if _, ok := dt.data[community.Name]; ok {
+		return community, errors.New("community already exists")
+	}
+	dt.data[community.Name] = community
+	dt.addSets(community.Name)
+	return community, nil
+}
+
+func (dt *communityDataMap) GetCommunity(name string) (communitydata.Community, error) {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	community, ok := dt.data[name]
+	if !ok {
+		return community, errors.New("invalid community")
+	}
+	community.Subscribers = len(dt.subscribers[name])
+	return community, nil
+}
+
+func (dt *communityDataMap) GetCommunities() ([]communitydata.Community, error) {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	res := make([]communitydata.Community, 0, len(dt.data))
+	for name, el := range dt.data {
+		el.Subscribers = len(dt.subscribers[name])
+		res = append(res, el)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res, nil
+}
+
+func (dt *communityDataMap) UpdateDescription(name, description string) error {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	community, ok := dt.data[name]
+	if !ok {
+		return errors.New("invalid community")
+	}
+	community.Description = description
+	dt.data[name] = community
+	return nil
+}
+
+func (dt *communityDataMap) Subscribe(name, userID string) error {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	subscribers, ok := dt.subscribers[name]
+	if !ok {
+		return errors.New("invalid community")
+	}
+	subscribers[userID] = struct{}{}
+	return nil
+}
+
+func (dt *communityDataMap) Unsubscribe(name, userID string) error {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	if _, ok := dt.subscribers[name][userID]; !ok {
+		return errors.New("invalid subscription")
+	}
+	delete(dt.subscribers[name], userID)
+	return nil
+}
+
+func (dt *communityDataMap) Subscriptions(userID string) ([]string, error) {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	res := make([]string, 0, 10)
+	for name, subscribers := range dt.subscribers {
+		if _, ok := subscribers[userID]; ok {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res, nil
+}
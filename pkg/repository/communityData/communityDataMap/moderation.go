@@ -0,0 +1,140 @@
+package communitydatamap
+
+import (
+	"errors"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	"sort"
+	"strconv"
+)
+
+func (dt *communityDataMap) AddModerator(name, userID string) error {
+	return dt.addMember(dt.moderators, name, userID)
+}
+
+func (dt *communityDataMap) RemoveModerator(name, userID string) error {
+	return dt.removeMember(dt.moderators, name, userID, "invalid moderator")
+}
+
+func (dt *communityDataMap) Moderators(name string) ([]string, error) {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	moderators, ok := dt.moderators[name]
+	if !ok {
+		return nil, errors.New("invalid community")
+	}
+	res := make([]string, 0, len(moderators))
+	for userID := range moderators {
+		res = append(res, userID)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+func (dt *communityDataMap) IsModerator(name, userID string) (bool, error) {
+	return dt.isMember(dt.moderators, name, userID)
+}
+
+func (dt *communityDataMap) Ban(name, userID string) error {
+	return dt.addMember(dt.banned, name, userID)
+}
+
+func (dt *communityDataMap) Unban(name, userID string) error {
+	return dt.removeMember(dt.banned, name, userID, "invalid ban")
+}
+
+func (dt *communityDataMap) IsBanned(name, userID string) (bool, error) {
+	return dt.isMember(dt.banned, name, userID)
+}
+
+// AddPin takes a pin slot for the post, pinning a post twice takes one slot.
+func (dt *communityDataMap) AddPin(name, postID string, limit int) error {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	if _, ok := dt.data[name]; !ok {
+		return errors.New("invalid community")
+	}
+	for _, el := range dt.pins[name] {
+		if el == postID {
+			return nil
+		}
+	}
+	if len(dt.pins[name]) >= limit {
+		return communitydata.ErrTooManyPinned
+	}
+	dt.pins[name] = append(dt.pins[name], postID)
+	return nil
+}
+
+func (dt *communityDataMap) RemovePin(name, postID string) error {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	for i, el := range dt.pins[name] {
+		if el == postID {
+			dt.pins[name] = append(dt.pins[name][:i:i], dt.pins[name][i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (dt *communityDataMap) Pins(name string) ([]string, error) {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	return append([]string{}, dt.pins[name]...), nil
+}
+
+func (dt *communityDataMap) AddModAction(action communitydata.ModAction) error {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	action.ID = strconv.Itoa(len(dt.modLog) + 1)
+	dt.modLog = append(dt.modLog, action)
+	return nil
+}
+
+// ModLog returns the newest entries first.
+func (dt *communityDataMap) ModLog(name string, limit int) ([]communitydata.ModAction, error) {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	if _, ok := dt.data[name]; !ok {
+		return nil, errors.New("invalid community")
+	}
+	res := make([]communitydata.ModAction, 0, 10)
+	for i := len(dt.modLog) - 1; i >= 0 && len(res) < limit; i-- {
+		if dt.modLog[i].Community == name {
+			res = append(res, dt.modLog[i])
+		}
+	}
+	return res, nil
+}
+
+func (dt *communityDataMap) addMember(sets map[string]map[string]struct{}, name, userID string) error {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	members, ok := sets[name]
+	if !ok {
+		return errors.New("invalid community")
+	}
+	members[userID] = struct{}{}
+	return nil
+}
+
+func (dt *communityDataMap) removeMember(sets map[string]map[string]struct{}, name, userID, msg string) error {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	if _, ok := sets[name][userID]; !ok {
+		return errors.New(msg)
+	}
+	delete(sets[name], userID)
+	return nil
+}
+
+func (dt *communityDataMap) isMember(sets map[string]map[string]struct{}, name, userID string) (bool, error) {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	members, ok := sets[name]
+	if !ok {
+		return false, errors.New("invalid community")
+	}
+	_, ok = members[userID]
+	return ok, nil
+}
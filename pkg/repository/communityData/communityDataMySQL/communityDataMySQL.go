@@ -0,0 +1,16 @@
+package communitydatamysql
+
+import (
+	"database/sql"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+)
+
+var _ communitydata.CommunityData = (*CommunityDataMySQL)(nil)
+
+type CommunityDataMySQL struct {
+	db *sql.DB
+}
+
+func NewCommunityDataMySQL(db *sql.DB) *CommunityDataMySQL {
+	return &CommunityDataMySQL{db: db}
+}
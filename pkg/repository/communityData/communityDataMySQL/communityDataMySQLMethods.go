@@ -0,0 +1,117 @@
+package communitydatamysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	"strconv"
+	"time"
+)
+
+const (
+	errDuplicateEntry = 1062
+	errNoReferenced   = 1452
+)
+
+const selectCommunity = "SELECT c.name, c.description, c.owner_id, c.created, " +
+	"(SELECT COUNT(*) FROM subscriptions s WHERE s.name = c.name) FROM communities c"
+
+func (dt *CommunityDataMySQL) CreateCommunity(community communitydata.Community) (communitydata.Community, error) {
+	created, err := time.Parse(time.RFC3339, community.Created)
+	if err != nil {
+		return community, err
+	}
+	ownerID, _ := strconv.Atoi(community.OwnerID)
+	_, err = dt.db.Exec("INSERT INTO communities (name, description, owner_id, created) VALUES (?, ?, ?, ?)",
+		community.Name, community.Description, ownerID, created)
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry {
+		return community, errors.New("community already exists")
+	}
+	return community, err
+}
+
+func (dt *CommunityDataMySQL) GetCommunity(name string) (communitydata.Community, error) {
+	community, err := scanCommunity(dt.db.QueryRow(selectCommunity+" WHERE c.name = ?", name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return community, errors.New("invalid community")
+	}
+	return community, err
+}
+
+func (dt *CommunityDataMySQL) GetCommunities() ([]communitydata.Community, error) {
+	rows, err := dt.db.Query(selectCommunity + " ORDER BY c.name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make([]communitydata.Community, 0, 10)
+	for rows.Next() {
+		community, err := scanCommunity(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, community)
+	}
+	return res, rows.Err()
+}
+
+func (dt *CommunityDataMySQL) UpdateDescription(name, description string) error {
+	res, err := dt.db.Exec("UPDATE communities SET description = ? WHERE name = ?", description, name)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("invalid community")
+	}
+	return nil
+}
+
+func (dt *CommunityDataMySQL) Subscribe(name, userID string) error {
+	_, err := dt.db.Exec("INSERT IGNORE INTO subscriptions (name, user_id) VALUES (?, ?)", name, userID)
+	return noCommunity(err)
+}
+
+func (dt *CommunityDataMySQL) Unsubscribe(name, userID string) error {
+	return dt.deleteMember("DELETE FROM subscriptions WHERE name = ? AND user_id = ?", name, userID, "invalid subscription")
+}
+
+func (dt *CommunityDataMySQL) Subscriptions(userID string) ([]string, error) {
+	rows, err := dt.db.Query("SELECT name FROM subscriptions WHERE user_id = ? ORDER BY name", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make([]string, 0, 10)
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		res = append(res, name)
+	}
+	return res, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCommunity(row scanner) (communitydata.Community, error) {
+	var community communitydata.Community
+	var ownerID sql.NullInt64
+	var created time.Time
+	if err := row.Scan(&community.Name, &community.Description, &ownerID, &created, &community.Subscribers); err != nil {
+		return community, err
+	}
+	if ownerID.Valid {
+		community.OwnerID = strconv.FormatInt(ownerID.Int64, 10)
+	}
+	community.Created = created.UTC().Format("2006-01-02T15:04:05Z07:00")
+	return community, nil
+}
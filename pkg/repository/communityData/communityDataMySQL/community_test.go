@@ -0,0 +1,241 @@
+package communitydatamysql
+
+import (
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCommunity_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewCommunityDataMySQL(db)
+	community := communitydata.Community{Name: "golang", Description: "gophers", OwnerID: "1", Created: "2022-11-04T17:55:14Z"}
+	created, _ := time.Parse(time.RFC3339, community.Created)
+	testTable := []struct {
+		name          string
+		mockBehaviour func()
+		err           error
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func() {
+				mock.ExpectExec("INSERT INTO communities").
+					WithArgs("golang", "gophers", 1, created).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "already exists",
+			mockBehaviour: func() {
+				mock.ExpectExec("INSERT INTO communities").
+					WithArgs("golang", "gophers", 1, created).
+					WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+			},
+			err: errors.New("community already exists"),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehaviour()
+			_, err := repo.CreateCommunity(community)
+			if testCase.err != nil {
+				if err == nil || err.Error() != testCase.err.Error() {
+					t.Errorf("results not match, want %v, have %v", testCase.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected err: %s", err)
+				return
+			}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCommunity_GetCommunity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewCommunityDataMySQL(db)
+	created := time.Date(2022, 11, 4, 17, 55, 14, 0, time.UTC)
+	testTable := []struct {
+		name          string
+		mockBehaviour func()
+		community     communitydata.Community
+		err           error
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func() {
+				rows := sqlmock.NewRows([]string{"name", "description", "owner_id", "created", "subscribers"}).
+					AddRow("golang", "gophers", 1, created, 3)
+				mock.ExpectQuery("SELECT (.+) FROM communities c WHERE").WithArgs("golang").WillReturnRows(rows)
+			},
+			community: communitydata.Community{Name: "golang", Description: "gophers", OwnerID: "1",
+				Created: "2022-11-04T17:55:14Z", Subscribers: 3},
+		},
+		{
+			name: "seeded without owner",
+			mockBehaviour: func() {
+				rows := sqlmock.NewRows([]string{"name", "description", "owner_id", "created", "subscribers"}).
+					AddRow("music", "", nil, created, 0)
+				mock.ExpectQuery("SELECT (.+) FROM communities c WHERE").WithArgs("music").WillReturnRows(rows)
+			},
+			community: communitydata.Community{Name: "music", Created: "2022-11-04T17:55:14Z"},
+		},
+		{
+			name: "invalid community",
+			mockBehaviour: func() {
+				rows := sqlmock.NewRows([]string{"name", "description", "owner_id", "created", "subscribers"})
+				mock.ExpectQuery("SELECT (.+) FROM communities c WHERE").WithArgs("cats").WillReturnRows(rows)
+			},
+			community: communitydata.Community{Name: "cats"},
+			err:       errors.New("invalid community"),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehaviour()
+			got, err := repo.GetCommunity(testCase.community.Name)
+			if testCase.err != nil {
+				if err == nil || err.Error() != testCase.err.Error() {
+					t.Errorf("results not match, want %v, have %v", testCase.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected err: %s", err)
+				return
+			}
+			if !reflect.DeepEqual(got, testCase.community) {
+				t.Errorf("results not match, want %v, have %v", testCase.community, got)
+			}
+		})
+	}
+}
+
+func TestCommunity_Unsubscribe(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewCommunityDataMySQL(db)
+	mock.ExpectExec("DELETE FROM subscriptions").WithArgs("golang", "1").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err = repo.Unsubscribe("golang", "1"); err != nil {
+		t.Errorf("unexpected err: %s", err)
+	}
+	mock.ExpectExec("DELETE FROM subscriptions").WithArgs("golang", "1").WillReturnResult(sqlmock.NewResult(0, 0))
+	if err = repo.Unsubscribe("golang", "1"); err == nil || err.Error() != "invalid subscription" {
+		t.Errorf("results not match, want %v, have %v", "invalid subscription", err)
+	}
+}
+
+func TestCommunity_ModLog(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewCommunityDataMySQL(db)
+	created := time.Date(2022, 11, 4, 17, 55, 14, 0, time.UTC)
+	mock.ExpectQuery("SELECT (.+) FROM communities c WHERE").WithArgs("golang").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "owner_id", "created", "subscribers"}).
+			AddRow("golang", "", 1, created, 1))
+	mock.ExpectQuery("SELECT id, moderator, action, target, reason, created FROM modlog").WithArgs("golang", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "moderator", "action", "target", "reason", "created"}).
+			AddRow(2, "admin", "ban", "spammer", "spam", created).
+			AddRow(1, "admin", "lock", "abcd", "", created))
+	got, err := repo.ModLog("golang", 10)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	want := []communitydata.ModAction{
+		{ID: "2", Community: "golang", Moderator: "admin", Action: "ban", Target: "spammer", Reason: "spam", Created: "2022-11-04T17:55:14Z"},
+		{ID: "1", Community: "golang", Moderator: "admin", Action: "lock", Target: "abcd", Created: "2022-11-04T17:55:14Z"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results not match, want %v, have %v", want, got)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCommunity_IsModerator(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewCommunityDataMySQL(db)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM moderators").WithArgs("golang", "1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM moderators").WithArgs("golang", "2").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	for i, want := range []bool{true, false} {
+		got, err := repo.IsModerator("golang", strconv.Itoa(i+1))
+		if err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+		if got != want {
+			t.Errorf("results not match, want %v, have %v", want, got)
+		}
+	}
+}
+
+func TestCommunity_AddPin(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewCommunityDataMySQL(db)
+	duplicate := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM pins").WithArgs("golang", "p3").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec("INSERT INTO pins").WithArgs("golang", 1, "p3").WillReturnError(duplicate)
+	mock.ExpectExec("INSERT INTO pins").WithArgs("golang", 2, "p3").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err = repo.AddPin("golang", "p3", 2); err != nil {
+		t.Errorf("unexpected err: %s", err)
+	}
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM pins").WithArgs("golang", "p4").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec("INSERT INTO pins").WithArgs("golang", 1, "p4").WillReturnError(duplicate)
+	mock.ExpectExec("INSERT INTO pins").WithArgs("golang", 2, "p4").WillReturnError(duplicate)
+	if err = repo.AddPin("golang", "p4", 2); !errors.Is(err, communitydata.ErrTooManyPinned) {
+		t.Errorf("results not match, want %v, have %v", communitydata.ErrTooManyPinned, err)
+	}
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM pins").WithArgs("golang", "p3").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	if err = repo.AddPin("golang", "p3", 2); err != nil {
+		t.Errorf("unexpected err: %s", err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
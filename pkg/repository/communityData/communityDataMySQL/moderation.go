@@ -0,0 +1,162 @@
+package communitydatamysql
+
+import (
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	"strconv"
+	"time"
+)
+
+func (dt *CommunityDataMySQL) AddModerator(name, userID string) error {
+	_, err := dt.db.Exec("INSERT IGNORE INTO moderators (name, user_id) VALUES (?, ?)", name, userID)
+	return noCommunity(err)
+}
+
+func (dt *CommunityDataMySQL) RemoveModerator(name, userID string) error {
+	return dt.deleteMember("DELETE FROM moderators WHERE name = ? AND user_id = ?", name, userID, "invalid moderator")
+}
+
+func (dt *CommunityDataMySQL) Moderators(name string) ([]string, error) {
+	if _, err := dt.GetCommunity(name); err != nil {
+		return nil, err
+	}
+	rows, err := dt.db.Query("SELECT user_id FROM moderators WHERE name = ? ORDER BY user_id", name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make([]string, 0, 5)
+	for rows.Next() {
+		var userID int
+		if err = rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		res = append(res, strconv.Itoa(userID))
+	}
+	return res, rows.Err()
+}
+
+func (dt *CommunityDataMySQL) IsModerator(name, userID string) (bool, error) {
+	return dt.isMember("SELECT COUNT(*) FROM moderators WHERE name = ? AND user_id = ?", name, userID)
+}
+
+func (dt *CommunityDataMySQL) Ban(name, userID string) error {
+	_, err := dt.db.Exec("INSERT IGNORE INTO bans (name, user_id, created) VALUES (?, ?, ?)", name, userID,
+		time.Now().UTC())
+	return noCommunity(err)
+}
+
+func (dt *CommunityDataMySQL) Unban(name, userID string) error {
+	return dt.deleteMember("DELETE FROM bans WHERE name = ? AND user_id = ?", name, userID, "invalid ban")
+}
+
+func (dt *CommunityDataMySQL) IsBanned(name, userID string) (bool, error) {
+	return dt.isMember("SELECT COUNT(*) FROM bans WHERE name = ? AND user_id = ?", name, userID)
+}
+
+// AddPin takes a free slot from 1 to limit. The primary key on the slot makes
+// two moderators pinning at once take different slots or fail, the count of
+// pinned posts never goes over the limit.
+func (dt *CommunityDataMySQL) AddPin(name, postID string, limit int) error {
+	pinned, err := dt.isMember("SELECT COUNT(*) FROM pins WHERE name = ? AND post_id = ?", name, postID)
+	if err != nil || pinned {
+		return err
+	}
+	for slot := 1; slot <= limit; slot++ {
+		_, err = dt.db.Exec("INSERT INTO pins (name, slot, post_id) VALUES (?, ?, ?)", name, slot, postID)
+		var mysqlErr *mysql.MySQLError
+		if !errors.As(err, &mysqlErr) || mysqlErr.Number != errDuplicateEntry {
+			return noCommunity(err)
+		}
+	}
+	return communitydata.ErrTooManyPinned
+}
+
+func (dt *CommunityDataMySQL) RemovePin(name, postID string) error {
+	_, err := dt.db.Exec("DELETE FROM pins WHERE name = ? AND post_id = ?", name, postID)
+	return err
+}
+
+func (dt *CommunityDataMySQL) Pins(name string) ([]string, error) {
+	rows, err := dt.db.Query("SELECT post_id FROM pins WHERE name = ? ORDER BY slot", name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make([]string, 0, 2)
+	for rows.Next() {
+		var postID string
+		if err = rows.Scan(&postID); err != nil {
+			return nil, err
+		}
+		res = append(res, postID)
+	}
+	return res, rows.Err()
+}
+
+func (dt *CommunityDataMySQL) AddModAction(action communitydata.ModAction) error {
+	created, err := time.Parse(time.RFC3339, action.Created)
+	if err != nil {
+		return err
+	}
+	_, err = dt.db.Exec("INSERT INTO modlog (name, moderator, action, target, reason, created) VALUES (?, ?, ?, ?, ?, ?)",
+		action.Community, action.Moderator, action.Action, action.Target, action.Reason, created)
+	return noCommunity(err)
+}
+
+func (dt *CommunityDataMySQL) ModLog(name string, limit int) ([]communitydata.ModAction, error) {
+	if _, err := dt.GetCommunity(name); err != nil {
+		return nil, err
+	}
+	rows, err := dt.db.Query("SELECT id, moderator, action, target, reason, created FROM modlog WHERE name = ? "+
+		"ORDER BY id DESC LIMIT ?", name, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make([]communitydata.ModAction, 0, 10)
+	for rows.Next() {
+		var id int
+		var created time.Time
+		action := communitydata.ModAction{Community: name}
+		if err = rows.Scan(&id, &action.Moderator, &action.Action, &action.Target, &action.Reason, &created); err != nil {
+			return nil, err
+		}
+		action.ID = strconv.Itoa(id)
+		action.Created = created.UTC().Format("2006-01-02T15:04:05Z07:00")
+		res = append(res, action)
+	}
+	return res, rows.Err()
+}
+
+func (dt *CommunityDataMySQL) deleteMember(query, name, userID, msg string) error {
+	res, err := dt.db.Exec(query, name, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func (dt *CommunityDataMySQL) isMember(query, name, userID string) (bool, error) {
+	var count int
+	if err := dt.db.QueryRow(query, name, userID).Scan(&count); err != nil {
+		return false, err
+	}
+	return count != 0, nil
+}
+
+func noCommunity(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errNoReferenced {
+		return errors.New("invalid community")
+	}
+	return err
+}
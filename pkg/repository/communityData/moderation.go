@@ -0,0 +1,35 @@
+package communitydata
+
+import (
+	"errors"
+)
+
+// ErrTooManyPinned is returned by AddPin when every pin slot of the community is taken.
+var ErrTooManyPinned = errors.New("too many pinned posts")
+
+const (
+	ActionRemovePost      = "remove_post"
+	ActionRemoveComment   = "remove_comment"
+	ActionLock            = "lock"
+	ActionUnlock          = "unlock"
+	ActionPin             = "pin"
+	ActionUnpin           = "unpin"
+	ActionBan             = "ban"
+	ActionUnban           = "unban"
+	ActionAddModerator    = "add_moderator"
+	ActionRemoveModerator = "remove_moderator"
+	ActionHold            = "hold"
+	ActionApprove         = "approve"
+)
+
+// ModAction is an entry of the public moderation log. Target is a post id,
+// post id/comment id or a user login depending on the action.
+type ModAction struct {
+	ID        string `json:"id"`
+	Community string `json:"community"`
+	Moderator string `json:"moderator"`
+	Action    string `json:"action"`
+	Target    string `json:"target"`
+	Reason    string `json:"reason,omitempty"`
+	Created   string `json:"created"`
+}
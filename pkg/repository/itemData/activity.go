@@ -0,0 +1,64 @@
+package itemdata
+
+const (
+	ActivityPost    = "post"
+	ActivityComment = "comment"
+)
+
+// Karma of a user is the sum of the scores of their posts and of their comments.
+type Karma struct {
+	Post    int
+	Comment int
+}
+
+func (k Karma) Total() int {
+	return k.Post + k.Comment
+}
+
+// Activity is a post or a comment in the history of its author. A comment
+// links back to its post by PostID and carries the title of the post.
+type Activity struct {
+	Kind      string `json:"kind"`
+	PostID    string `json:"post_id"`
+	CommentID string `json:"comment_id,omitempty"`
+	ParentID  string `json:"parent_id,omitempty"`
+	Community string `json:"community"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	Author    Author `json:"author"`
+	Score     int    `json:"score"`
+	Created   string `json:"created"`
+}
+
+type ActivityPage struct {
+	Items []Activity `json:"items"`
+	Next  string     `json:"next,omitempty"`
+}
+
+func (p Post) Activity() Activity {
+	return Activity{Kind: ActivityPost, PostID: p.ID, Community: p.Cat, Title: p.Title, Body: p.Text,
+		Author: p.Ath, Score: p.Score, Created: p.Created}
+}
+
+func (p Post) CommentActivity(comm Comment) Activity {
+	return Activity{Kind: ActivityComment, PostID: p.ID, CommentID: comm.ID, ParentID: comm.ParentID,
+		Community: p.Cat, Title: p.Title, Body: comm.Body, Author: comm.Ath, Score: comm.Score, Created: comm.Created}
+}
+
+// ItemID is the id the activity is ordered by when two items have the same creation time.
+func (a Activity) ItemID() string {
+	if a.Kind == ActivityComment {
+		return a.CommentID
+	}
+	return a.PostID
+}
+
+// CursorAfterActivity points right after the item in a newest first history.
+func CursorAfterActivity(a Activity, asOf string) Cursor {
+	return Cursor{Sort: SortNew, Created: a.Created, ID: a.ItemID(), AsOf: asOf}
+}
+
+// LessActivity orders a history newest first, like the new sort of the posts.
+func LessActivity(a, b Activity) bool {
+	return LessRanked(SortNew, 0, Post{Created: a.Created, ID: a.ItemID()}, 0, Post{Created: b.Created, ID: b.ItemID()})
+}
@@ -0,0 +1,159 @@
+package itemdata
+
+import (
+	"errors"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/ranking"
+	"sort"
+)
+
+// MaxCommentDepth limits how deep replies can be nested, a top level comment has depth 0.
+const MaxCommentDepth = 10
+
+const deletedText = "[deleted]"
+
+// Comments are stored flat in Post.Comments, replies point to their parent with ParentID.
+
+func (p *Post) FindComment(id string) (Comment, bool) {
+	for _, el := range p.Comments {
+		if el.ID == id {
+			return el, true
+		}
+	}
+	return Comment{}, false
+}
+
+// AddComment appends a comment or a reply to one of the existing comments.
+func (p *Post) AddComment(comm Comment) error {
+	if comm.ParentID != "" {
+		parent, ok := p.FindComment(comm.ParentID)
+		if !ok || parent.Deleted || !p.publicComment(parent) {
+			return errors.New("invalid parent id")
+		}
+		if p.commentDepth(parent)+1 > MaxCommentDepth {
+			return errors.New("comment is nested too deep")
+		}
+	}
+	p.Comments = append(p.Comments, comm)
+	return nil
+}
+
+// DeleteComment removes a comment. A comment that still has replies is kept
+// as a "[deleted]" placeholder so the thread stays intact, deleted
+// placeholders left without replies are removed as well.
+func (p *Post) DeleteComment(id string) bool {
+	comm, ok := p.FindComment(id)
+	if !ok {
+		return false
+	}
+	if p.hasReplies(id) {
+		// copy first, other Post values may share the slice
+		p.Comments = append([]Comment(nil), p.Comments...)
+		for i := range p.Comments {
+			if p.Comments[i].ID == id {
+				p.Comments[i].Ath = Author{Username: deletedText}
+				p.Comments[i].Body = deletedText
+				p.Comments[i].Deleted = true
+			}
+		}
+		return true
+	}
+	p.removeComment(id)
+	for comm.ParentID != "" {
+		parent, ok := p.FindComment(comm.ParentID)
+		if !ok || !parent.Deleted || p.hasReplies(parent.ID) {
+			break
+		}
+		p.removeComment(parent.ID)
+		comm = parent
+	}
+	return true
+}
+
+func (p *Post) commentDepth(comm Comment) int {
+	depth := 0
+	for comm.ParentID != "" && depth <= len(p.Comments) {
+		parent, ok := p.FindComment(comm.ParentID)
+		if !ok {
+			break
+		}
+		comm = parent
+		depth++
+	}
+	return depth
+}
+
+func (p *Post) hasReplies(id string) bool {
+	for _, el := range p.Comments {
+		if el.ParentID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Post) removeComment(id string) {
+	for i, el := range p.Comments {
+		if el.ID == id {
+			p.Comments = append(p.Comments[:i:i], p.Comments[i+1:]...)
+			return
+		}
+	}
+}
+
+// CommentTree nests replies into their parents keeping the stored order.
+// Replies to a missing parent are shown at the top level.
+func CommentTree(comments []Comment) []Comment {
+	children := make(map[string][]Comment, len(comments))
+	known := make(map[string]bool, len(comments))
+	for _, el := range comments {
+		known[el.ID] = true
+	}
+	roots := make([]Comment, 0, len(comments))
+	for _, el := range comments {
+		if el.ParentID == "" || !known[el.ParentID] {
+			roots = append(roots, el)
+			continue
+		}
+		children[el.ParentID] = append(children[el.ParentID], el)
+	}
+	var build func(list []Comment, depth int) []Comment
+	build = func(list []Comment, depth int) []Comment {
+		for i := range list {
+			if depth < MaxCommentDepth {
+				list[i].Replies = build(children[list[i].ID], depth+1)
+			}
+		}
+		return list
+	}
+	return build(roots, 0)
+}
+
+var commentOrders = map[string]func(a, b Comment) bool{
+	"old": func(a, b Comment) bool { return a.Created < b.Created },
+	"new": func(a, b Comment) bool { return a.Created > b.Created },
+	"top": func(a, b Comment) bool { return a.Score > b.Score },
+	"best": func(a, b Comment) bool {
+		return ranking.Wilson(countVotes(a.Votes)) > ranking.Wilson(countVotes(b.Votes))
+	},
+	"controversial": func(a, b Comment) bool {
+		return ranking.Controversy(countVotes(a.Votes)) > ranking.Controversy(countVotes(b.Votes))
+	},
+}
+
+func ValidCommentOrder(order string) bool {
+	_, ok := commentOrders[order]
+	return ok
+}
+
+// SortComments orders comments by one of old, new, top, best and controversial.
+// CommentTree keeps this order among the replies of every comment.
+func SortComments(comments []Comment, order string) error {
+	less, ok := commentOrders[order]
+	if !ok {
+		return errors.New("invalid sort")
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		return less(comments[i], comments[j])
+	})
+	return nil
+}
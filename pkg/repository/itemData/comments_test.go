@@ -0,0 +1,127 @@
+package itemdata
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestPost_AddComment(t *testing.T) {
+	post := Post{}
+	if err := post.AddComment(Comment{ID: "0"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for i := 1; i <= MaxCommentDepth; i++ {
+		if err := post.AddComment(Comment{ID: strconv.Itoa(i), ParentID: strconv.Itoa(i - 1)}); err != nil {
+			t.Fatalf("unexpected error on depth %d: %s", i, err)
+		}
+	}
+	if err := post.AddComment(Comment{ID: "deep", ParentID: strconv.Itoa(MaxCommentDepth)}); err == nil {
+		t.Errorf("reply deeper than %d accepted", MaxCommentDepth)
+	}
+	if err := post.AddComment(Comment{ID: "orphan", ParentID: "unknown"}); err == nil {
+		t.Errorf("reply to unknown comment accepted")
+	}
+}
+
+func TestPost_DeleteComment(t *testing.T) {
+	post := Post{Comments: []Comment{
+		{ID: "1", Body: "root", Ath: Author{ID: "1"}},
+		{ID: "2", Body: "reply", ParentID: "1"},
+		{ID: "3", Body: "other"},
+	}}
+	if !post.DeleteComment("1") {
+		t.Fatalf("comment not deleted")
+	}
+	root, _ := post.FindComment("1")
+	if !root.Deleted || root.Body != "[deleted]" || root.Ath.ID != "" || len(post.Comments) != 3 {
+		t.Errorf("placeholder not kept: %v", post.Comments)
+	}
+	if err := post.AddComment(Comment{ID: "4", ParentID: "1"}); err == nil {
+		t.Errorf("reply to deleted comment accepted")
+	}
+
+	post.DeleteComment("2")
+	want := []Comment{{ID: "3", Body: "other"}}
+	if !reflect.DeepEqual(post.Comments, want) {
+		t.Errorf("results not match, want %v, have %v", want, post.Comments)
+	}
+	if post.DeleteComment("2") {
+		t.Errorf("missing comment deleted")
+	}
+}
+
+func TestCommentTree(t *testing.T) {
+	comments := []Comment{
+		{ID: "1"},
+		{ID: "2", ParentID: "1"},
+		{ID: "3"},
+		{ID: "4", ParentID: "2"},
+		{ID: "5", ParentID: "1"},
+		{ID: "6", ParentID: "gone"},
+	}
+	want := []Comment{
+		{ID: "1", Replies: []Comment{
+			{ID: "2", ParentID: "1", Replies: []Comment{{ID: "4", ParentID: "2"}}},
+			{ID: "5", ParentID: "1"},
+		}},
+		{ID: "3"},
+		{ID: "6", ParentID: "gone"},
+	}
+	if res := CommentTree(comments); !reflect.DeepEqual(res, want) {
+		t.Errorf("results not match, want %v, have %v", want, res)
+	}
+}
+
+func TestSortComments(t *testing.T) {
+	up := func(n int) []Votes {
+		votes := make([]Votes, 0, n)
+		for i := 0; i < n; i++ {
+			votes = append(votes, Votes{User: strconv.Itoa(i), Vote: 1})
+		}
+		return votes
+	}
+	down := func(votes []Votes, n int) []Votes {
+		for i := 0; i < n; i++ {
+			votes = append(votes, Votes{User: "d" + strconv.Itoa(i), Vote: -1})
+		}
+		return votes
+	}
+	comments := []Comment{
+		{ID: "lucky", Created: "2022-11-04T17:55:14Z", Votes: up(1)},
+		{ID: "popular", Created: "2022-11-04T17:56:14Z", Votes: down(up(30), 5)},
+		{ID: "split", Created: "2022-11-04T17:57:14Z", Votes: down(up(20), 20)},
+		{ID: "fresh", Created: "2022-11-04T17:58:14Z"},
+	}
+	for i := range comments {
+		comments[i].recount()
+	}
+	testCases := []struct {
+		order string
+		want  []string
+	}{
+		{order: "old", want: []string{"lucky", "popular", "split", "fresh"}},
+		{order: "new", want: []string{"fresh", "split", "popular", "lucky"}},
+		{order: "top", want: []string{"popular", "lucky", "split", "fresh"}},
+		{order: "best", want: []string{"popular", "split", "lucky", "fresh"}},
+		{order: "controversial", want: []string{"split", "popular", "lucky", "fresh"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.order, func(t *testing.T) {
+			list := append([]Comment(nil), comments...)
+			if err := SortComments(list, tc.order); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			res := make([]string, 0, len(list))
+			for _, el := range list {
+				res = append(res, el.ID)
+			}
+			if !reflect.DeepEqual(res, tc.want) {
+				t.Errorf("results not match, want %v, have %v", tc.want, res)
+			}
+		})
+	}
+	if err := SortComments(comments, "random"); err == nil {
+		t.Errorf("unknown order accepted")
+	}
+}
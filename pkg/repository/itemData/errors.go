@@ -0,0 +1,21 @@
+package itemdata
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPostNotFound is returned by GetPostID when there is no such post, any
+// other error means the store could not be read.
+var ErrPostNotFound = errors.New("invalid post id")
+
+// ConflictError is returned by SetPost when the stored post has changed since
+// it was read, i.e. its version no longer matches.
+type ConflictError struct {
+	PostID  string
+	Version int64
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("post %s was modified concurrently, version %d is outdated", e.PostID, e.Version)
+}
@@ -4,10 +4,20 @@ package itemdata
 
 type ItemData interface {
 	CreatePost(post Post) (Post, error)
-	GetPosts() ([]Post, error)
-	GetCategory(category string) ([]Post, error)
-	GetName(login string) ([]Post, error)
+	GetPosts(opts ListOptions) ([]Post, error)
+	GetCategory(category string, opts ListOptions) ([]Post, error)
+	GetName(login string, opts ListOptions) ([]Post, error)
+	GetFeed(categories, authorIDs []string, opts ListOptions) ([]Post, error)
+	GetPinned(category string) ([]Post, error)
+	GetHeld(category string) ([]Post, error)
 	GetPostID(id string) (Post, error)
 	SetPost(post Post) error
+	ApplyVote(postID, userID string, vote int) (Post, error)
+	RemoveVote(postID, userID string) (Post, error)
+	ApplyCommentVote(postID, commentID, userID string, vote int) (Post, error)
+	RemoveCommentVote(postID, commentID, userID string) (Post, error)
 	DeletePost(postID string) error
+	Search(query SearchQuery) ([]SearchHit, error)
+	GetKarma(userID string) (Karma, error)
+	GetUserComments(login string, opts ListOptions) ([]Activity, error)
 }
@@ -2,19 +2,22 @@ package itemdatamap
 
 import (
 	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/search"
 	"sync"
 )
 
 var _ itemdata.ItemData = (*itemDataMap)(nil)
 
 type itemDataMap struct {
-	data map[string]itemdata.Post
-	mux  *sync.RWMutex
+	data  map[string]itemdata.Post
+	index *search.Index
+	mux   *sync.RWMutex
 }
 
 func NewItemDataMap() *itemDataMap {
 	return &itemDataMap{
-		data: make(map[string]itemdata.Post, 10),
-		mux:  &sync.RWMutex{},
+		data:  make(map[string]itemdata.Post, 10),
+		index: search.NewIndex(),
+		mux:   &sync.RWMutex{},
 	}
 }
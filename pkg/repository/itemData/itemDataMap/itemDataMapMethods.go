@@ -5,6 +5,7 @@ import (
 	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
 	"sort"
+	"time"
 )
 
 func (dt *itemDataMap) CreatePost(post itemdata.Post) (itemdata.Post, error) {
@@ -12,43 +13,83 @@ func (dt *itemDataMap) CreatePost(post itemdata.Post) (itemdata.Post, error) {
 	dt.mux.Lock()
 	defer dt.mux.Unlock()
 	dt.data[post.ID] = post
+	dt.reindex(post)
 	return post, nil
 }
 
-func (dt *itemDataMap) GetPosts() ([]itemdata.Post, error) {
-	res := make([]itemdata.Post, 0, len(dt.data))
+func (dt *itemDataMap) GetPosts(opts itemdata.ListOptions) ([]itemdata.Post, error) {
+	return dt.list(func(post itemdata.Post) bool { return true }, opts), nil
+}
+
+func (dt *itemDataMap) GetCategory(category string, opts itemdata.ListOptions) ([]itemdata.Post, error) {
+	return dt.list(func(post itemdata.Post) bool { return post.Cat == category }, opts), nil
+}
+
+func (dt *itemDataMap) GetName(login string, opts itemdata.ListOptions) ([]itemdata.Post, error) {
+	return dt.list(func(post itemdata.Post) bool { return post.Ath.Username == login }, opts), nil
+}
+
+// GetFeed lists the posts of the communities together with the posts of the authors.
+func (dt *itemDataMap) GetFeed(categories, authorIDs []string, opts itemdata.ListOptions) ([]itemdata.Post, error) {
+	inFeed := make(map[string]bool, len(categories)+len(authorIDs))
+	for _, el := range categories {
+		inFeed["c:"+el] = true
+	}
+	for _, el := range authorIDs {
+		inFeed["a:"+el] = true
+	}
+	return dt.list(func(post itemdata.Post) bool {
+		return inFeed["c:"+post.Cat] || inFeed["a:"+post.Ath.ID]
+	}, opts), nil
+}
+
+func (dt *itemDataMap) list(match func(post itemdata.Post) bool, opts itemdata.ListOptions) []itemdata.Post {
+	asOf := opts.AsOf()
+	res := make([]itemdata.Post, 0, 10)
 	dt.mux.RLock()
 	for _, el := range dt.data {
-		res = append(res, el)
+		el.Rank = itemdata.RankKey(opts.Sort, el, asOf)
+		if el.Status == "" && match(el) && opts.Admits(el, el.Rank) {
+			res = append(res, el)
+		}
 	}
 	dt.mux.RUnlock()
-	res = dt.sort(res)
-	return res, nil
+	sort.Slice(res, func(i, j int) bool {
+		return itemdata.LessRanked(opts.Sort, res[i].Rank, res[i], res[j].Rank, res[j])
+	})
+	if opts.Limit > 0 && len(res) > opts.Limit {
+		res = res[:opts.Limit]
+	}
+	return res
 }
 
-func (dt *itemDataMap) GetCategory(category string) ([]itemdata.Post, error) {
-	res := make([]itemdata.Post, 0, len(dt.data))
+func (dt *itemDataMap) GetPinned(category string) ([]itemdata.Post, error) {
+	res := make([]itemdata.Post, 0, itemdata.MaxPinned)
 	dt.mux.RLock()
 	for _, el := range dt.data {
-		if el.Cat == category {
+		if el.Cat == category && el.Pinned {
 			res = append(res, el)
 		}
 	}
 	dt.mux.RUnlock()
-	res = dt.sort(res)
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Created < res[j].Created
+	})
 	return res, nil
 }
 
-func (dt *itemDataMap) GetName(login string) ([]itemdata.Post, error) {
-	res := make([]itemdata.Post, 0, len(dt.data))
+func (dt *itemDataMap) GetHeld(category string) ([]itemdata.Post, error) {
+	res := make([]itemdata.Post, 0, 10)
 	dt.mux.RLock()
 	for _, el := range dt.data {
-		if el.Ath.Username == login {
+		if el.Cat == category && len(el.HeldItems()) != 0 {
 			res = append(res, el)
 		}
 	}
 	dt.mux.RUnlock()
-	res = dt.sort(res)
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Created < res[j].Created
+	})
 	return res, nil
 }
 
@@ -57,7 +98,7 @@ func (dt *itemDataMap) GetPostID(id string) (itemdata.Post, error) {
 	defer dt.mux.RUnlock()
 	post, ok := dt.data[id]
 	if !ok {
-		return post, errors.New("invalid post id")
+		return post, itemdata.ErrPostNotFound
 	}
 	return post, nil
 }
@@ -65,23 +106,160 @@ func (dt *itemDataMap) GetPostID(id string) (itemdata.Post, error) {
 func (dt *itemDataMap) SetPost(post itemdata.Post) error {
 	dt.mux.Lock()
 	defer dt.mux.Unlock()
+	old, ok := dt.data[post.ID]
+	if !ok {
+		return errors.New("invalid post id")
+	}
+	if old.Version != post.Version {
+		return &itemdata.ConflictError{PostID: post.ID, Version: post.Version}
+	}
+	post.Version++
 	dt.data[post.ID] = post
+	dt.reindex(post)
 	return nil
 }
 
+func (dt *itemDataMap) ApplyVote(postID, userID string, vote int) (itemdata.Post, error) {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	post, ok := dt.data[postID]
+	if !ok {
+		return post, errors.New("invalid post id")
+	}
+	post.SetVote(userID, vote)
+	post.Version++
+	dt.data[postID] = post
+	return post, nil
+}
+
+func (dt *itemDataMap) RemoveVote(postID, userID string) (itemdata.Post, error) {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	post, ok := dt.data[postID]
+	if !ok {
+		return post, errors.New("invalid post id")
+	}
+	if !post.DropVote(userID) {
+		return itemdata.Post{}, errors.New("invalid vote")
+	}
+	post.Version++
+	dt.data[postID] = post
+	return post, nil
+}
+
+func (dt *itemDataMap) ApplyCommentVote(postID, commentID, userID string, vote int) (itemdata.Post, error) {
+	return dt.updateComment(postID, commentID, func(comm *itemdata.Comment) error {
+		comm.SetVote(userID, vote)
+		return nil
+	})
+}
+
+func (dt *itemDataMap) RemoveCommentVote(postID, commentID, userID string) (itemdata.Post, error) {
+	return dt.updateComment(postID, commentID, func(comm *itemdata.Comment) error {
+		if !comm.DropVote(userID) {
+			return errors.New("invalid vote")
+		}
+		return nil
+	})
+}
+
+// updateComment changes a copy of the comments, posts handed out earlier share the old slice.
+func (dt *itemDataMap) updateComment(postID, commentID string, update func(comm *itemdata.Comment) error) (itemdata.Post, error) {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	post, ok := dt.data[postID]
+	if !ok {
+		return itemdata.Post{}, errors.New("invalid post id")
+	}
+	comments := append([]itemdata.Comment(nil), post.Comments...)
+	for i := range comments {
+		if comments[i].ID != commentID || comments[i].Deleted {
+			continue
+		}
+		if err := update(&comments[i]); err != nil {
+			return itemdata.Post{}, err
+		}
+		post.Comments = comments
+		post.Version++
+		dt.data[postID] = post
+		return post, nil
+	}
+	return itemdata.Post{}, errors.New("invalid comment id")
+}
+
 func (dt *itemDataMap) DeletePost(postID string) error {
 	dt.mux.Lock()
 	defer dt.mux.Unlock()
 	delete(dt.data, postID)
+	dt.index.Remove(postID)
 	return nil
 }
 
-func (dt *itemDataMap) sort(posts []itemdata.Post) []itemdata.Post {
-	sort.Slice(posts, func(i, j int) bool {
-		if posts[i].Score == posts[j].Score {
-			return posts[i].Created < posts[j].Created
+func (dt *itemDataMap) Search(query itemdata.SearchQuery) ([]itemdata.SearchHit, error) {
+	now := time.Now().UTC()
+	hits := make([]itemdata.SearchHit, 0, 10)
+	dt.mux.RLock()
+	for _, id := range dt.index.Lookup(query.Terms()) {
+		if post, ok := dt.data[id]; ok && query.MatchesPost(post) {
+			hits = append(hits, post.SearchHits(query, now)...)
 		}
-		return posts[i].Score > posts[j].Score
+	}
+	dt.mux.RUnlock()
+	return itemdata.RankHits(hits, query.Limit), nil
+}
+
+// GetKarma sums the scores of the visible posts and comments of the user.
+func (dt *itemDataMap) GetKarma(userID string) (itemdata.Karma, error) {
+	var karma itemdata.Karma
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	for _, post := range dt.data {
+		if post.Status != "" {
+			continue
+		}
+		if post.Ath.ID == userID {
+			karma.Post += post.Score
+		}
+		for _, el := range post.Comments {
+			if el.Ath.ID == userID && !el.Deleted && el.Status == "" {
+				karma.Comment += el.Score
+			}
+		}
+	}
+	return karma, nil
+}
+
+// GetUserComments lists the public comments of the user across all posts, newest first.
+func (dt *itemDataMap) GetUserComments(login string, opts itemdata.ListOptions) ([]itemdata.Activity, error) {
+	opts.Sort = itemdata.SortNew
+	res := make([]itemdata.Activity, 0, 10)
+	dt.mux.RLock()
+	for _, post := range dt.data {
+		if post.Status != "" {
+			continue
+		}
+		for _, el := range post.PublicComments() {
+			if el.Ath.Username == login && !el.Deleted && opts.Admits(itemdata.Post{ID: el.ID, Created: el.Created}, 0) {
+				res = append(res, post.CommentActivity(el))
+			}
+		}
+	}
+	dt.mux.RUnlock()
+	sort.Slice(res, func(i, j int) bool {
+		return itemdata.LessActivity(res[i], res[j])
 	})
-	return posts
+	if opts.Limit > 0 && len(res) > opts.Limit {
+		res = res[:opts.Limit]
+	}
+	return res, nil
+}
+
+func (dt *itemDataMap) reindex(post itemdata.Post) {
+	texts := []string{post.Title, post.Text}
+	for _, el := range post.Comments {
+		if !el.Deleted {
+			texts = append(texts, el.Body)
+		}
+	}
+	dt.index.Set(post.ID, texts...)
 }
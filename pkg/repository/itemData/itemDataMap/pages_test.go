@@ -0,0 +1,141 @@
+package itemdatamap
+
+import (
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func newRankedPost(dt *itemDataMap, created string, ups, downs int) itemdata.Post {
+	post := itemdata.Post{Created: created}
+	for i := 0; i < ups; i++ {
+		post.SetVote("u"+strconv.Itoa(i), 1)
+	}
+	for i := 0; i < downs; i++ {
+		post.SetVote("d"+strconv.Itoa(i), -1)
+	}
+	post, _ = dt.CreatePost(post)
+	return post
+}
+
+func TestList_Cursor(t *testing.T) {
+	dt := NewItemDataMap()
+	newRankedPost(dt, "2022-11-04T10:00:00Z", 3, 0)
+	newRankedPost(dt, "2022-11-04T11:00:00Z", 1, 0)
+	newRankedPost(dt, "2022-11-04T11:00:00Z", 1, 0)
+	newRankedPost(dt, "2022-11-04T12:00:00Z", 4, 3)
+	newRankedPost(dt, "2022-11-04T13:00:00Z", 2, 2)
+	newRankedPost(dt, "2022-10-01T13:00:00Z", 10, 1)
+	asOf := "2022-11-05T00:00:00Z"
+
+	for _, sort := range []string{itemdata.SortTop, itemdata.SortHot, itemdata.SortNew, itemdata.SortRising, itemdata.SortControversial} {
+		t.Run(sort, func(t *testing.T) {
+			all, _ := dt.GetPosts(itemdata.ListOptions{Sort: sort, After: itemdata.Cursor{AsOf: asOf}})
+			want := make([]string, 0, len(all))
+			for _, el := range all {
+				want = append(want, el.ID)
+			}
+			res := make([]string, 0, len(all))
+			opts := itemdata.ListOptions{Limit: 2, Sort: sort, After: itemdata.Cursor{AsOf: asOf}}
+			for page := 0; page < 5; page++ {
+				posts, _ := dt.GetPosts(opts)
+				if page == 0 {
+					// a new post on top must not shift the following pages
+					newRankedPost(dt, "2022-11-05T01:00:00Z", 50, 0)
+				}
+				if len(posts) == 0 {
+					break
+				}
+				for _, el := range posts {
+					res = append(res, el.ID)
+				}
+				opts.After = itemdata.CursorAfter(posts[len(posts)-1], sort, asOf)
+			}
+			if !reflect.DeepEqual(res, want) {
+				t.Errorf("results not match, want %v, have %v", want, res)
+			}
+		})
+	}
+}
+
+func TestList_Sorts(t *testing.T) {
+	dt := NewItemDataMap()
+	old := newRankedPost(dt, "2022-10-01T10:00:00Z", 30, 0)
+	popular := newRankedPost(dt, "2022-11-04T10:00:00Z", 10, 0)
+	split := newRankedPost(dt, "2022-11-04T11:00:00Z", 6, 5)
+	fresh := newRankedPost(dt, "2022-11-04T23:00:00Z", 3, 0)
+	asOf := itemdata.Cursor{AsOf: "2022-11-05T00:00:00Z"}
+	testCases := []struct {
+		opts itemdata.ListOptions
+		want []string
+	}{
+		{opts: itemdata.ListOptions{Sort: itemdata.SortTop}, want: []string{old.ID, popular.ID, fresh.ID, split.ID}},
+		{opts: itemdata.ListOptions{Sort: itemdata.SortTop, Window: itemdata.Windows["week"]}, want: []string{popular.ID, fresh.ID, split.ID}},
+		{opts: itemdata.ListOptions{Sort: itemdata.SortNew}, want: []string{fresh.ID, split.ID, popular.ID, old.ID}},
+		{opts: itemdata.ListOptions{Sort: itemdata.SortHot}, want: []string{fresh.ID, popular.ID, split.ID, old.ID}},
+		{opts: itemdata.ListOptions{Sort: itemdata.SortRising}, want: []string{fresh.ID, popular.ID, split.ID}},
+		{opts: itemdata.ListOptions{Sort: itemdata.SortControversial}, want: []string{split.ID, fresh.ID, popular.ID, old.ID}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.opts.Sort, func(t *testing.T) {
+			tc.opts.After = asOf
+			posts, _ := dt.GetPosts(tc.opts)
+			res := make([]string, 0, len(posts))
+			for _, el := range posts {
+				res = append(res, el.ID)
+			}
+			if !reflect.DeepEqual(res, tc.want) {
+				t.Errorf("results not match, want %v, have %v", tc.want, res)
+			}
+		})
+	}
+}
+
+func TestGetPinned(t *testing.T) {
+	dt := NewItemDataMap()
+	first, _ := dt.CreatePost(itemdata.Post{Cat: "music", Created: "2022-11-04T10:00:00Z", Pinned: true})
+	second, _ := dt.CreatePost(itemdata.Post{Cat: "music", Created: "2022-11-04T11:00:00Z", Pinned: true})
+	dt.CreatePost(itemdata.Post{Cat: "music", Created: "2022-11-04T12:00:00Z"})
+	dt.CreatePost(itemdata.Post{Cat: "news", Created: "2022-11-04T12:00:00Z", Pinned: true})
+	posts, _ := dt.GetPinned("music")
+	res := make([]string, 0, len(posts))
+	for _, el := range posts {
+		res = append(res, el.ID)
+	}
+	want := []string{first.ID, second.ID}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("results not match, want %v, have %v", want, res)
+	}
+}
+
+func TestList_Exclude(t *testing.T) {
+	dt := NewItemDataMap()
+	first := newRankedPost(dt, "2022-11-04T10:00:00Z", 3, 0)
+	hidden := newRankedPost(dt, "2022-11-04T11:00:00Z", 2, 0)
+	last := newRankedPost(dt, "2022-11-04T12:00:00Z", 1, 0)
+	posts, _ := dt.GetPosts(itemdata.ListOptions{Limit: 2, Sort: itemdata.SortTop, Exclude: map[string]bool{hidden.ID: true}})
+	res := make([]string, 0, len(posts))
+	for _, el := range posts {
+		res = append(res, el.ID)
+	}
+	if want := []string{first.ID, last.ID}; !reflect.DeepEqual(res, want) {
+		t.Errorf("results not match, want %v, have %v", want, res)
+	}
+}
+
+func TestGetFeed(t *testing.T) {
+	dt := NewItemDataMap()
+	music, _ := dt.CreatePost(itemdata.Post{Cat: "music", Created: "2022-11-04T10:00:00Z"})
+	followed, _ := dt.CreatePost(itemdata.Post{Cat: "news", Created: "2022-11-04T11:00:00Z",
+		Ath: itemdata.Author{ID: "7"}})
+	dt.CreatePost(itemdata.Post{Cat: "news", Created: "2022-11-04T12:00:00Z", Ath: itemdata.Author{ID: "8"}})
+	posts, _ := dt.GetFeed([]string{"music"}, []string{"7"}, itemdata.ListOptions{Sort: itemdata.SortNew})
+	res := make([]string, 0, len(posts))
+	for _, el := range posts {
+		res = append(res, el.ID)
+	}
+	if want := []string{followed.ID, music.ID}; !reflect.DeepEqual(res, want) {
+		t.Errorf("results not match, want %v, have %v", want, res)
+	}
+}
@@ -0,0 +1,112 @@
+package itemdatamap
+
+import (
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func hitIDs(hits []itemdata.SearchHit) []string {
+	res := make([]string, 0, len(hits))
+	for _, el := range hits {
+		res = append(res, el.PostID+"/"+el.CommentID)
+	}
+	return res
+}
+
+func TestSearch(t *testing.T) {
+	dt := NewItemDataMap()
+	now := time.Now().UTC()
+	created := func(age time.Duration) string {
+		return now.Add(-age).Format(time.RFC3339)
+	}
+	golang, _ := dt.CreatePost(itemdata.Post{
+		Title: "Golang generics", Text: "generics landed in go", Cat: "programming", Type: "text",
+		Ath: itemdata.Author{Username: "alice"}, Created: created(time.Hour),
+		Comments: []itemdata.Comment{
+			{ID: "c1", Body: "generics are great", Ath: itemdata.Author{Username: "bob"}, Created: created(time.Minute)},
+			{ID: "c2", Body: "[deleted]", Deleted: true, Created: created(time.Minute)},
+		},
+	})
+	old, _ := dt.CreatePost(itemdata.Post{
+		Title: "Generics in java", Text: "old news", Cat: "news", Type: "link",
+		Ath: itemdata.Author{Username: "bob"}, Created: created(90 * 24 * time.Hour),
+	})
+	music, _ := dt.CreatePost(itemdata.Post{
+		Title: "Guitar", Text: "music", Cat: "music", Type: "text",
+		Ath: itemdata.Author{Username: "alice"}, Created: created(time.Hour),
+	})
+
+	testingTable := []struct {
+		name   string
+		query  itemdata.SearchQuery
+		expect []string
+	}{
+		{
+			name:   "relevance and recency",
+			query:  itemdata.SearchQuery{Text: "Generics"},
+			expect: []string{golang.ID + "/", golang.ID + "/c1", old.ID + "/"},
+		},
+		{
+			name:   "category",
+			query:  itemdata.SearchQuery{Text: "generics", Category: "news"},
+			expect: []string{old.ID + "/"},
+		},
+		{
+			name:   "type",
+			query:  itemdata.SearchQuery{Text: "generics", Type: "link"},
+			expect: []string{old.ID + "/"},
+		},
+		{
+			name:   "author",
+			query:  itemdata.SearchQuery{Text: "generics", Author: "bob"},
+			expect: []string{golang.ID + "/c1", old.ID + "/"},
+		},
+		{
+			name:   "date range",
+			query:  itemdata.SearchQuery{Text: "generics", From: created(2 * time.Hour), To: created(30 * time.Minute)},
+			expect: []string{golang.ID + "/"},
+		},
+		{
+			name:   "limit",
+			query:  itemdata.SearchQuery{Text: "generics", Limit: 1},
+			expect: []string{golang.ID + "/"},
+		},
+		{
+			name:   "no match",
+			query:  itemdata.SearchQuery{Text: "deleted"},
+			expect: []string{},
+		},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			hits, err := dt.Search(testCase.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if res := hitIDs(hits); !reflect.DeepEqual(res, testCase.expect) {
+				t.Errorf("results not match, want %v, have %v", testCase.expect, res)
+			}
+		})
+	}
+
+	t.Run("index follows updates", func(t *testing.T) {
+		music, _ = dt.GetPostID(music.ID)
+		music.Text = "generics for guitars"
+		if err := dt.SetPost(music); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if err := dt.DeletePost(old.ID); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		hits, _ := dt.Search(itemdata.SearchQuery{Text: "generics"})
+		want := []string{golang.ID + "/", golang.ID + "/c1", music.ID + "/"}
+		if res := hitIDs(hits); !reflect.DeepEqual(res, want) {
+			t.Errorf("results not match, want %v, have %v", want, res)
+		}
+		if hits[0].Snippet != "<mark>generics</mark> landed in go" {
+			t.Errorf("results not match, want %v, have %v", "<mark>generics</mark> landed in go", hits[0].Snippet)
+		}
+	})
+}
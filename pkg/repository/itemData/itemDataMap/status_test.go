@@ -0,0 +1,91 @@
+package itemdatamap
+
+import (
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"reflect"
+	"testing"
+)
+
+func TestHeldPosts(t *testing.T) {
+	dt := NewItemDataMap()
+	held, _ := dt.CreatePost(itemdata.Post{Cat: "music", Created: "2022-11-04T10:00:00Z", Status: itemdata.StatusHeld})
+	withComment, _ := dt.CreatePost(itemdata.Post{Cat: "music", Created: "2022-11-04T11:00:00Z",
+		Comments: []itemdata.Comment{{ID: "c1", Status: itemdata.StatusHeld}}})
+	visible, _ := dt.CreatePost(itemdata.Post{Cat: "music", Created: "2022-11-04T12:00:00Z"})
+	dt.CreatePost(itemdata.Post{Cat: "music", Created: "2022-11-04T13:00:00Z", Status: itemdata.StatusRemoved})
+
+	posts, _ := dt.GetHeld("music")
+	res := make([]string, 0, len(posts))
+	for _, el := range posts {
+		res = append(res, el.ID)
+	}
+	if want := []string{held.ID, withComment.ID}; !reflect.DeepEqual(res, want) {
+		t.Errorf("results not match, want %v, have %v", want, res)
+	}
+
+	posts, _ = dt.GetCategory("music", itemdata.ListOptions{Sort: itemdata.SortNew})
+	res = res[:0]
+	for _, el := range posts {
+		res = append(res, el.ID)
+	}
+	if want := []string{visible.ID, withComment.ID}; !reflect.DeepEqual(res, want) {
+		t.Errorf("results not match, want %v, have %v", want, res)
+	}
+}
+
+func TestGetKarma(t *testing.T) {
+	dt := NewItemDataMap()
+	dt.CreatePost(itemdata.Post{Ath: itemdata.Author{ID: "1"}, Score: 5, Comments: []itemdata.Comment{
+		{ID: "c1", Ath: itemdata.Author{ID: "2"}, Score: 3},
+		{ID: "c2", Ath: itemdata.Author{ID: "1"}, Score: -1},
+	}})
+	dt.CreatePost(itemdata.Post{Ath: itemdata.Author{ID: "2"}, Score: 2, Comments: []itemdata.Comment{
+		{ID: "c3", Ath: itemdata.Author{ID: "1"}, Score: 4},
+		{ID: "c4", Ath: itemdata.Author{ID: "1"}, Score: 10, Deleted: true},
+		{ID: "c5", Ath: itemdata.Author{ID: "1"}, Score: 7, Status: itemdata.StatusHeld},
+	}})
+	dt.CreatePost(itemdata.Post{Ath: itemdata.Author{ID: "1"}, Score: 8, Status: itemdata.StatusRemoved, Comments: []itemdata.Comment{
+		{ID: "c6", Ath: itemdata.Author{ID: "1"}, Score: 6},
+	}})
+	if karma, _ := dt.GetKarma("1"); karma != (itemdata.Karma{Post: 5, Comment: 3}) {
+		t.Errorf("results not match, want %v, have %v", itemdata.Karma{Post: 5, Comment: 3}, karma)
+	}
+	if karma, _ := dt.GetKarma("3"); karma != (itemdata.Karma{}) {
+		t.Errorf("results not match, want %v, have %v", itemdata.Karma{}, karma)
+	}
+}
+
+func TestGetUserComments(t *testing.T) {
+	dt := NewItemDataMap()
+	first, _ := dt.CreatePost(itemdata.Post{Title: "first", Cat: "music", Comments: []itemdata.Comment{
+		{ID: "c1", Ath: itemdata.Author{Username: "bob"}, Created: "2022-11-04T10:00:00Z"},
+		{ID: "c2", Ath: itemdata.Author{Username: "alice"}, Created: "2022-11-04T11:00:00Z"},
+		{ID: "c3", Ath: itemdata.Author{Username: "bob"}, Created: "2022-11-04T12:00:00Z", Deleted: true},
+		{ID: "c4", Ath: itemdata.Author{Username: "bob"}, Created: "2022-11-04T13:00:00Z", Status: itemdata.StatusHeld},
+	}})
+	dt.CreatePost(itemdata.Post{Title: "second", Cat: "news", Comments: []itemdata.Comment{
+		{ID: "c5", Ath: itemdata.Author{Username: "bob"}, Created: "2022-11-04T14:00:00Z", ParentID: "c6"},
+		{ID: "c6", Ath: itemdata.Author{Username: "bob"}, Created: "2022-11-04T09:00:00Z"},
+	}})
+	dt.CreatePost(itemdata.Post{Status: itemdata.StatusRemoved, Comments: []itemdata.Comment{
+		{ID: "c7", Ath: itemdata.Author{Username: "bob"}, Created: "2022-11-04T15:00:00Z"},
+	}})
+
+	items, _ := dt.GetUserComments("bob", itemdata.ListOptions{Limit: 2})
+	res := make([]string, 0, len(items))
+	for _, el := range items {
+		res = append(res, el.CommentID)
+	}
+	if want := []string{"c5", "c1"}; !reflect.DeepEqual(res, want) {
+		t.Errorf("results not match, want %v, have %v", want, res)
+	}
+	if items[1].PostID != first.ID || items[1].Title != "first" || items[1].Kind != itemdata.ActivityComment {
+		t.Errorf("results not match, want post %v, have %+v", first.ID, items[1])
+	}
+
+	cursor := itemdata.CursorAfterActivity(items[1], "2022-11-05T00:00:00Z")
+	items, _ = dt.GetUserComments("bob", itemdata.ListOptions{After: cursor})
+	if len(items) != 1 || items[0].CommentID != "c6" {
+		t.Errorf("results not match, want %v, have %+v", "c6", items)
+	}
+}
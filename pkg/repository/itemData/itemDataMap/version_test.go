@@ -0,0 +1,35 @@
+package itemdatamap
+
+import (
+	"errors"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"testing"
+)
+
+func TestSetPost_Conflict(t *testing.T) {
+	dt := NewItemDataMap()
+	post, _ := dt.CreatePost(itemdata.Post{Vote: []itemdata.Votes{{User: "author", Vote: 1}}})
+
+	first, _ := dt.GetPostID(post.ID)
+	second, _ := dt.GetPostID(post.ID)
+	first.Comments = append(first.Comments, itemdata.Comment{ID: "1"})
+	if err := dt.SetPost(first); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	second.Views++
+	var conflict *itemdata.ConflictError
+	if err := dt.SetPost(second); !errors.As(err, &conflict) {
+		t.Errorf("results not match, want conflict, have %v", err)
+	}
+
+	if _, err := dt.ApplyVote(post.ID, "voter", 1); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := dt.SetPost(first); !errors.As(err, &conflict) {
+		t.Errorf("stale post saved after vote, have %v", err)
+	}
+	res, _ := dt.GetPostID(post.ID)
+	if len(res.Comments) != 1 || len(res.Vote) != 2 || res.Version != 2 {
+		t.Errorf("unexpected post %v", res)
+	}
+}
@@ -0,0 +1,94 @@
+package itemdatamap
+
+import (
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestVotes_Concurrent(t *testing.T) {
+	dt := NewItemDataMap()
+	post, _ := dt.CreatePost(itemdata.Post{
+		Score:            1,
+		UpvotePercentage: 100,
+		Vote:             []itemdata.Votes{{User: "author", Vote: 1}},
+	})
+	const users = 200
+	wg := &sync.WaitGroup{}
+	for i := 0; i < users; i++ {
+		wg.Add(1)
+		go func(user string, i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, err := dt.ApplyVote(post.ID, user, 1-2*((i+j)%2)); err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+			}
+			if i%4 == 0 {
+				if _, err := dt.RemoveVote(post.ID, user); err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+			}
+		}(strconv.Itoa(i), i)
+	}
+	wg.Wait()
+
+	res, _ := dt.GetPostID(post.ID)
+	// the last of 20 votes is -1 for even users and 1 for odd ones;
+	// every fourth user (all of them even) took the vote back.
+	wantVotes := 1 + users - users/4
+	wantScore := 1 + users/2 - (users/2 - users/4)
+	if len(res.Vote) != wantVotes {
+		t.Errorf("results not match, want %d votes, have %d", wantVotes, len(res.Vote))
+	}
+	if res.Score != wantScore {
+		t.Errorf("results not match, want score %d, have %d", wantScore, res.Score)
+	}
+	sum := 0
+	for _, el := range res.Vote {
+		sum += el.Vote
+	}
+	if sum != res.Score {
+		t.Errorf("score %d is not consistent with votes %d", res.Score, sum)
+	}
+}
+
+func TestVotes_RemoveMissing(t *testing.T) {
+	dt := NewItemDataMap()
+	post, _ := dt.CreatePost(itemdata.Post{Vote: []itemdata.Votes{{User: "author", Vote: 1}}})
+	if _, err := dt.RemoveVote(post.ID, "stranger"); err == nil {
+		t.Errorf("missing vote removed")
+	}
+	if _, err := dt.ApplyVote("unknown", "stranger", 1); err == nil {
+		t.Errorf("vote on unknown post applied")
+	}
+}
+
+func TestCommentVotes(t *testing.T) {
+	dt := NewItemDataMap()
+	post, _ := dt.CreatePost(itemdata.Post{Comments: []itemdata.Comment{{ID: "1"}, {ID: "2", Deleted: true}}})
+	before, _ := dt.GetPostID(post.ID)
+
+	res, err := dt.ApplyCommentVote(post.ID, "1", "a", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	res, _ = dt.ApplyCommentVote(post.ID, "1", "b", -1)
+	res, _ = dt.ApplyCommentVote(post.ID, "1", "b", 1)
+	if res.Comments[0].Score != 2 || len(res.Comments[0].Votes) != 2 {
+		t.Errorf("unexpected comment %v", res.Comments[0])
+	}
+	if before.Comments[0].Score != 0 {
+		t.Errorf("post read before the vote was changed")
+	}
+	if res, _ = dt.RemoveCommentVote(post.ID, "1", "a"); res.Comments[0].Score != 1 {
+		t.Errorf("results not match, want 1, have %d", res.Comments[0].Score)
+	}
+	if _, err = dt.RemoveCommentVote(post.ID, "1", "a"); err == nil {
+		t.Errorf("missing vote removed")
+	}
+	if _, err = dt.ApplyCommentVote(post.ID, "2", "a", 1); err == nil {
+		t.Errorf("deleted comment voted")
+	}
+}
@@ -1,10 +1,13 @@
 package itemdatamongo
 
 import (
+	"errors"
 	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
 )
 
 func (dt *itemDataMongo) CreatePost(post itemdata.Post) (itemdata.Post, error) {
@@ -16,22 +19,133 @@ func (dt *itemDataMongo) CreatePost(post itemdata.Post) (itemdata.Post, error) {
 	return post, nil
 }
 
-func (dt *itemDataMongo) GetPosts() ([]itemdata.Post, error) {
-	return dt.sort(bson.D{})
+func (dt *itemDataMongo) GetPosts(opts itemdata.ListOptions) ([]itemdata.Post, error) {
+	return dt.sort(bson.D{}, opts)
 }
 
-func (dt *itemDataMongo) GetCategory(category string) ([]itemdata.Post, error) {
-	return dt.sort(bson.D{{"category", category}})
+func (dt *itemDataMongo) GetCategory(category string, opts itemdata.ListOptions) ([]itemdata.Post, error) {
+	return dt.sort(bson.D{{Key: "category", Value: category}}, opts)
 }
 
-func (dt *itemDataMongo) GetName(login string) ([]itemdata.Post, error) {
-	return dt.sort(bson.D{{"username", login}})
+func (dt *itemDataMongo) GetName(login string, opts itemdata.ListOptions) ([]itemdata.Post, error) {
+	return dt.sort(bson.D{{Key: "author.username", Value: login}}, opts)
 }
 
-func (dt *itemDataMongo) sort(m bson.D) ([]itemdata.Post, error) {
+// GetFeed lists the posts of the communities together with the posts of the authors.
+func (dt *itemDataMongo) GetFeed(categories, authorIDs []string, opts itemdata.ListOptions) ([]itemdata.Post, error) {
+	// $in does not accept null
+	categories = append(make([]string, 0, len(categories)), categories...)
+	authorIDs = append(make([]string, 0, len(authorIDs)), authorIDs...)
+	return dt.sort(bson.D{{Key: "$or", Value: bson.A{
+		bson.M{"category": bson.M{"$in": categories}},
+		bson.M{"author.id": bson.M{"$in": authorIDs}},
+	}}}, opts)
+}
+
+// visible leaves out held and removed posts, the status of the others is not stored.
+var visible = bson.E{Key: "status", Value: bson.M{"$exists": false}}
+
+// sort runs the listing as an aggregation: the rank key of every post is
+// computed into the rank field, then the page after the cursor is taken.
+func (dt *itemDataMongo) sort(m bson.D, opts itemdata.ListOptions) ([]itemdata.Post, error) {
+	res := make([]itemdata.Post, 0, 10)
+	filter := append(bson.D{}, m...)
+	filter = append(filter, visible)
+	if len(opts.Exclude) != 0 {
+		excluded := make([]string, 0, len(opts.Exclude))
+		for id := range opts.Exclude {
+			excluded = append(excluded, id)
+		}
+		filter = append(filter, bson.E{Key: "_id", Value: bson.M{"$nin": excluded}})
+	}
+	created := bson.M{}
+	if after := opts.After; after.AsOf != "" {
+		created["$lte"] = after.AsOf
+	}
+	if since := opts.Since(); since != "" {
+		created["$gte"] = since
+	}
+	if len(created) != 0 {
+		filter = append(filter, bson.E{Key: "created", Value: created})
+	}
+	order := -1
+	if itemdata.OldestFirst(opts.Sort) {
+		order = 1
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$addFields", Value: bson.M{"rank": rankKey(opts.Sort, opts.AsOf())}}},
+	}
+	if after := opts.After; after.ID != "" {
+		next := "$gt"
+		if order == -1 {
+			next = "$lt"
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"rank": bson.M{"$lt": after.Key}},
+			bson.M{"rank": after.Key, "created": bson.M{next: after.Created}},
+			bson.M{"rank": after.Key, "created": after.Created, "_id": bson.M{"$gt": after.ID}},
+		}}}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{
+		{Key: "rank", Value: -1},
+		{Key: "created", Value: order},
+		{Key: "_id", Value: 1},
+	}}})
+	if opts.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: opts.Limit}})
+	}
+	posts, err := dt.collection.Aggregate(dt.ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	if err = posts.All(dt.ctx, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// rankKey mirrors itemdata.RankKey.
+func rankKey(sort string, asOf time.Time) interface{} {
+	switch sort {
+	case itemdata.SortHot:
+		return bson.M{"$ifNull": bson.A{"$hot", 0}}
+	case itemdata.SortRising:
+		age := bson.M{"$divide": bson.A{
+			bson.M{"$subtract": bson.A{asOf, bson.M{"$dateFromString": bson.M{"dateString": "$created"}}}},
+			float64(time.Hour / time.Millisecond),
+		}}
+		return bson.M{"$divide": bson.A{"$score", bson.M{"$pow": bson.A{bson.M{"$add": bson.A{age, 2}}, 1.5}}}}
+	case itemdata.SortControversial:
+		return bson.M{"$ifNull": bson.A{"$controversy", 0}}
+	case itemdata.SortNew:
+		return bson.M{"$literal": 0}
+	default:
+		return "$score"
+	}
+}
+
+func (dt *itemDataMongo) GetPinned(category string) ([]itemdata.Post, error) {
+	res := make([]itemdata.Post, 0, itemdata.MaxPinned)
+	opts := options.Find().SetSort(bson.D{{Key: "created", Value: 1}})
+	posts, err := dt.collection.Find(dt.ctx, bson.M{"category": category, "pinned": true}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err = posts.All(dt.ctx, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (dt *itemDataMongo) GetHeld(category string) ([]itemdata.Post, error) {
 	res := make([]itemdata.Post, 0, 10)
-	opts := options.Find().SetSort(bson.D{{"score", -1}, {"created", 1}})
-	posts, err := dt.collection.Find(dt.ctx, m, opts)
+	opts := options.Find().SetSort(bson.D{{Key: "created", Value: 1}})
+	filter := bson.M{"category": category, "$or": bson.A{
+		bson.M{"status": itemdata.StatusHeld},
+		bson.M{"comments": bson.M{"$elemMatch": bson.M{"status": itemdata.StatusHeld, "deleted": bson.M{"$ne": true}}}},
+	}}
+	posts, err := dt.collection.Find(dt.ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -43,21 +157,280 @@ func (dt *itemDataMongo) sort(m bson.D) ([]itemdata.Post, error) {
 
 func (dt *itemDataMongo) GetPostID(id string) (itemdata.Post, error) {
 	var post itemdata.Post
-	if err := dt.collection.FindOne(dt.ctx, bson.M{"_id": id}).Decode(&post); err != nil {
-		return post, err
+	err := dt.collection.FindOne(dt.ctx, bson.M{"_id": id}).Decode(&post)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return post, itemdata.ErrPostNotFound
 	}
-	return post, nil
+	return post, err
 }
 
 func (dt *itemDataMongo) SetPost(post itemdata.Post) error {
-	_, err := dt.collection.ReplaceOne(dt.ctx, bson.M{"_id": post.ID}, post)
+	filter := bson.M{"_id": post.ID, "version": post.Version}
+	if post.Version == 0 {
+		filter = bson.M{"_id": post.ID, "$or": bson.A{
+			bson.M{"version": 0},
+			bson.M{"version": bson.M{"$exists": false}},
+		}}
+	}
+	expected := post.Version
+	post.Version++
+	res, err := dt.collection.ReplaceOne(dt.ctx, filter, post)
 	if err != nil {
 		return err
 	}
+	if res.MatchedCount == 0 {
+		return &itemdata.ConflictError{PostID: post.ID, Version: expected}
+	}
 	return nil
 }
 
+// ApplyVote and RemoveVote rewrite the votes and the derived fields in one
+// pipeline update, so concurrent votes on the same post cannot lose each other.
+func (dt *itemDataMongo) ApplyVote(postID, userID string, vote int) (itemdata.Post, error) {
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"vote": bson.M{"$concatArrays": bson.A{
+			votesWithout("$vote", userID),
+			bson.A{bson.M{"user": bson.M{"$literal": userID}, "vote": vote}},
+		}}}}},
+	}
+	update = append(append(update, recountVotes()...), bumpVersion())
+	return dt.updatePost(bson.M{"_id": postID}, update)
+}
+
+func (dt *itemDataMongo) RemoveVote(postID, userID string) (itemdata.Post, error) {
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"vote": votesWithout("$vote", userID)}}},
+	}
+	update = append(append(update, recountVotes()...), bumpVersion())
+	post, err := dt.updatePost(bson.M{"_id": postID, "vote.user": userID}, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return itemdata.Post{}, errors.New("invalid vote")
+	}
+	return post, err
+}
+
+func (dt *itemDataMongo) ApplyCommentVote(postID, commentID, userID string, vote int) (itemdata.Post, error) {
+	update := mongo.Pipeline{
+		setComment(commentID, bson.M{"votes": bson.M{"$concatArrays": bson.A{
+			votesWithout("$$this.votes", userID),
+			bson.A{bson.M{"user": bson.M{"$literal": userID}, "vote": vote}},
+		}}}),
+		recountCommentVotes(),
+		bumpVersion(),
+	}
+	filter := bson.M{"_id": postID, "comments": bson.M{"$elemMatch": bson.M{
+		"id":      commentID,
+		"deleted": bson.M{"$ne": true},
+	}}}
+	post, err := dt.updatePost(filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return itemdata.Post{}, errors.New("invalid comment id")
+	}
+	return post, err
+}
+
+func (dt *itemDataMongo) RemoveCommentVote(postID, commentID, userID string) (itemdata.Post, error) {
+	update := mongo.Pipeline{
+		setComment(commentID, bson.M{"votes": votesWithout("$$this.votes", userID)}),
+		recountCommentVotes(),
+		bumpVersion(),
+	}
+	filter := bson.M{"_id": postID, "comments": bson.M{"$elemMatch": bson.M{
+		"id":         commentID,
+		"votes.user": userID,
+	}}}
+	post, err := dt.updatePost(filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return itemdata.Post{}, errors.New("invalid vote")
+	}
+	return post, err
+}
+
+func (dt *itemDataMongo) updatePost(filter bson.M, update interface{}) (itemdata.Post, error) {
+	var post itemdata.Post
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := dt.collection.FindOneAndUpdate(dt.ctx, filter, update, opts).Decode(&post); err != nil {
+		return itemdata.Post{}, err
+	}
+	return post, nil
+}
+
+func votesWithout(field, userID string) bson.M {
+	return bson.M{"$filter": bson.M{
+		"input": bson.M{"$ifNull": bson.A{field, bson.A{}}},
+		"cond":  bson.M{"$ne": bson.A{"$$this.user", bson.M{"$literal": userID}}},
+	}}
+}
+
+// recountVotes returns the stages that recount the derived fields like Post.recount does.
+func recountVotes() []bson.D {
+	count := func(vote int) bson.M {
+		return bson.M{"$size": bson.M{"$filter": bson.M{
+			"input": "$vote",
+			"cond":  bson.M{"$eq": bson.A{"$$this.vote", vote}},
+		}}}
+	}
+	size := bson.M{"$size": "$vote"}
+	percentage := bson.M{"$toInt": bson.M{"$trunc": bson.M{"$divide": bson.A{bson.M{"$multiply": bson.A{"$ups", 100}}, size}}}}
+	seconds := bson.M{"$subtract": bson.A{
+		bson.M{"$divide": bson.A{bson.M{"$toLong": bson.M{"$dateFromString": bson.M{"dateString": "$created"}}}, 1000}},
+		hotEpoch,
+	}}
+	order := bson.M{"$log10": bson.M{"$max": bson.A{bson.M{"$abs": "$score"}, 1}}}
+	controversy := bson.M{"$cond": bson.A{
+		bson.M{"$or": bson.A{bson.M{"$lte": bson.A{"$ups", 0}}, bson.M{"$lte": bson.A{"$downs", 0}}}},
+		0,
+		bson.M{"$pow": bson.A{
+			bson.M{"$add": bson.A{"$ups", "$downs"}},
+			bson.M{"$divide": bson.A{bson.M{"$min": bson.A{"$ups", "$downs"}}, bson.M{"$max": bson.A{"$ups", "$downs"}}}},
+		}},
+	}}
+	return []bson.D{
+		{{Key: "$set", Value: bson.M{"ups": count(1), "downs": count(-1)}}},
+		{{Key: "$set", Value: bson.M{
+			"score":            bson.M{"$subtract": bson.A{"$ups", "$downs"}},
+			"upvotePercentage": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{size, 0}}, 0, percentage}},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"hot": bson.M{"$add": bson.A{
+				bson.M{"$multiply": bson.A{bson.M{"$cmp": bson.A{"$score", 0}}, order}},
+				bson.M{"$divide": bson.A{seconds, 45000}},
+			}},
+			"controversy": controversy,
+		}}},
+	}
+}
+
+// backfillRanks recounts the posts that have no hot field yet, listings would
+// rank them 0 until the next vote. Their votes may be stored as null.
+func (dt *itemDataMongo) backfillRanks() error {
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"vote": bson.M{"$ifNull": bson.A{"$vote", bson.A{}}}}}},
+	}
+	update = append(update, recountVotes()...)
+	_, err := dt.collection.UpdateMany(dt.ctx, bson.M{"hot": bson.M{"$exists": false}}, update)
+	return err
+}
+
+// hotEpoch is the reference point of ranking.Hot.
+const hotEpoch = 1134028003
+
+// setComment merges fields into the comment with the given id, the fields can refer to it as $$this.
+func setComment(commentID string, fields bson.M) bson.D {
+	return bson.D{{Key: "$set", Value: bson.M{"comments": bson.M{"$map": bson.M{
+		"input": "$comments",
+		"in": bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{"$$this.id", bson.M{"$literal": commentID}}},
+			bson.M{"$mergeObjects": bson.A{"$$this", fields}},
+			"$$this",
+		}},
+	}}}}}
+}
+
+func recountCommentVotes() bson.D {
+	return bson.D{{Key: "$set", Value: bson.M{"comments": bson.M{"$map": bson.M{
+		"input": "$comments",
+		"in": bson.M{"$mergeObjects": bson.A{
+			"$$this",
+			bson.M{"score": bson.M{"$sum": bson.M{"$ifNull": bson.A{"$$this.votes.vote", bson.A{}}}}},
+		}},
+	}}}}}
+}
+
+func bumpVersion() bson.D {
+	return bson.D{{Key: "$set", Value: bson.M{"version": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$version", 0}}, 1}}}}}
+}
+
 func (dt *itemDataMongo) DeletePost(postID string) error {
 	_, err := dt.collection.DeleteOne(dt.ctx, bson.M{"_id": postID})
 	return err
 }
+
+// GetKarma sums the scores of the visible posts and comments of the user.
+func (dt *itemDataMongo) GetKarma(userID string) (itemdata.Karma, error) {
+	own := bson.M{"$filter": bson.M{
+		"input": bson.M{"$ifNull": bson.A{"$comments", bson.A{}}},
+		"cond": bson.M{"$and": bson.A{
+			bson.M{"$eq": bson.A{"$$this.author.id", bson.M{"$literal": userID}}},
+			bson.M{"$ne": bson.A{"$$this.deleted", true}},
+			bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$$this.status", ""}}, ""}},
+		}},
+	}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "$or", Value: bson.A{
+				bson.M{"author.id": userID},
+				bson.M{"comments.author.id": userID},
+			}},
+			visible,
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"post":    bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$author.id", bson.M{"$literal": userID}}}, "$score", 0}},
+			"comment": bson.M{"$sum": bson.M{"$map": bson.M{"input": own, "in": "$$this.score"}}},
+		}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "post": bson.M{"$sum": "$post"}, "comment": bson.M{"$sum": "$comment"}}}},
+	}
+	cur, err := dt.collection.Aggregate(dt.ctx, pipeline)
+	if err != nil {
+		return itemdata.Karma{}, err
+	}
+	res := make([]struct {
+		Post    int `bson:"post"`
+		Comment int `bson:"comment"`
+	}, 0, 1)
+	if err = cur.All(dt.ctx, &res); err != nil {
+		return itemdata.Karma{}, err
+	}
+	if len(res) == 0 {
+		return itemdata.Karma{}, nil
+	}
+	return itemdata.Karma{Post: res[0].Post, Comment: res[0].Comment}, nil
+}
+
+// GetUserComments unwinds the comments of the visible posts the user commented
+// on and pages through them newest first.
+func (dt *itemDataMongo) GetUserComments(login string, opts itemdata.ListOptions) ([]itemdata.Activity, error) {
+	filter := bson.D{
+		{Key: "comments.author.username", Value: login},
+		{Key: "comments.deleted", Value: bson.M{"$ne": true}},
+		{Key: "comments.status", Value: bson.M{"$exists": false}},
+	}
+	if after := opts.After; after.AsOf != "" {
+		filter = append(filter, bson.E{Key: "comments.created", Value: bson.M{"$lte": after.AsOf}})
+	}
+	if after := opts.After; after.ID != "" {
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.M{"comments.created": bson.M{"$lt": after.Created}},
+			bson.M{"comments.created": after.Created, "comments.id": bson.M{"$gt": after.ID}},
+		}})
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "comments.author.username", Value: login}, visible}}},
+		{{Key: "$unwind", Value: "$comments"}},
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.D{{Key: "comments.created", Value: -1}, {Key: "comments.id", Value: 1}}}},
+	}
+	if opts.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: opts.Limit}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$project", Value: bson.M{"title": 1, "category": 1, "comments": 1}}})
+	cur, err := dt.collection.Aggregate(dt.ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]struct {
+		ID       string           `bson:"_id"`
+		Title    string           `bson:"title"`
+		Cat      string           `bson:"category"`
+		Comments itemdata.Comment `bson:"comments"`
+	}, 0, 10)
+	if err = cur.All(dt.ctx, &rows); err != nil {
+		return nil, err
+	}
+	res := make([]itemdata.Activity, 0, len(rows))
+	for _, el := range rows {
+		post := itemdata.Post{ID: el.ID, Title: el.Title, Cat: el.Cat}
+		res = append(res, post.CommentActivity(el.Comments))
+	}
+	return res, nil
+}
@@ -160,7 +160,7 @@ func TestPosts_GetPosts(t *testing.T) {
 		mt.Run(tc.name, func(mt *mtest.T) {
 			mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
 			mt.AddMockResponses(tc.mongoRes(mt, tc.postsRes)...)
-			res, err := mongo.GetPosts()
+			res, err := mongo.GetPosts(itemdata.ListOptions{})
 			if tc.wantErr == nil {
 				if err != nil {
 					t.Errorf("unexpected error")
@@ -244,7 +244,7 @@ func TestPosts_GetCategory(t *testing.T) {
 		mt.Run(tc.name, func(mt *mtest.T) {
 			mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
 			mt.AddMockResponses(tc.mongoRes(mt, tc.postsRes)...)
-			res, err := mongo.GetCategory(tc.category)
+			res, err := mongo.GetCategory(tc.category, itemdata.ListOptions{})
 			if tc.wantErr == nil {
 				if err != nil {
 					t.Errorf("unexpected error")
@@ -329,7 +329,7 @@ func TestPosts_GetName(t *testing.T) {
 		mt.Run(tc.name, func(mt *mtest.T) {
 			mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
 			mt.AddMockResponses(tc.mongoRes(mt, tc.postsRes)...)
-			res, err := mongo.GetName(tc.nameUser)
+			res, err := mongo.GetName(tc.nameUser, itemdata.ListOptions{})
 			if tc.wantErr == nil {
 				if err != nil {
 					t.Errorf("unexpected error")
@@ -349,6 +349,17 @@ func TestPosts_GetName(t *testing.T) {
 			}
 		})
 	}
+
+	// the login is stored in the author, posts have no username field
+	mt.Run("filter", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "postDB.postDB", mtest.FirstBatch))
+		_, err := mongo.GetName("123", itemdata.ListOptions{})
+		require.NoError(mt, err)
+		stages, _ := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Values()
+		match := stages[0].Document().Lookup("$match").Document()
+		require.EqualValues(mt, "123", match.Lookup("author.username").StringValue())
+	})
 }
 
 func TestPosts_GetPostID(t *testing.T) {
@@ -401,6 +412,15 @@ func TestPosts_GetPostID(t *testing.T) {
 			wantErr: errors.New("invalid post id"),
 			postID:  "123",
 		},
+		{
+			name:     "not found",
+			postsRes: itemdata.Post{},
+			mongoRes: func(mt *mtest.T, posts itemdata.Post) []bson.D {
+				return []bson.D{mtest.CreateCursorResponse(0, "postDB.postDB", mtest.FirstBatch)}
+			},
+			wantErr: itemdata.ErrPostNotFound,
+			postID:  "123",
+		},
 	}
 
 	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
@@ -460,10 +480,17 @@ func TestPosts_SetPost(t *testing.T) {
 		{
 			name:      "ok",
 			inputPost: post,
-			mongoRes:  mtest.CreateSuccessResponse(),
+			mongoRes:  mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
 			wantErr:   nil,
 		},
 
+		{
+			name:      "version conflict",
+			inputPost: post,
+			mongoRes:  mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+			wantErr:   &itemdata.ConflictError{PostID: "123", Version: 0},
+		},
+
 		{
 			name:      "replace error",
 			inputPost: post,
@@ -547,3 +574,229 @@ func TestPosts_DeletePost(t *testing.T) {
 		})
 	}
 }
+
+func TestPosts_ApplyVote(t *testing.T) {
+	post := itemdata.Post{
+		ID: "123",
+		Ath: itemdata.Author{
+			ID:       "1",
+			Username: "123",
+		},
+		Comments:         []itemdata.Comment{},
+		Cat:              "music",
+		Score:            2,
+		Type:             "text",
+		Title:            "213",
+		Created:          "2022-11-04T17:55:14Z",
+		UpvotePercentage: 100,
+		Views:            1,
+		Text:             "123",
+		Vote:             []itemdata.Votes{{User: "1", Vote: 1}, {User: "2", Vote: 1}},
+	}
+	testCases := []struct {
+		name     string
+		mongoRes bson.D
+		wantErr  error
+	}{
+		{
+			name:     "ok",
+			mongoRes: mtest.CreateSuccessResponse(bson.E{Key: "value", Value: marshalPost(post)}),
+			wantErr:  nil,
+		},
+		{
+			name: "update error",
+			mongoRes: mtest.CreateWriteErrorsResponse(mtest.WriteError{
+				Index:   1,
+				Code:    123,
+				Message: "invalid post id",
+			}),
+			wantErr: errors.New("invalid post id"),
+		},
+	}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	for _, tc := range testCases {
+		tc := tc
+		mt.Run(tc.name, func(mt *mtest.T) {
+			mt.AddMockResponses(tc.mongoRes)
+			mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+			res, err := mongo.ApplyVote("123", "2", 1)
+			if tc.wantErr == nil {
+				if err != nil {
+					t.Errorf("unexpected error")
+				}
+				require.EqualValues(mt, post.Score, res.Score)
+				require.EqualValues(mt, post.Vote, res.Vote)
+				cmd := mt.GetStartedEvent().Command
+				update, ok := cmd.Lookup("update").ArrayOK()
+				require.True(mt, ok, "update is not a pipeline")
+				values, _ := update.Values()
+				require.Len(mt, values, 5)
+				require.Contains(mt, values[4].String(), `"version"`)
+			} else {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr.Error()) {
+					t.Errorf("unexpected error")
+				}
+			}
+		})
+	}
+}
+
+func TestPosts_RemoveVote(t *testing.T) {
+	post := itemdata.Post{
+		ID:               "123",
+		Score:            1,
+		UpvotePercentage: 100,
+		Vote:             []itemdata.Votes{{User: "1", Vote: 1}},
+	}
+	testCases := []struct {
+		name     string
+		mongoRes bson.D
+		wantErr  error
+	}{
+		{
+			name:     "ok",
+			mongoRes: mtest.CreateSuccessResponse(bson.E{Key: "value", Value: marshalPost(post)}),
+			wantErr:  nil,
+		},
+		{
+			name:     "no vote",
+			mongoRes: mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}),
+			wantErr:  errors.New("invalid vote"),
+		},
+	}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	for _, tc := range testCases {
+		tc := tc
+		mt.Run(tc.name, func(mt *mtest.T) {
+			mt.AddMockResponses(tc.mongoRes)
+			mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+			res, err := mongo.RemoveVote("123", "2")
+			if tc.wantErr == nil {
+				if err != nil {
+					t.Errorf("unexpected error")
+				}
+				require.EqualValues(mt, post.Vote, res.Vote)
+				filter := mt.GetStartedEvent().Command.Lookup("query").Document()
+				require.EqualValues(mt, "2", filter.Lookup("vote.user").StringValue())
+			} else {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr.Error()) {
+					t.Errorf("unexpected error")
+				}
+			}
+		})
+	}
+}
+
+func TestPosts_ApplyCommentVote(t *testing.T) {
+	post := itemdata.Post{
+		ID: "123",
+		Comments: []itemdata.Comment{
+			{ID: "c1", Body: "123", Score: -1, Votes: []itemdata.Votes{{User: "2", Vote: -1}}},
+		},
+		Score: 1,
+		Vote:  []itemdata.Votes{{User: "1", Vote: 1}},
+	}
+	testCases := []struct {
+		name     string
+		mongoRes bson.D
+		wantErr  error
+	}{
+		{
+			name:     "ok",
+			mongoRes: mtest.CreateSuccessResponse(bson.E{Key: "value", Value: marshalPost(post)}),
+			wantErr:  nil,
+		},
+		{
+			name:     "no comment",
+			mongoRes: mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}),
+			wantErr:  errors.New("invalid comment id"),
+		},
+	}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	for _, tc := range testCases {
+		tc := tc
+		mt.Run(tc.name, func(mt *mtest.T) {
+			mt.AddMockResponses(tc.mongoRes)
+			mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+			res, err := mongo.ApplyCommentVote("123", "c1", "2", -1)
+			if tc.wantErr == nil {
+				if err != nil {
+					t.Errorf("unexpected error")
+				}
+				require.EqualValues(mt, post.Comments, res.Comments)
+				cmd := mt.GetStartedEvent().Command
+				match := cmd.Lookup("query", "comments", "$elemMatch").Document()
+				require.EqualValues(mt, "c1", match.Lookup("id").StringValue())
+				update, ok := cmd.Lookup("update").ArrayOK()
+				require.True(mt, ok, "update is not a pipeline")
+				values, _ := update.Values()
+				require.Len(mt, values, 3)
+			} else {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr.Error()) {
+					t.Errorf("unexpected error")
+				}
+			}
+		})
+	}
+}
+
+func TestPosts_GetPostsAfter(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("cursor", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "postDB.postDB", mtest.FirstBatch))
+		after := itemdata.Cursor{Sort: "hot", Key: 1.5, Created: "2022-11-04T17:55:14Z", ID: "123", AsOf: "2022-11-05T00:00:00Z"}
+		_, err := mongo.GetPosts(itemdata.ListOptions{Limit: 26, After: after, Sort: "hot"})
+		require.NoError(mt, err)
+		stages, _ := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Values()
+		require.Len(mt, stages, 5)
+		match := stages[0].Document().Lookup("$match").Document()
+		require.EqualValues(mt, after.AsOf, match.Lookup("created", "$lte").StringValue())
+		require.EqualValues(mt, "$hot", stages[1].Document().Lookup("$addFields", "rank", "$ifNull").Array().Index(0).Value().StringValue())
+		keyset, _ := stages[2].Document().Lookup("$match", "$or").Array().Values()
+		require.Len(mt, keyset, 3)
+		require.EqualValues(mt, -1, stages[3].Document().Lookup("$sort", "created").Int32())
+		require.EqualValues(mt, 26, stages[4].Document().Lookup("$limit").AsInt64())
+	})
+
+	mt.Run("rising", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "postDB.postDB", mtest.FirstBatch))
+		_, err := mongo.GetPosts(itemdata.ListOptions{Sort: "rising", After: itemdata.Cursor{AsOf: "2022-11-05T00:00:00Z"}})
+		require.NoError(mt, err)
+		stages, _ := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Values()
+		match := stages[0].Document().Lookup("$match").Document()
+		require.EqualValues(mt, "2022-11-04T00:00:00Z", match.Lookup("created", "$gte").StringValue())
+		require.EqualValues(mt, "2022-11-05T00:00:00Z", match.Lookup("created", "$lte").StringValue())
+	})
+}
+
+func TestPosts_GetFeed(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("ok", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "postDB.postDB", mtest.FirstBatch))
+		_, err := mongo.GetFeed([]string{"music"}, nil, itemdata.ListOptions{Sort: "new"})
+		require.NoError(mt, err)
+		stages, _ := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Values()
+		sources, _ := stages[0].Document().Lookup("$match", "$or").Array().Values()
+		require.Len(mt, sources, 2)
+		categories, _ := sources[0].Document().Lookup("category", "$in").Array().Values()
+		require.Len(mt, categories, 1)
+		authors, _ := sources[1].Document().Lookup("author.id", "$in").Array().Values()
+		require.Len(mt, authors, 0)
+	})
+}
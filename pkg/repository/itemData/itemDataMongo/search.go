@@ -0,0 +1,73 @@
+package itemdatamongo
+
+import (
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"strings"
+	"time"
+)
+
+// searchCandidates bounds how many posts found by the text index are scored.
+const searchCandidates = 500
+
+// EnsureIndexes creates the text index used by Search. The language is none so
+// Mongo neither stems nor drops words, the same as the search package. The
+// author indexes serve GetKarma, which automod reads for every new item.
+// It also ranks the posts stored before the hot and controversial sorts.
+func (dt *itemDataMongo) EnsureIndexes() error {
+	if err := dt.backfillRanks(); err != nil {
+		return err
+	}
+	_, err := dt.collection.Indexes().CreateMany(dt.ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "title", Value: "text"},
+				{Key: "text", Value: "text"},
+				{Key: "comments.body", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("search").
+				SetDefaultLanguage("none").
+				SetWeights(bson.D{{Key: "title", Value: 3}, {Key: "text", Value: 1}, {Key: "comments.body", Value: 1}}),
+		},
+		{Keys: bson.D{{Key: "author.id", Value: 1}}},
+		{Keys: bson.D{{Key: "comments.author.id", Value: 1}}},
+	})
+	return err
+}
+
+// Search takes the best candidates of the text index and scores them like
+// the in-memory backend does.
+func (dt *itemDataMongo) Search(query itemdata.SearchQuery) ([]itemdata.SearchHit, error) {
+	terms := query.Terms()
+	if len(terms) == 0 {
+		return []itemdata.SearchHit{}, nil
+	}
+	filter := bson.D{{Key: "$text", Value: bson.M{"$search": strings.Join(terms, " ")}}, visible}
+	if query.Category != "" {
+		filter = append(filter, bson.E{Key: "category", Value: query.Category})
+	}
+	if query.Type != "" {
+		filter = append(filter, bson.E{Key: "type", Value: query.Type})
+	}
+	opts := options.Find().
+		SetProjection(bson.M{"textScore": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.M{"textScore": bson.M{"$meta": "textScore"}}).
+		SetLimit(searchCandidates)
+	cur, err := dt.collection.Find(dt.ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	posts := make([]itemdata.Post, 0, 10)
+	if err = cur.All(dt.ctx, &posts); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	hits := make([]itemdata.SearchHit, 0, len(posts))
+	for _, el := range posts {
+		hits = append(hits, el.SearchHits(query, now)...)
+	}
+	return itemdata.RankHits(hits, query.Limit), nil
+}
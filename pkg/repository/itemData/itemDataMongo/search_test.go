@@ -0,0 +1,74 @@
+package itemdatamongo
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"testing"
+	"time"
+)
+
+func TestPosts_Search(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("ok", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		created := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)
+		post := itemdata.Post{
+			ID: "1", Title: "Golang", Text: "generics landed", Cat: "programming", Type: "text", Created: created,
+			Comments: []itemdata.Comment{{ID: "c1", Body: "no generics here", Created: created}},
+		}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "postDB.postDB", mtest.FirstBatch, marshalPost(post)))
+		hits, err := mongo.Search(itemdata.SearchQuery{Text: "the Generics", Category: "programming", Limit: 1})
+		require.NoError(mt, err)
+		require.Len(mt, hits, 1)
+		require.EqualValues(mt, "<mark>generics</mark> landed", hits[0].Snippet)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		require.EqualValues(mt, "generics", filter.Lookup("$text", "$search").StringValue())
+		require.EqualValues(mt, "programming", filter.Lookup("category").StringValue())
+	})
+
+	mt.Run("empty query", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		hits, err := mongo.Search(itemdata.SearchQuery{Text: "the"})
+		require.NoError(mt, err)
+		require.Empty(mt, hits)
+	})
+
+	mt.Run("get server problems", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+		_, err := mongo.Search(itemdata.SearchQuery{Text: "generics"})
+		require.Error(mt, err)
+	})
+}
+
+func TestPosts_EnsureIndexes(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("ok", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}, {Key: "nModified", Value: 2}}, mtest.CreateSuccessResponse())
+		require.NoError(mt, mongo.EnsureIndexes())
+		backfill := mt.GetStartedEvent().Command
+		update := backfill.Lookup("updates").Array().Index(0).Value().Document()
+		require.False(mt, update.Lookup("q", "hot", "$exists").Boolean())
+		stages, _ := update.Lookup("u").Array().Values()
+		require.Len(mt, stages, 4)
+		create := mt.GetStartedEvent().Command
+		indexes, _ := create.Lookup("indexes").Array().Values()
+		require.Len(mt, indexes, 3)
+		require.EqualValues(mt, 1, indexes[1].Document().Lookup("key", "author.id").Int32())
+		require.EqualValues(mt, 1, indexes[2].Document().Lookup("key", "comments.author.id").Int32())
+	})
+
+	mt.Run("backfill problems", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+		require.Error(mt, mongo.EnsureIndexes())
+	})
+}
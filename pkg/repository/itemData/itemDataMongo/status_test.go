@@ -0,0 +1,93 @@
+package itemdatamongo
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"testing"
+)
+
+func TestPosts_GetHeld(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("ok", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		post := itemdata.Post{ID: "1", Cat: "music", Status: itemdata.StatusHeld}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "postDB.postDB", mtest.FirstBatch, marshalPost(post)))
+		posts, err := mongo.GetHeld("music")
+		require.NoError(mt, err)
+		require.Len(mt, posts, 1)
+		require.EqualValues(mt, itemdata.StatusHeld, posts[0].Status)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		require.EqualValues(mt, "music", filter.Lookup("category").StringValue())
+	})
+
+	mt.Run("get server problems", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+		_, err := mongo.GetHeld("music")
+		require.Error(mt, err)
+	})
+}
+
+func TestPosts_GetKarma(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("ok", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "postDB.postDB", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: nil}, {Key: "post", Value: 5}, {Key: "comment", Value: 3}}))
+		karma, err := mongo.GetKarma("1")
+		require.NoError(mt, err)
+		require.EqualValues(mt, itemdata.Karma{Post: 5, Comment: 3}, karma)
+		stages, _ := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Values()
+		require.False(mt, stages[0].Document().Lookup("$match", "status", "$exists").Boolean())
+	})
+
+	mt.Run("no items", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "postDB.postDB", mtest.FirstBatch))
+		karma, err := mongo.GetKarma("1")
+		require.NoError(mt, err)
+		require.EqualValues(mt, itemdata.Karma{}, karma)
+	})
+
+	mt.Run("get server problems", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+		_, err := mongo.GetKarma("1")
+		require.Error(mt, err)
+	})
+}
+
+func TestPosts_GetUserComments(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("ok", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "postDB.postDB", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "p1"},
+			{Key: "title", Value: "first"},
+			{Key: "category", Value: "music"},
+			{Key: "comments", Value: bson.D{{Key: "id", Value: "c1"}, {Key: "body", Value: "hi"},
+				{Key: "author", Value: bson.D{{Key: "username", Value: "bob"}}}}},
+		}))
+		items, err := mongo.GetUserComments("bob", itemdata.ListOptions{Limit: 10})
+		require.NoError(mt, err)
+		require.Len(mt, items, 1)
+		require.EqualValues(mt, itemdata.Activity{Kind: itemdata.ActivityComment, PostID: "p1", CommentID: "c1",
+			Community: "music", Title: "first", Body: "hi", Author: itemdata.Author{Username: "bob"}}, items[0])
+	})
+
+	mt.Run("get server problems", func(mt *mtest.T) {
+		mongo := NewItemDataMongo(mt.DB.Collection("postDB"), context.Background())
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+		_, err := mongo.GetUserComments("bob", itemdata.ListOptions{})
+		require.Error(mt, err)
+	})
+}
@@ -11,30 +11,61 @@ type Votes struct {
 }
 
 type Post struct {
-	ID               string    `json:"id" bson:"_id"`
-	Ath              Author    `json:"author" bson:"author"`
-	Comments         []Comment `json:"comments" bson:"comments"`
-	Cat              string    `json:"category" valid:"in(music|funny|videos|programming|news|fashion)" bson:"category"`
-	Score            int       `json:"score" bson:"score"`
-	Type             string    `json:"type" valid:"in(text|link)" bson:"type"`
-	Title            string    `json:"title" bson:"title"`
-	Created          string    `json:"created" bson:"created"`
-	UpvotePercentage int       `json:"upvotePercentage" bson:"upvotePercentage"`
-	Views            int64     `json:"views" bson:"views"`
-	Text             string    `json:"-" bson:"text"`
-	Vote             []Votes   `json:"votes" bson:"vote"`
+	ID               string     `json:"id" bson:"_id"`
+	Ath              Author     `json:"author" bson:"author"`
+	Comments         []Comment  `json:"comments" bson:"comments"`
+	Cat              string     `json:"category" bson:"category"`
+	Score            int        `json:"score" bson:"score"`
+	Type             string     `json:"type" valid:"in(text|link|image)" bson:"type"`
+	Title            string     `json:"title" bson:"title"`
+	Created          string     `json:"created" bson:"created"`
+	UpvotePercentage int        `json:"upvotePercentage" bson:"upvotePercentage"`
+	Views            int64      `json:"views" bson:"views"`
+	Text             string     `json:"-" bson:"text"`
+	Vote             []Votes    `json:"votes" bson:"vote"`
+	Ups              int        `json:"-" bson:"ups"`
+	Downs            int        `json:"-" bson:"downs"`
+	Hot              float64    `json:"-" bson:"hot"`
+	Controversy      float64    `json:"-" bson:"controversy"`
+	Rank             float64    `json:"-" bson:"rank,omitempty"`
+	Edited           string     `json:"edited,omitempty" bson:"edited,omitempty"`
+	Locked           bool       `json:"locked,omitempty" bson:"locked,omitempty"`
+	Pinned           bool       `json:"pinned,omitempty" bson:"pinned,omitempty"`
+	Status           string     `json:"status,omitempty" bson:"status,omitempty"`
+	Flair            string     `json:"flair,omitempty" bson:"flair,omitempty"`
+	Image            *Image     `json:"image,omitempty" bson:"image,omitempty"`
+	Revisions        []Revision `json:"-" bson:"revisions,omitempty"`
+	Version          int64      `json:"-" bson:"version"`
+}
+
+// Image of an image post, the text of such a post is its caption.
+type Image struct {
+	URL          string   `json:"url" bson:"url"`
+	ThumbnailURL string   `json:"thumbnail_url" bson:"thumbnail_url"`
+	ContentType  string   `json:"content_type" bson:"content_type"`
+	Width        int      `json:"width" bson:"width"`
+	Height       int      `json:"height" bson:"height"`
+	Keys         []string `json:"-" bson:"keys"`
 }
 
 type Comment struct {
-	Ath     Author `json:"author" bson:"author"`
-	Body    string `json:"body" bson:"body"`
-	Created string `json:"created" bson:"created"`
-	ID      string `json:"id" bson:"id"`
+	Ath      Author    `json:"author" bson:"author"`
+	Body     string    `json:"body" bson:"body"`
+	Created  string    `json:"created" bson:"created"`
+	ID       string    `json:"id" bson:"id"`
+	Score    int       `json:"score" bson:"score"`
+	Votes    []Votes   `json:"votes,omitempty" bson:"votes,omitempty"`
+	ParentID string    `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	Edited   string    `json:"edited,omitempty" bson:"edited,omitempty"`
+	Deleted  bool      `json:"deleted,omitempty" bson:"deleted,omitempty"`
+	Status   string    `json:"status,omitempty" bson:"status,omitempty"`
+	Replies  []Comment `json:"replies,omitempty" bson:"-"`
 }
 
 type CreatePost struct {
-	Cat   string `json:"category" valid:"in(music|funny|videos|programming|news|fashion),required"`
+	Cat   string `json:"category" valid:",required"`
 	Title string `json:"title,required"`
-	Type  string `json:"type" valid:"in(text|link),required"`
+	Type  string `json:"type" valid:"in(text|link|image),required"`
 	Text  string `json:"-"`
+	Image []byte `json:"-"`
 }
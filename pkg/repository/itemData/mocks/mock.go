@@ -34,6 +34,36 @@ func (m *MockItemData) EXPECT() *MockItemDataMockRecorder {
 	return m.recorder
 }
 
+// ApplyCommentVote mocks base method.
+func (m *MockItemData) ApplyCommentVote(postID, commentID, userID string, vote int) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyCommentVote", postID, commentID, userID, vote)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyCommentVote indicates an expected call of ApplyCommentVote.
+func (mr *MockItemDataMockRecorder) ApplyCommentVote(postID, commentID, userID, vote interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyCommentVote", reflect.TypeOf((*MockItemData)(nil).ApplyCommentVote), postID, commentID, userID, vote)
+}
+
+// ApplyVote mocks base method.
+func (m *MockItemData) ApplyVote(postID, userID string, vote int) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyVote", postID, userID, vote)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyVote indicates an expected call of ApplyVote.
+func (mr *MockItemDataMockRecorder) ApplyVote(postID, userID, vote interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyVote", reflect.TypeOf((*MockItemData)(nil).ApplyVote), postID, userID, vote)
+}
+
 // CreatePost mocks base method.
 func (m *MockItemData) CreatePost(post itemdata.Post) (itemdata.Post, error) {
 	m.ctrl.T.Helper()
@@ -64,33 +94,93 @@ func (mr *MockItemDataMockRecorder) DeletePost(postID interface{}) *gomock.Call
 }
 
 // GetCategory mocks base method.
-func (m *MockItemData) GetCategory(category string) ([]itemdata.Post, error) {
+func (m *MockItemData) GetCategory(category string, opts itemdata.ListOptions) ([]itemdata.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCategory", category)
+	ret := m.ctrl.Call(m, "GetCategory", category, opts)
 	ret0, _ := ret[0].([]itemdata.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCategory indicates an expected call of GetCategory.
-func (mr *MockItemDataMockRecorder) GetCategory(category interface{}) *gomock.Call {
+func (mr *MockItemDataMockRecorder) GetCategory(category, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockItemData)(nil).GetCategory), category, opts)
+}
+
+// GetFeed mocks base method.
+func (m *MockItemData) GetFeed(categories, authorIDs []string, opts itemdata.ListOptions) ([]itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", categories, authorIDs, opts)
+	ret0, _ := ret[0].([]itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockItemDataMockRecorder) GetFeed(categories, authorIDs, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockItemData)(nil).GetFeed), categories, authorIDs, opts)
+}
+
+// GetHeld mocks base method.
+func (m *MockItemData) GetHeld(category string) ([]itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeld", category)
+	ret0, _ := ret[0].([]itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeld indicates an expected call of GetHeld.
+func (mr *MockItemDataMockRecorder) GetHeld(category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeld", reflect.TypeOf((*MockItemData)(nil).GetHeld), category)
+}
+
+// GetKarma mocks base method.
+func (m *MockItemData) GetKarma(userID string) (itemdata.Karma, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKarma", userID)
+	ret0, _ := ret[0].(itemdata.Karma)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKarma indicates an expected call of GetKarma.
+func (mr *MockItemDataMockRecorder) GetKarma(userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockItemData)(nil).GetCategory), category)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKarma", reflect.TypeOf((*MockItemData)(nil).GetKarma), userID)
 }
 
 // GetName mocks base method.
-func (m *MockItemData) GetName(login string) ([]itemdata.Post, error) {
+func (m *MockItemData) GetName(login string, opts itemdata.ListOptions) ([]itemdata.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetName", login)
+	ret := m.ctrl.Call(m, "GetName", login, opts)
 	ret0, _ := ret[0].([]itemdata.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetName indicates an expected call of GetName.
-func (mr *MockItemDataMockRecorder) GetName(login interface{}) *gomock.Call {
+func (mr *MockItemDataMockRecorder) GetName(login, opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetName", reflect.TypeOf((*MockItemData)(nil).GetName), login)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetName", reflect.TypeOf((*MockItemData)(nil).GetName), login, opts)
+}
+
+// GetPinned mocks base method.
+func (m *MockItemData) GetPinned(category string) ([]itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPinned", category)
+	ret0, _ := ret[0].([]itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPinned indicates an expected call of GetPinned.
+func (mr *MockItemDataMockRecorder) GetPinned(category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPinned", reflect.TypeOf((*MockItemData)(nil).GetPinned), category)
 }
 
 // GetPostID mocks base method.
@@ -109,18 +199,78 @@ func (mr *MockItemDataMockRecorder) GetPostID(id interface{}) *gomock.Call {
 }
 
 // GetPosts mocks base method.
-func (m *MockItemData) GetPosts() ([]itemdata.Post, error) {
+func (m *MockItemData) GetPosts(opts itemdata.ListOptions) ([]itemdata.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPosts")
+	ret := m.ctrl.Call(m, "GetPosts", opts)
 	ret0, _ := ret[0].([]itemdata.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPosts indicates an expected call of GetPosts.
-func (mr *MockItemDataMockRecorder) GetPosts() *gomock.Call {
+func (mr *MockItemDataMockRecorder) GetPosts(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockItemData)(nil).GetPosts), opts)
+}
+
+// GetUserComments mocks base method.
+func (m *MockItemData) GetUserComments(login string, opts itemdata.ListOptions) ([]itemdata.Activity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserComments", login, opts)
+	ret0, _ := ret[0].([]itemdata.Activity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserComments indicates an expected call of GetUserComments.
+func (mr *MockItemDataMockRecorder) GetUserComments(login, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserComments", reflect.TypeOf((*MockItemData)(nil).GetUserComments), login, opts)
+}
+
+// RemoveCommentVote mocks base method.
+func (m *MockItemData) RemoveCommentVote(postID, commentID, userID string) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCommentVote", postID, commentID, userID)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveCommentVote indicates an expected call of RemoveCommentVote.
+func (mr *MockItemDataMockRecorder) RemoveCommentVote(postID, commentID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCommentVote", reflect.TypeOf((*MockItemData)(nil).RemoveCommentVote), postID, commentID, userID)
+}
+
+// RemoveVote mocks base method.
+func (m *MockItemData) RemoveVote(postID, userID string) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveVote", postID, userID)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveVote indicates an expected call of RemoveVote.
+func (mr *MockItemDataMockRecorder) RemoveVote(postID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveVote", reflect.TypeOf((*MockItemData)(nil).RemoveVote), postID, userID)
+}
+
+// Search mocks base method.
+func (m *MockItemData) Search(query itemdata.SearchQuery) ([]itemdata.SearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", query)
+	ret0, _ := ret[0].([]itemdata.SearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockItemDataMockRecorder) Search(query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockItemData)(nil).GetPosts))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockItemData)(nil).Search), query)
 }
 
 // SetPost mocks base method.
@@ -0,0 +1,109 @@
+package itemdata
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	DefaultPageSize = 25
+	MaxPageSize     = 100
+	// MaxPinned is how many posts a community can pin above its listing.
+	MaxPinned = 2
+)
+
+// Cursor points right after the last post of a page. It keeps the rank key,
+// creation time and id of that post instead of a position, so it stays valid
+// when posts are added or deleted. AsOf is the time the first page was read;
+// posts created later are left out so they never shift the following pages,
+// and time based ranks are computed for that moment.
+//
+// Only the new listing is stable under votes, its key is the creation time.
+// The other sorts rank by the current score: a post voted across the cursor
+// between two requests is shown again or skipped.
+type Cursor struct {
+	Sort    string  `json:"o"`
+	Key     float64 `json:"k"`
+	Created string  `json:"c"`
+	ID      string  `json:"i"`
+	AsOf    string  `json:"t"`
+}
+
+// ListOptions select a page of a listing. Window leaves only posts younger than
+// that at AsOf, zero means all time. Viewer is the user the listing is shown
+// to, the service turns the posts they hid into Exclude, a set of post ids.
+type ListOptions struct {
+	Limit   int
+	After   Cursor
+	Sort    string
+	Window  time.Duration
+	Viewer  string
+	Exclude map[string]bool
+}
+
+type PostPage struct {
+	Posts []Post
+	Next  string
+}
+
+// CursorAfter expects the post to come from a listing, which fills Post.Rank.
+func CursorAfter(post Post, sort, asOf string) Cursor {
+	return Cursor{Sort: sort, Key: post.Rank, Created: post.Created, ID: post.ID, AsOf: asOf}
+}
+
+func (c Cursor) Encode() string {
+	buf, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, errors.New("invalid cursor")
+	}
+	if err = json.Unmarshal(buf, &c); err != nil || c.ID == "" || c.AsOf == "" {
+		return Cursor{}, errors.New("invalid cursor")
+	}
+	return c, nil
+}
+
+func (opts ListOptions) AsOf() time.Time {
+	if opts.After.AsOf == "" {
+		return time.Now().UTC()
+	}
+	return parseCreated(opts.After.AsOf)
+}
+
+// Since is the creation time of the oldest post in the listing, empty if there is no limit.
+func (opts ListOptions) Since() string {
+	window := opts.Window
+	if opts.Sort == SortRising {
+		window = RisingWindow
+	}
+	if window == 0 {
+		return ""
+	}
+	return opts.AsOf().Add(-window).Format(time.RFC3339)
+}
+
+// Admits reports whether the post with the given rank key belongs to the
+// listing continued from the cursor.
+func (opts ListOptions) Admits(post Post, key float64) bool {
+	c := opts.After
+	if c.AsOf != "" && post.Created > c.AsOf {
+		return false
+	}
+	if opts.Exclude[post.ID] {
+		return false
+	}
+	if since := opts.Since(); since != "" && post.Created < since {
+		return false
+	}
+	if c.ID == "" {
+		return true
+	}
+	return LessRanked(opts.Sort, c.Key, Post{Created: c.Created, ID: c.ID}, key, post)
+}
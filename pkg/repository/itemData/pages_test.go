@@ -0,0 +1,21 @@
+package itemdata
+
+import (
+	"testing"
+)
+
+func TestCursor_Encode(t *testing.T) {
+	cursor := Cursor{Sort: SortHot, Key: -2.25, Created: "2022-11-04T17:55:14Z", ID: "abc", AsOf: "2022-11-05T00:00:00Z"}
+	res, err := DecodeCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if res != cursor {
+		t.Errorf("results not match, want %v, have %v", cursor, res)
+	}
+	for _, el := range []string{"", "???", Cursor{ID: "abc"}.Encode()} {
+		if _, err = DecodeCursor(el); err == nil {
+			t.Errorf("cursor accepted: %q", el)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package itemdata
+
+import (
+	"errors"
+)
+
+// Revision keeps a replaced version of a post text or, with CommentID set, of a comment body.
+type Revision struct {
+	CommentID string `json:"comment_id,omitempty" bson:"comment_id,omitempty"`
+	Body      string `json:"body" bson:"body"`
+	Created   string `json:"created" bson:"created"`
+}
+
+// PostRevisions are the replaced versions of the post text, without the comments.
+func (p *Post) PostRevisions() []Revision {
+	return p.revisions("")
+}
+
+// CommentRevisions are the replaced versions of the comment body.
+func (p *Post) CommentRevisions(id string) []Revision {
+	return p.revisions(id)
+}
+
+func (p *Post) revisions(commentID string) []Revision {
+	res := make([]Revision, 0, len(p.Revisions))
+	for _, el := range p.Revisions {
+		if el.CommentID == commentID {
+			res = append(res, el)
+		}
+	}
+	return res
+}
+
+// Edit replaces the text of the post and saves the previous one as a revision.
+func (p *Post) Edit(text, now string) {
+	created := p.Created
+	if p.Edited != "" {
+		created = p.Edited
+	}
+	p.Revisions = append(p.Revisions, Revision{Body: p.Text, Created: created})
+	p.Text = text
+	p.Edited = now
+}
+
+// EditComment replaces the body of the comment and saves the previous one as a revision.
+func (p *Post) EditComment(id, body, now string) error {
+	for i, el := range p.Comments {
+		if el.ID != id || el.Deleted {
+			continue
+		}
+		created := el.Created
+		if el.Edited != "" {
+			created = el.Edited
+		}
+		p.Revisions = append(p.Revisions, Revision{CommentID: id, Body: el.Body, Created: created})
+		p.Comments = append([]Comment(nil), p.Comments...)
+		p.Comments[i].Body = body
+		p.Comments[i].Edited = now
+		return nil
+	}
+	return errors.New("invalid comment id")
+}
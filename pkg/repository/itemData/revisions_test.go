@@ -0,0 +1,37 @@
+package itemdata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPost_Edit(t *testing.T) {
+	post := Post{
+		Text:     "first",
+		Created:  "2022-11-04T17:55:14Z",
+		Comments: []Comment{{ID: "1", Body: "hi", Created: "2022-11-04T17:56:14Z"}},
+	}
+	shared := post
+	post.Edit("second", "2022-11-04T18:00:00Z")
+	post.Edit("third", "2022-11-04T19:00:00Z")
+	if err := post.EditComment("1", "hello", "2022-11-04T20:00:00Z"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []Revision{
+		{Body: "first", Created: "2022-11-04T17:55:14Z"},
+		{Body: "second", Created: "2022-11-04T18:00:00Z"},
+		{CommentID: "1", Body: "hi", Created: "2022-11-04T17:56:14Z"},
+	}
+	if !reflect.DeepEqual(post.Revisions, want) {
+		t.Errorf("results not match, want %v, have %v", want, post.Revisions)
+	}
+	if post.Text != "third" || post.Edited != "2022-11-04T19:00:00Z" || post.Comments[0].Edited != "2022-11-04T20:00:00Z" {
+		t.Errorf("unexpected post %v", post)
+	}
+	if shared.Comments[0].Body != "hi" {
+		t.Errorf("edit changed a copy of the post")
+	}
+	if err := post.EditComment("2", "hello", ""); err == nil {
+		t.Errorf("missing comment edited")
+	}
+}
@@ -0,0 +1,34 @@
+package itemdata
+
+// SavedItem is a saved post or comment as the owner of the list sees it.
+type SavedItem struct {
+	PostID    string `json:"post_id"`
+	CommentID string `json:"comment_id,omitempty"`
+	Community string `json:"community"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	Author    Author `json:"author"`
+	Created   string `json:"created"`
+	Saved     string `json:"saved"`
+}
+
+// SavedItem describes the post or, with commentID, one of its comments. It is
+// false when the comment is gone or the content is not public.
+func (p Post) SavedItem(commentID, saved string) (SavedItem, bool) {
+	if p.Status != "" {
+		return SavedItem{}, false
+	}
+	res := SavedItem{PostID: p.ID, Community: p.Cat, Title: p.Title, Saved: saved}
+	if commentID == "" {
+		res.Body, res.Author, res.Created = p.Text, p.Ath, p.Created
+		return res, true
+	}
+	for _, el := range p.PublicComments() {
+		if el.ID == commentID && !el.Deleted {
+			res.CommentID = commentID
+			res.Body, res.Author, res.Created = el.Body, el.Ath, el.Created
+			return res, true
+		}
+	}
+	return SavedItem{}, false
+}
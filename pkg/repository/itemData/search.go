@@ -0,0 +1,106 @@
+package itemdata
+
+import (
+	"gitlab.com/vk-go/lectures-2022-2/pkg/search"
+	"sort"
+	"time"
+)
+
+const snippetWidth = 160
+
+// titleWeight makes a match in the title count more than in the text.
+const titleWeight = 3
+
+// SearchQuery filters hits by the category and type of the post, the author
+// of the hit and its creation time between From and To (RFC 3339, inclusive).
+type SearchQuery struct {
+	Text     string
+	Category string
+	Author   string
+	Type     string
+	From     string
+	To       string
+	Limit    int
+}
+
+// SearchHit is a post or, with CommentID set, a comment matching the query.
+type SearchHit struct {
+	PostID    string  `json:"post_id"`
+	CommentID string  `json:"comment_id,omitempty"`
+	Title     string  `json:"title"`
+	Category  string  `json:"category"`
+	Type      string  `json:"type"`
+	Author    Author  `json:"author"`
+	Created   string  `json:"created"`
+	Snippet   string  `json:"snippet"`
+	Score     float64 `json:"-"`
+}
+
+func (q SearchQuery) Terms() []string {
+	return search.Tokenize(q.Text)
+}
+
+// MatchesPost checks the filters which depend only on the post.
+func (q SearchQuery) MatchesPost(post Post) bool {
+	return (q.Category == "" || post.Cat == q.Category) && (q.Type == "" || post.Type == q.Type)
+}
+
+func (q SearchQuery) matchesHit(author Author, created string) bool {
+	if q.Author != "" && author.Username != q.Author {
+		return false
+	}
+	return (q.From == "" || created >= q.From) && (q.To == "" || created <= q.To)
+}
+
+// SearchHits scores the post and its comments, both backends only select the candidates.
+func (p Post) SearchHits(q SearchQuery, now time.Time) []SearchHit {
+	terms := q.Terms()
+	res := make([]SearchHit, 0, 1)
+	hit := func(author Author, created string) SearchHit {
+		return SearchHit{PostID: p.ID, Title: p.Title, Category: p.Cat, Type: p.Type, Author: author, Created: created}
+	}
+	if p.Status != "" {
+		return res
+	}
+	if q.matchesHit(p.Ath, p.Created) {
+		text := search.Relevance(terms, p.Text)
+		if relevance := titleWeight*search.Relevance(terms, p.Title) + text; relevance > 0 {
+			el := hit(p.Ath, p.Created)
+			el.Score = search.Decay(relevance, now.Sub(parseCreated(p.Created)))
+			el.Snippet = search.Snippet(p.Text, terms, snippetWidth)
+			if text == 0 {
+				el.Snippet = search.Snippet(p.Title, terms, snippetWidth)
+			}
+			res = append(res, el)
+		}
+	}
+	for _, comm := range p.PublicComments() {
+		if comm.Deleted || !q.matchesHit(comm.Ath, comm.Created) {
+			continue
+		}
+		relevance := search.Relevance(terms, comm.Body)
+		if relevance == 0 {
+			continue
+		}
+		el := hit(comm.Ath, comm.Created)
+		el.CommentID = comm.ID
+		el.Score = search.Decay(relevance, now.Sub(parseCreated(comm.Created)))
+		el.Snippet = search.Snippet(comm.Body, terms, snippetWidth)
+		res = append(res, el)
+	}
+	return res
+}
+
+// RankHits orders hits by relevance decayed by age and keeps the first limit of them.
+func RankHits(hits []SearchHit, limit int) []SearchHit {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Created > hits[j].Created
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
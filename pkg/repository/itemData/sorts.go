@@ -0,0 +1,70 @@
+package itemdata
+
+import (
+	"gitlab.com/vk-go/lectures-2022-2/pkg/ranking"
+	"time"
+)
+
+const (
+	SortHot           = "hot"
+	SortNew           = "new"
+	SortTop           = "top"
+	SortRising        = "rising"
+	SortControversial = "controversial"
+)
+
+// RisingWindow limits the rising listing to young posts.
+const RisingWindow = 24 * time.Hour
+
+var sorts = map[string]bool{SortHot: true, SortNew: true, SortTop: true, SortRising: true, SortControversial: true}
+
+// Windows are the values of ?t= for the top and controversial listings.
+var Windows = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+	"year":  365 * 24 * time.Hour,
+	"all":   0,
+}
+
+func ValidSort(name string) bool {
+	return sorts[name]
+}
+
+// RankKey is the value a listing is ordered by, higher first. Posts with equal
+// keys follow in creation order, see OldestFirst, and then by id.
+func RankKey(sort string, post Post, asOf time.Time) float64 {
+	switch sort {
+	case SortHot:
+		return post.Hot
+	case SortRising:
+		return ranking.Rising(post.Score, asOf.Sub(parseCreated(post.Created)))
+	case SortControversial:
+		return post.Controversy
+	case SortNew:
+		return 0
+	default:
+		return float64(post.Score)
+	}
+}
+
+// OldestFirst reports how posts with equal keys are ordered, only top keeps older posts first.
+func OldestFirst(sort string) bool {
+	return sort == SortTop || sort == ""
+}
+
+// LessRanked reports whether the post a goes before b in the listing.
+func LessRanked(sort string, keyA float64, a Post, keyB float64, b Post) bool {
+	if keyA != keyB {
+		return keyA > keyB
+	}
+	if a.Created != b.Created {
+		return (a.Created < b.Created) == OldestFirst(sort)
+	}
+	return a.ID < b.ID
+}
+
+func parseCreated(created string) time.Time {
+	res, _ := time.Parse(time.RFC3339, created)
+	return res
+}
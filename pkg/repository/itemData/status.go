@@ -0,0 +1,108 @@
+package itemdata
+
+import (
+	"errors"
+)
+
+// A post or comment with a status is hidden from everybody but the moderators
+// until one of them approves it.
+const (
+	StatusHeld    = "held"
+	StatusRemoved = "removed"
+)
+
+// HeldItem is a post or a comment waiting for a moderator.
+type HeldItem struct {
+	PostID    string `json:"post_id"`
+	CommentID string `json:"comment_id,omitempty"`
+	Community string `json:"community"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	Author    Author `json:"author"`
+	Created   string `json:"created"`
+}
+
+// PublicComments leaves out the held and removed comments together with their replies.
+func (p Post) PublicComments() []Comment {
+	hidden := make(map[string]bool)
+	for _, el := range p.Comments {
+		if el.Status != "" {
+			hidden[el.ID] = true
+		}
+	}
+	return p.CommentsWithout(hidden)
+}
+
+// CommentsWithout leaves out the comments with the ids together with their replies.
+func (p Post) CommentsWithout(hidden map[string]bool) []Comment {
+	if len(hidden) == 0 {
+		return p.Comments
+	}
+	res := make([]Comment, 0, len(p.Comments))
+	for _, el := range p.Comments {
+		if !hidden[el.ID] && !p.underHidden(el, hidden) {
+			res = append(res, el)
+		}
+	}
+	return res
+}
+
+// publicComment reports whether neither the comment nor any of its parents is held or removed.
+func (p Post) publicComment(comm Comment) bool {
+	for depth := 0; depth <= len(p.Comments); depth++ {
+		if comm.Status != "" {
+			return false
+		}
+		if comm.ParentID == "" {
+			return true
+		}
+		parent, ok := p.FindComment(comm.ParentID)
+		if !ok {
+			return true
+		}
+		comm = parent
+	}
+	return true
+}
+
+func (p Post) underHidden(comm Comment, hidden map[string]bool) bool {
+	for depth := 0; comm.ParentID != "" && depth <= len(p.Comments); depth++ {
+		if hidden[comm.ParentID] {
+			return true
+		}
+		parent, ok := p.FindComment(comm.ParentID)
+		if !ok {
+			return false
+		}
+		comm = parent
+	}
+	return false
+}
+
+// HeldItems lists the held post itself and its held comments.
+func (p Post) HeldItems() []HeldItem {
+	res := make([]HeldItem, 0, 1)
+	if p.Status == StatusHeld {
+		res = append(res, HeldItem{PostID: p.ID, Community: p.Cat, Title: p.Title, Body: p.Text,
+			Author: p.Ath, Created: p.Created})
+	}
+	for _, el := range p.Comments {
+		if el.Status == StatusHeld && !el.Deleted {
+			res = append(res, HeldItem{PostID: p.ID, CommentID: el.ID, Community: p.Cat, Title: p.Title,
+				Body: el.Body, Author: el.Ath, Created: el.Created})
+		}
+	}
+	return res
+}
+
+func (p *Post) SetCommentStatus(id, status string) error {
+	for i, el := range p.Comments {
+		if el.ID != id || el.Deleted {
+			continue
+		}
+		p.Comments = append([]Comment(nil), p.Comments...)
+		p.Comments[i].Status = status
+		return nil
+	}
+	return errors.New("invalid comment id")
+}
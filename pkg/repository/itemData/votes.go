@@ -0,0 +1,71 @@
+package itemdata
+
+import (
+	"gitlab.com/vk-go/lectures-2022-2/pkg/ranking"
+)
+
+// SetVote replaces the vote userID gave to the post and recounts the score.
+func (p *Post) SetVote(userID string, vote int) {
+	p.Vote, _ = withoutVote(p.Vote, userID)
+	p.Vote = append(p.Vote, Votes{User: userID, Vote: vote})
+	p.recount()
+}
+
+// DropVote removes the vote userID gave to the post and reports whether there was one.
+func (p *Post) DropVote(userID string) bool {
+	var ok bool
+	p.Vote, ok = withoutVote(p.Vote, userID)
+	p.recount()
+	return ok
+}
+
+func (p *Post) recount() {
+	p.Ups, p.Downs = countVotes(p.Vote)
+	p.Score = p.Ups - p.Downs
+	p.UpvotePercentage = 0
+	if len(p.Vote) != 0 {
+		p.UpvotePercentage = (p.Ups * 100) / len(p.Vote)
+	}
+	p.Hot = ranking.Hot(p.Score, parseCreated(p.Created))
+	p.Controversy = ranking.Controversy(p.Ups, p.Downs)
+}
+
+// SetVote replaces the vote userID gave to the comment and recounts the score.
+func (c *Comment) SetVote(userID string, vote int) {
+	c.Votes, _ = withoutVote(c.Votes, userID)
+	c.Votes = append(c.Votes, Votes{User: userID, Vote: vote})
+	c.recount()
+}
+
+// DropVote removes the vote userID gave to the comment and reports whether there was one.
+func (c *Comment) DropVote(userID string) bool {
+	var ok bool
+	c.Votes, ok = withoutVote(c.Votes, userID)
+	c.recount()
+	return ok
+}
+
+func (c *Comment) recount() {
+	ups, downs := countVotes(c.Votes)
+	c.Score = ups - downs
+}
+
+func withoutVote(votes []Votes, userID string) ([]Votes, bool) {
+	for i, el := range votes {
+		if el.User == userID {
+			return append(votes[:i:i], votes[i+1:]...), true
+		}
+	}
+	return votes, false
+}
+
+func countVotes(votes []Votes) (ups, downs int) {
+	for _, el := range votes {
+		if el.Vote == 1 {
+			ups++
+		} else if el.Vote == -1 {
+			downs++
+		}
+	}
+	return ups, downs
+}
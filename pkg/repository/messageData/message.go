@@ -0,0 +1,25 @@
+package messagedata
+
+const MaxBodyLength = 10000
+
+// Message is a private message. All the messages between two users belong to
+// one conversation.
+type Message struct {
+	ID           string `json:"id"`
+	Conversation string `json:"conversation_id"`
+	FromID       string `json:"-"`
+	From         string `json:"from"`
+	ToID         string `json:"-"`
+	To           string `json:"to"`
+	Body         string `json:"body"`
+	Read         bool   `json:"read"`
+	Created      string `json:"created"`
+}
+
+// ConversationID is the same whichever of the two users sends the message.
+func ConversationID(userA, userB string) string {
+	if userB < userA {
+		userA, userB = userB, userA
+	}
+	return userA + "-" + userB
+}
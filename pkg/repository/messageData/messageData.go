@@ -0,0 +1,13 @@
+package messagedata
+
+type MessageData interface {
+	AddMessage(msg Message) (Message, error)
+	GetInbox(userID string, limit int, unreadOnly bool) ([]Message, error)
+	GetSent(userID string, limit int) ([]Message, error)
+	GetConversation(conversationID, userID string, limit int) ([]Message, error)
+	MarkConversationRead(conversationID, userID string) error
+	Block(userID, blockedID string) error
+	Unblock(userID, blockedID string) error
+	IsBlocked(userID, blockedID string) (bool, error)
+	GetBlocked(userID string) ([]string, error)
+}
@@ -0,0 +1,22 @@
+package messagedatamap
+
+import (
+	messagedata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/messageData"
+	"sync"
+)
+
+var _ messagedata.MessageData = (*messageDataMap)(nil)
+
+type messageDataMap struct {
+	data   []messagedata.Message
+	blocks map[string][]string
+	mux    *sync.RWMutex
+}
+
+func NewMessageDataMap() *messageDataMap {
+	return &messageDataMap{
+		data:   make([]messagedata.Message, 0, 10),
+		blocks: make(map[string][]string),
+		mux:    &sync.RWMutex{},
+	}
+}
@@ -0,0 +1,104 @@
+package messagedatamap
+
+import (
+	messagedata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/messageData"
+	"strconv"
+)
+
+func (dt *messageDataMap) AddMessage(msg messagedata.Message) (messagedata.Message, error) {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	msg.ID = strconv.Itoa(len(dt.data) + 1)
+	msg.Conversation = messagedata.ConversationID(msg.FromID, msg.ToID)
+	msg.Read = false
+	dt.data = append(dt.data, msg)
+	return msg, nil
+}
+
+// GetInbox returns the newest messages first.
+func (dt *messageDataMap) GetInbox(userID string, limit int, unreadOnly bool) ([]messagedata.Message, error) {
+	return dt.newest(func(msg messagedata.Message) bool {
+		return msg.ToID == userID && !(unreadOnly && msg.Read)
+	}, limit), nil
+}
+
+func (dt *messageDataMap) GetSent(userID string, limit int) ([]messagedata.Message, error) {
+	return dt.newest(func(msg messagedata.Message) bool { return msg.FromID == userID }, limit), nil
+}
+
+// GetConversation returns the last messages of the conversation oldest first,
+// nothing if the user does not take part in it.
+func (dt *messageDataMap) GetConversation(conversationID, userID string, limit int) ([]messagedata.Message, error) {
+	res := dt.newest(func(msg messagedata.Message) bool {
+		return msg.Conversation == conversationID && (msg.FromID == userID || msg.ToID == userID)
+	}, limit)
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res, nil
+}
+
+func (dt *messageDataMap) newest(match func(msg messagedata.Message) bool, limit int) []messagedata.Message {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	res := make([]messagedata.Message, 0, 10)
+	for i := len(dt.data) - 1; i >= 0 && len(res) < limit; i-- {
+		if match(dt.data[i]) {
+			res = append(res, dt.data[i])
+		}
+	}
+	return res
+}
+
+func (dt *messageDataMap) MarkConversationRead(conversationID, userID string) error {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	for i, el := range dt.data {
+		if el.Conversation == conversationID && el.ToID == userID {
+			dt.data[i].Read = true
+		}
+	}
+	return nil
+}
+
+func (dt *messageDataMap) Block(userID, blockedID string) error {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	for _, el := range dt.blocks[userID] {
+		if el == blockedID {
+			return nil
+		}
+	}
+	dt.blocks[userID] = append(dt.blocks[userID], blockedID)
+	return nil
+}
+
+func (dt *messageDataMap) Unblock(userID, blockedID string) error {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	blocked := dt.blocks[userID]
+	for i, el := range blocked {
+		if el == blockedID {
+			dt.blocks[userID] = append(blocked[:i:i], blocked[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (dt *messageDataMap) IsBlocked(userID, blockedID string) (bool, error) {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	for _, el := range dt.blocks[userID] {
+		if el == blockedID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (dt *messageDataMap) GetBlocked(userID string) ([]string, error) {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	return append(make([]string, 0, len(dt.blocks[userID])), dt.blocks[userID]...), nil
+}
@@ -0,0 +1,68 @@
+package messagedatamap
+
+import (
+	messagedata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/messageData"
+	"reflect"
+	"testing"
+)
+
+func TestMessages(t *testing.T) {
+	dt := NewMessageDataMap()
+	send := func(fromID, toID, body string) messagedata.Message {
+		msg, err := dt.AddMessage(messagedata.Message{FromID: fromID, ToID: toID, Body: body})
+		if err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+		return msg
+	}
+	first := send("1", "2", "hi")
+	reply := send("2", "1", "hello")
+	send("3", "2", "spam")
+	if first.Conversation != reply.Conversation {
+		t.Errorf("results not match, want %v, have %v", first.Conversation, reply.Conversation)
+	}
+
+	inbox, _ := dt.GetInbox("2", 10, false)
+	bodies := make([]string, 0, len(inbox))
+	for _, el := range inbox {
+		bodies = append(bodies, el.Body)
+	}
+	if want := []string{"spam", "hi"}; !reflect.DeepEqual(bodies, want) {
+		t.Errorf("results not match, want %v, have %v", want, bodies)
+	}
+
+	thread, _ := dt.GetConversation(first.Conversation, "1", 10)
+	if len(thread) != 2 || thread[0].Body != "hi" || thread[1].Body != "hello" {
+		t.Errorf("results not match, want %v, have %v", "hi, hello", thread)
+	}
+	if thread, _ = dt.GetConversation(first.Conversation, "3", 10); len(thread) != 0 {
+		t.Errorf("results not match, want %v, have %v", 0, len(thread))
+	}
+
+	if err := dt.MarkConversationRead(first.Conversation, "2"); err != nil {
+		t.Errorf("unexpected err: %s", err)
+	}
+	if unread, _ := dt.GetInbox("2", 10, true); len(unread) != 1 || unread[0].Body != "spam" {
+		t.Errorf("results not match, want %v, have %v", "spam", unread)
+	}
+	if unread, _ := dt.GetInbox("1", 10, true); len(unread) != 1 {
+		t.Errorf("results not match, want %v, have %v", 1, len(unread))
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	dt := NewMessageDataMap()
+	_ = dt.Block("1", "2")
+	_ = dt.Block("1", "2")
+	_ = dt.Block("1", "3")
+	if blocked, _ := dt.GetBlocked("1"); !reflect.DeepEqual(blocked, []string{"2", "3"}) {
+		t.Errorf("results not match, want %v, have %v", []string{"2", "3"}, blocked)
+	}
+	if blocked, _ := dt.IsBlocked("2", "1"); blocked {
+		t.Errorf("block is one way")
+	}
+	_ = dt.Unblock("1", "2")
+	if blocked, _ := dt.IsBlocked("1", "2"); blocked {
+		t.Errorf("still blocked after unblock")
+	}
+}
@@ -0,0 +1,16 @@
+package messagedatamysql
+
+import (
+	"database/sql"
+	messagedata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/messageData"
+)
+
+var _ messagedata.MessageData = (*MessageDataMySQL)(nil)
+
+type MessageDataMySQL struct {
+	db *sql.DB
+}
+
+func NewMessageDataMySQL(db *sql.DB) *MessageDataMySQL {
+	return &MessageDataMySQL{db: db}
+}
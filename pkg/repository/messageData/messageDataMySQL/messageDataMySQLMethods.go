@@ -0,0 +1,131 @@
+package messagedatamysql
+
+import (
+	"database/sql"
+	"errors"
+	messagedata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/messageData"
+	"strconv"
+	"time"
+)
+
+const selectMessage = "SELECT id, conversation, from_id, from_login, to_id, to_login, body, is_read, created FROM messages"
+
+func (dt *MessageDataMySQL) AddMessage(msg messagedata.Message) (messagedata.Message, error) {
+	created, err := time.Parse(time.RFC3339, msg.Created)
+	if err != nil {
+		return msg, err
+	}
+	msg.Conversation = messagedata.ConversationID(msg.FromID, msg.ToID)
+	res, err := dt.db.Exec("INSERT INTO messages (conversation, from_id, from_login, to_id, to_login, body, created) "+
+		"VALUES (?, ?, ?, ?, ?, ?, ?)", msg.Conversation, msg.FromID, msg.From, msg.ToID, msg.To, msg.Body, created)
+	if err != nil {
+		return msg, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return msg, err
+	}
+	msg.ID = strconv.FormatInt(id, 10)
+	msg.Read = false
+	return msg, nil
+}
+
+func (dt *MessageDataMySQL) GetInbox(userID string, limit int, unreadOnly bool) ([]messagedata.Message, error) {
+	query := selectMessage + " WHERE to_id = ?"
+	if unreadOnly {
+		query += " AND is_read = FALSE"
+	}
+	rows, err := dt.db.Query(query+" ORDER BY id DESC LIMIT ?", userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanMessages(rows)
+}
+
+func (dt *MessageDataMySQL) GetSent(userID string, limit int) ([]messagedata.Message, error) {
+	rows, err := dt.db.Query(selectMessage+" WHERE from_id = ? ORDER BY id DESC LIMIT ?", userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanMessages(rows)
+}
+
+// GetConversation returns the last messages of the conversation oldest first,
+// nothing if the user does not take part in it.
+func (dt *MessageDataMySQL) GetConversation(conversationID, userID string, limit int) ([]messagedata.Message, error) {
+	rows, err := dt.db.Query(selectMessage+" WHERE conversation = ? AND (from_id = ? OR to_id = ?) "+
+		"ORDER BY id DESC LIMIT ?", conversationID, userID, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	res, err := scanMessages(rows)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res, nil
+}
+
+func (dt *MessageDataMySQL) MarkConversationRead(conversationID, userID string) error {
+	_, err := dt.db.Exec("UPDATE messages SET is_read = TRUE WHERE conversation = ? AND to_id = ? AND is_read = FALSE",
+		conversationID, userID)
+	return err
+}
+
+func (dt *MessageDataMySQL) Block(userID, blockedID string) error {
+	_, err := dt.db.Exec("INSERT IGNORE INTO blocks (user_id, blocked_id) VALUES (?, ?)", userID, blockedID)
+	return err
+}
+
+func (dt *MessageDataMySQL) Unblock(userID, blockedID string) error {
+	_, err := dt.db.Exec("DELETE FROM blocks WHERE user_id = ? AND blocked_id = ?", userID, blockedID)
+	return err
+}
+
+func (dt *MessageDataMySQL) IsBlocked(userID, blockedID string) (bool, error) {
+	var found int
+	err := dt.db.QueryRow("SELECT 1 FROM blocks WHERE user_id = ? AND blocked_id = ?", userID, blockedID).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (dt *MessageDataMySQL) GetBlocked(userID string) ([]string, error) {
+	rows, err := dt.db.Query("SELECT blocked_id FROM blocks WHERE user_id = ? ORDER BY created", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make([]string, 0, 10)
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		res = append(res, strconv.Itoa(id))
+	}
+	return res, rows.Err()
+}
+
+func scanMessages(rows *sql.Rows) ([]messagedata.Message, error) {
+	defer rows.Close()
+	res := make([]messagedata.Message, 0, 10)
+	for rows.Next() {
+		var id, fromID, toID int
+		var created time.Time
+		var msg messagedata.Message
+		if err := rows.Scan(&id, &msg.Conversation, &fromID, &msg.From, &toID, &msg.To, &msg.Body, &msg.Read,
+			&created); err != nil {
+			return nil, err
+		}
+		msg.ID = strconv.Itoa(id)
+		msg.FromID = strconv.Itoa(fromID)
+		msg.ToID = strconv.Itoa(toID)
+		msg.Created = created.UTC().Format("2006-01-02T15:04:05Z07:00")
+		res = append(res, msg)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,90 @@
+package messagedatamysql
+
+import (
+	messagedata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/messageData"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMessages_AddMessage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewMessageDataMySQL(db)
+	created := time.Date(2022, 11, 4, 17, 55, 14, 0, time.UTC)
+	mock.ExpectExec("INSERT INTO messages").
+		WithArgs("1-2", "2", "bob", "1", "alice", "hi", created).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	msg, err := repo.AddMessage(messagedata.Message{FromID: "2", From: "bob", ToID: "1", To: "alice", Body: "hi",
+		Created: "2022-11-04T17:55:14Z"})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if msg.ID != "4" || msg.Conversation != "1-2" {
+		t.Errorf("results not match, want %v, have %v", "4 1-2", msg)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMessages_GetConversation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewMessageDataMySQL(db)
+	created := time.Date(2022, 11, 4, 17, 55, 14, 0, time.UTC)
+	columns := []string{"id", "conversation", "from_id", "from_login", "to_id", "to_login", "body", "is_read", "created"}
+	mock.ExpectQuery("SELECT (.+) FROM messages WHERE conversation = (.+) ORDER BY id DESC").
+		WithArgs("1-2", "1", "1", 500).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(5, "1-2", 1, "alice", 2, "bob", "hello", false, created).
+			AddRow(4, "1-2", 2, "bob", 1, "alice", "hi", true, created))
+	got, err := repo.GetConversation("1-2", "1", 500)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	want := []messagedata.Message{
+		{ID: "4", Conversation: "1-2", FromID: "2", From: "bob", ToID: "1", To: "alice", Body: "hi", Read: true,
+			Created: "2022-11-04T17:55:14Z"},
+		{ID: "5", Conversation: "1-2", FromID: "1", From: "alice", ToID: "2", To: "bob", Body: "hello",
+			Created: "2022-11-04T17:55:14Z"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results not match, want %v, have %v", want, got)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMessages_IsBlocked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewMessageDataMySQL(db)
+	mock.ExpectQuery("SELECT 1 FROM blocks").WithArgs("1", "2").
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	mock.ExpectQuery("SELECT 1 FROM blocks").WithArgs("2", "1").
+		WillReturnRows(sqlmock.NewRows([]string{"1"}))
+	if blocked, err := repo.IsBlocked("1", "2"); err != nil || !blocked {
+		t.Errorf("results not match, want %v, have %v (%v)", true, blocked, err)
+	}
+	if blocked, err := repo.IsBlocked("2", "1"); err != nil || blocked {
+		t.Errorf("results not match, want %v, have %v (%v)", false, blocked, err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
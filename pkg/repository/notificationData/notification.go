@@ -0,0 +1,25 @@
+package notificationdata
+
+const (
+	KindReply   = "reply"
+	KindMention = "mention"
+	KindRemoval = "removal"
+	KindReport  = "report_resolved"
+)
+
+// Notification tells a user about a reply to their post or comment, a mention
+// of their name, the removal of their content or what became of their report.
+// Actor is the user that caused it, Body is an excerpt of the comment, the
+// reason of the removal or the outcome of the report.
+type Notification struct {
+	ID        string `json:"id"`
+	UserID    string `json:"-"`
+	Kind      string `json:"kind"`
+	Actor     string `json:"actor,omitempty"`
+	PostID    string `json:"post_id"`
+	CommentID string `json:"comment_id,omitempty"`
+	Title     string `json:"title"`
+	Body      string `json:"body,omitempty"`
+	Read      bool   `json:"read"`
+	Created   string `json:"created"`
+}
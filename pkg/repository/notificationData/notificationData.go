@@ -0,0 +1,9 @@
+package notificationdata
+
+type NotificationData interface {
+	AddNotification(note Notification) (Notification, error)
+	GetNotifications(userID string, limit int, unreadOnly bool) ([]Notification, error)
+	CountUnread(userID string) (int, error)
+	MarkRead(userID, id string) error
+	MarkAllRead(userID string) error
+}
@@ -0,0 +1,20 @@
+package notificationdatamap
+
+import (
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	"sync"
+)
+
+var _ notificationdata.NotificationData = (*notificationDataMap)(nil)
+
+type notificationDataMap struct {
+	data []notificationdata.Notification
+	mux  *sync.RWMutex
+}
+
+func NewNotificationDataMap() *notificationDataMap {
+	return &notificationDataMap{
+		data: make([]notificationdata.Notification, 0, 10),
+		mux:  &sync.RWMutex{},
+	}
+}
@@ -0,0 +1,64 @@
+package notificationdatamap
+
+import (
+	"errors"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	"strconv"
+)
+
+func (dt *notificationDataMap) AddNotification(note notificationdata.Notification) (notificationdata.Notification, error) {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	note.ID = strconv.Itoa(len(dt.data) + 1)
+	note.Read = false
+	dt.data = append(dt.data, note)
+	return note, nil
+}
+
+// GetNotifications returns the newest notifications first.
+func (dt *notificationDataMap) GetNotifications(userID string, limit int, unreadOnly bool) ([]notificationdata.Notification, error) {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	res := make([]notificationdata.Notification, 0, 10)
+	for i := len(dt.data) - 1; i >= 0 && len(res) < limit; i-- {
+		if el := dt.data[i]; el.UserID == userID && !(unreadOnly && el.Read) {
+			res = append(res, el)
+		}
+	}
+	return res, nil
+}
+
+func (dt *notificationDataMap) CountUnread(userID string) (int, error) {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	count := 0
+	for _, el := range dt.data {
+		if el.UserID == userID && !el.Read {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (dt *notificationDataMap) MarkRead(userID, id string) error {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	for i, el := range dt.data {
+		if el.ID == id && el.UserID == userID {
+			dt.data[i].Read = true
+			return nil
+		}
+	}
+	return errors.New("invalid notification")
+}
+
+func (dt *notificationDataMap) MarkAllRead(userID string) error {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	for i, el := range dt.data {
+		if el.UserID == userID {
+			dt.data[i].Read = true
+		}
+	}
+	return nil
+}
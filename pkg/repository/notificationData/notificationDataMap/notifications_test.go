@@ -0,0 +1,51 @@
+package notificationdatamap
+
+import (
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	"reflect"
+	"testing"
+)
+
+func TestNotifications(t *testing.T) {
+	dt := NewNotificationDataMap()
+	for _, el := range []notificationdata.Notification{
+		{UserID: "1", Kind: notificationdata.KindReply, PostID: "p1", CommentID: "c1"},
+		{UserID: "2", Kind: notificationdata.KindMention, PostID: "p1", CommentID: "c1"},
+		{UserID: "1", Kind: notificationdata.KindRemoval, PostID: "p2"},
+	} {
+		if _, err := dt.AddNotification(el); err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+	}
+	if count, _ := dt.CountUnread("1"); count != 2 {
+		t.Errorf("results not match, want %v, have %v", 2, count)
+	}
+	if err := dt.MarkRead("1", "3"); err != nil {
+		t.Errorf("unexpected err: %s", err)
+	}
+	if err := dt.MarkRead("1", "2"); err == nil || err.Error() != "invalid notification" {
+		t.Errorf("results not match, want %v, have %v", "invalid notification", err)
+	}
+
+	notes, _ := dt.GetNotifications("1", 10, false)
+	res := make([]string, 0, len(notes))
+	for _, el := range notes {
+		res = append(res, el.Kind)
+	}
+	if want := []string{notificationdata.KindRemoval, notificationdata.KindReply}; !reflect.DeepEqual(res, want) {
+		t.Errorf("results not match, want %v, have %v", want, res)
+	}
+	if notes, _ = dt.GetNotifications("1", 10, true); len(notes) != 1 || notes[0].ID != "1" {
+		t.Errorf("results not match, want %v, have %v", "1", notes)
+	}
+
+	if err := dt.MarkAllRead("1"); err != nil {
+		t.Errorf("unexpected err: %s", err)
+	}
+	if count, _ := dt.CountUnread("1"); count != 0 {
+		t.Errorf("results not match, want %v, have %v", 0, count)
+	}
+	if count, _ := dt.CountUnread("2"); count != 1 {
+		t.Errorf("results not match, want %v, have %v", 1, count)
+	}
+}
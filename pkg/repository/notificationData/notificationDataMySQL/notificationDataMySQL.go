@@ -0,0 +1,16 @@
+package notificationdatamysql
+
+import (
+	"database/sql"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+)
+
+var _ notificationdata.NotificationData = (*NotificationDataMySQL)(nil)
+
+type NotificationDataMySQL struct {
+	db *sql.DB
+}
+
+func NewNotificationDataMySQL(db *sql.DB) *NotificationDataMySQL {
+	return &NotificationDataMySQL{db: db}
+}
@@ -0,0 +1,91 @@
+package notificationdatamysql
+
+import (
+	"database/sql"
+	"errors"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	"strconv"
+	"time"
+)
+
+const selectNotification = "SELECT id, user_id, kind, actor, post_id, comment_id, title, body, is_read, created " +
+	"FROM notifications"
+
+func (dt *NotificationDataMySQL) AddNotification(note notificationdata.Notification) (notificationdata.Notification, error) {
+	created, err := time.Parse(time.RFC3339, note.Created)
+	if err != nil {
+		return note, err
+	}
+	res, err := dt.db.Exec("INSERT INTO notifications (user_id, kind, actor, post_id, comment_id, title, body, created) "+
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		note.UserID, note.Kind, note.Actor, note.PostID, note.CommentID, note.Title, note.Body, created)
+	if err != nil {
+		return note, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return note, err
+	}
+	note.ID = strconv.FormatInt(id, 10)
+	note.Read = false
+	return note, nil
+}
+
+func (dt *NotificationDataMySQL) GetNotifications(userID string, limit int, unreadOnly bool) ([]notificationdata.Notification, error) {
+	query := selectNotification + " WHERE user_id = ?"
+	if unreadOnly {
+		query += " AND is_read = FALSE"
+	}
+	rows, err := dt.db.Query(query+" ORDER BY id DESC LIMIT ?", userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make([]notificationdata.Notification, 0, 10)
+	for rows.Next() {
+		var id, ownerID int
+		var created time.Time
+		var note notificationdata.Notification
+		if err = rows.Scan(&id, &ownerID, &note.Kind, &note.Actor, &note.PostID, &note.CommentID, &note.Title,
+			&note.Body, &note.Read, &created); err != nil {
+			return nil, err
+		}
+		note.ID = strconv.Itoa(id)
+		note.UserID = strconv.Itoa(ownerID)
+		note.Created = created.UTC().Format("2006-01-02T15:04:05Z07:00")
+		res = append(res, note)
+	}
+	return res, rows.Err()
+}
+
+func (dt *NotificationDataMySQL) CountUnread(userID string) (int, error) {
+	var count int
+	err := dt.db.QueryRow("SELECT COUNT(*) FROM notifications WHERE user_id = ? AND is_read = FALSE", userID).
+		Scan(&count)
+	return count, err
+}
+
+// MarkRead is a no-op for a notification that is already read, the second
+// query tells it apart from one that does not exist.
+func (dt *NotificationDataMySQL) MarkRead(userID, id string) error {
+	res, err := dt.db.Exec("UPDATE notifications SET is_read = TRUE WHERE id = ? AND user_id = ? AND is_read = FALSE",
+		id, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil || affected != 0 {
+		return err
+	}
+	var found int
+	err = dt.db.QueryRow("SELECT 1 FROM notifications WHERE id = ? AND user_id = ?", id, userID).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("invalid notification")
+	}
+	return err
+}
+
+func (dt *NotificationDataMySQL) MarkAllRead(userID string) error {
+	_, err := dt.db.Exec("UPDATE notifications SET is_read = TRUE WHERE user_id = ? AND is_read = FALSE", userID)
+	return err
+}
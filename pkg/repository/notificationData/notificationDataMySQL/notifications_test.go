@@ -0,0 +1,107 @@
+package notificationdatamysql
+
+import (
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNotifications_AddAndList(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewNotificationDataMySQL(db)
+	created := time.Date(2022, 11, 4, 17, 55, 14, 0, time.UTC)
+	note := notificationdata.Notification{UserID: "1", Kind: notificationdata.KindReply, Actor: "bob", PostID: "p1",
+		CommentID: "c1", Title: "first", Body: "hi", Created: "2022-11-04T17:55:14Z"}
+
+	mock.ExpectExec("INSERT INTO notifications").
+		WithArgs("1", "reply", "bob", "p1", "c1", "first", "hi", created).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	got, err := repo.AddNotification(note)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	note.ID = "5"
+	if !reflect.DeepEqual(got, note) {
+		t.Errorf("results not match, want %v, have %v", note, got)
+	}
+
+	columns := []string{"id", "user_id", "kind", "actor", "post_id", "comment_id", "title", "body", "is_read", "created"}
+	mock.ExpectQuery("SELECT (.+) FROM notifications WHERE user_id = (.+) AND is_read = FALSE ORDER BY id DESC").
+		WithArgs("1", 10).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(5, 1, "reply", "bob", "p1", "c1", "first", "hi", false, created))
+	notes, err := repo.GetNotifications("1", 10, true)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if want := []notificationdata.Notification{note}; !reflect.DeepEqual(notes, want) {
+		t.Errorf("results not match, want %v, have %v", want, notes)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestNotifications_MarkRead(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewNotificationDataMySQL(db)
+	testTable := []struct {
+		name          string
+		mockBehaviour func()
+		err           string
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func() {
+				mock.ExpectExec("UPDATE notifications SET is_read").WithArgs("5", "1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "already read",
+			mockBehaviour: func() {
+				mock.ExpectExec("UPDATE notifications SET is_read").WithArgs("5", "1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT 1 FROM notifications").WithArgs("5", "1").
+					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+			},
+		},
+		{
+			name: "not found",
+			mockBehaviour: func() {
+				mock.ExpectExec("UPDATE notifications SET is_read").WithArgs("5", "1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT 1 FROM notifications").WithArgs("5", "1").
+					WillReturnRows(sqlmock.NewRows([]string{"1"}))
+			},
+			err: "invalid notification",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehaviour()
+			err := repo.MarkRead("1", "5")
+			if testCase.err == "" && err != nil {
+				t.Errorf("unexpected err: %s", err)
+			}
+			if testCase.err != "" && (err == nil || err.Error() != testCase.err) {
+				t.Errorf("results not match, want %v, have %v", testCase.err, err)
+			}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
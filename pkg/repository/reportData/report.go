@@ -0,0 +1,30 @@
+package reportdata
+
+const (
+	StatusOpen     = "open"
+	StatusApproved = "approved"
+	StatusRemoved  = "removed"
+)
+
+// Report flags a post or, with CommentID set, a comment of the post.
+type Report struct {
+	ID         string `json:"id"`
+	PostID     string `json:"post_id"`
+	CommentID  string `json:"comment_id,omitempty"`
+	Community  string `json:"community"`
+	ReporterID string `json:"-"`
+	Reason     string `json:"reason"`
+	Status     string `json:"status"`
+	Created    string `json:"created"`
+	Resolved   string `json:"resolved,omitempty"`
+}
+
+// QueueItem groups the open reports of one post or comment.
+type QueueItem struct {
+	PostID    string   `json:"post_id"`
+	CommentID string   `json:"comment_id,omitempty"`
+	Community string   `json:"community"`
+	Count     int      `json:"count"`
+	Reasons   []string `json:"reasons"`
+	First     string   `json:"first_reported"`
+}
@@ -0,0 +1,9 @@
+package reportdata
+
+type ReportData interface {
+	AddReport(report Report) (Report, error)
+	GetQueue(community string, limit int) ([]QueueItem, error)
+	HasOpen(community, postID, commentID string) (bool, error)
+	Resolve(community, postID, commentID, status, resolved string) ([]Report, error)
+	GetByReporter(reporterID string, limit int) ([]Report, error)
+}
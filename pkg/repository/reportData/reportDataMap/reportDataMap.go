@@ -0,0 +1,20 @@
+package reportdatamap
+
+import (
+	reportdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData"
+	"sync"
+)
+
+var _ reportdata.ReportData = (*reportDataMap)(nil)
+
+type reportDataMap struct {
+	data []reportdata.Report
+	mux  *sync.RWMutex
+}
+
+func NewReportDataMap() *reportDataMap {
+	return &reportDataMap{
+		data: make([]reportdata.Report, 0, 10),
+		mux:  &sync.RWMutex{},
+	}
+}
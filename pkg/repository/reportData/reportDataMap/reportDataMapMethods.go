@@ -0,0 +1,100 @@
+package reportdatamap
+
+import (
+	"errors"
+	reportdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData"
+	"sort"
+	"strconv"
+)
+
+func (dt *reportDataMap) AddReport(report reportdata.Report) (reportdata.Report, error) {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	for _, el := range dt.data {
+		if el.Status == reportdata.StatusOpen && el.PostID == report.PostID && el.CommentID == report.CommentID &&
+			el.ReporterID == report.ReporterID {
+			return report, errors.New("already reported")
+		}
+	}
+	report.ID = strconv.Itoa(len(dt.data) + 1)
+	report.Status = reportdata.StatusOpen
+	dt.data = append(dt.data, report)
+	return report, nil
+}
+
+func (dt *reportDataMap) GetQueue(community string, limit int) ([]reportdata.QueueItem, error) {
+	dt.mux.RLock()
+	res := make([]reportdata.QueueItem, 0, 10)
+	index := make(map[[2]string]int, 10)
+	for _, el := range dt.data {
+		if el.Status != reportdata.StatusOpen || el.Community != community {
+			continue
+		}
+		key := [2]string{el.PostID, el.CommentID}
+		i, ok := index[key]
+		if !ok {
+			i = len(res)
+			index[key] = i
+			res = append(res, reportdata.QueueItem{PostID: el.PostID, CommentID: el.CommentID,
+				Community: el.Community, First: el.Created})
+		}
+		res[i].Count++
+		res[i].Reasons = append(res[i].Reasons, el.Reason)
+	}
+	dt.mux.RUnlock()
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].First < res[j].First
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+func (dt *reportDataMap) HasOpen(community, postID, commentID string) (bool, error) {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	for _, el := range dt.data {
+		if el.Status == reportdata.StatusOpen && el.Community == community && el.PostID == postID &&
+			el.CommentID == commentID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (dt *reportDataMap) Resolve(community, postID, commentID, status, resolved string) ([]reportdata.Report, error) {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	res := make([]reportdata.Report, 0, 5)
+	for i, el := range dt.data {
+		if el.Status != reportdata.StatusOpen || el.Community != community || el.PostID != postID ||
+			el.CommentID != commentID {
+			continue
+		}
+		el.Status = status
+		el.Resolved = resolved
+		dt.data[i] = el
+		res = append(res, el)
+	}
+	if len(res) == 0 {
+		return nil, errors.New("invalid report")
+	}
+	return res, nil
+}
+
+// GetByReporter returns the newest reports first.
+func (dt *reportDataMap) GetByReporter(reporterID string, limit int) ([]reportdata.Report, error) {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	res := make([]reportdata.Report, 0, 10)
+	for i := len(dt.data) - 1; i >= 0 && len(res) < limit; i-- {
+		if dt.data[i].ReporterID == reporterID {
+			res = append(res, dt.data[i])
+		}
+	}
+	return res, nil
+}
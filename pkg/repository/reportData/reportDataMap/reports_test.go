@@ -0,0 +1,62 @@
+package reportdatamap
+
+import (
+	reportdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData"
+	"reflect"
+	"testing"
+)
+
+func TestReports(t *testing.T) {
+	dt := NewReportDataMap()
+	add := func(postID, commentID, reporterID, created string) error {
+		_, err := dt.AddReport(reportdata.Report{PostID: postID, CommentID: commentID, Community: "music",
+			ReporterID: reporterID, Reason: "spam", Created: created})
+		return err
+	}
+	_ = add("p1", "", "u1", "2022-11-04T10:00:00Z")
+	_ = add("p2", "c1", "u1", "2022-11-04T11:00:00Z")
+	_ = add("p2", "c1", "u2", "2022-11-04T12:00:00Z")
+	_ = add("p3", "", "u1", "2022-11-04T09:00:00Z")
+	if err := add("p2", "c1", "u2", "2022-11-04T13:00:00Z"); err == nil || err.Error() != "already reported" {
+		t.Errorf("results not match, want %v, have %v", "already reported", err)
+	}
+
+	queue, _ := dt.GetQueue("music", 10)
+	res := make([]string, 0, len(queue))
+	for _, el := range queue {
+		res = append(res, el.PostID+"/"+el.CommentID)
+	}
+	want := []string{"p2/c1", "p3/", "p1/"}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("results not match, want %v, have %v", want, res)
+	}
+	if queue[0].Count != 2 || !reflect.DeepEqual(queue[0].Reasons, []string{"spam", "spam"}) {
+		t.Errorf("results not match, want %v, have %v", 2, queue[0])
+	}
+
+	if ok, _ := dt.HasOpen("music", "p2", "c1"); !ok {
+		t.Errorf("results not match, want %v, have %v", true, ok)
+	}
+	resolved, err := dt.Resolve("music", "p2", "c1", reportdata.StatusRemoved, "2022-11-05T00:00:00Z")
+	if err != nil || len(resolved) != 2 {
+		t.Fatalf("results not match, want %v, have %v (%v)", 2, len(resolved), err)
+	}
+	if _, err = dt.Resolve("music", "p2", "c1", reportdata.StatusRemoved, "2022-11-05T00:00:00Z"); err == nil {
+		t.Errorf("resolved twice")
+	}
+	if ok, _ := dt.HasOpen("music", "p2", "c1"); ok {
+		t.Errorf("results not match, want %v, have %v", false, ok)
+	}
+	if err = add("p2", "c1", "u2", "2022-11-05T01:00:00Z"); err != nil {
+		t.Errorf("unexpected err: %s", err)
+	}
+
+	mine, _ := dt.GetByReporter("u2", 10)
+	statuses := make([]string, 0, len(mine))
+	for _, el := range mine {
+		statuses = append(statuses, el.Status)
+	}
+	if want := []string{reportdata.StatusOpen, reportdata.StatusRemoved}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("results not match, want %v, have %v", want, statuses)
+	}
+}
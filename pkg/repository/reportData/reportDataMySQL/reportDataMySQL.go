@@ -0,0 +1,16 @@
+package reportdatamysql
+
+import (
+	"database/sql"
+	reportdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData"
+)
+
+var _ reportdata.ReportData = (*ReportDataMySQL)(nil)
+
+type ReportDataMySQL struct {
+	db *sql.DB
+}
+
+func NewReportDataMySQL(db *sql.DB) *ReportDataMySQL {
+	return &ReportDataMySQL{db: db}
+}
@@ -0,0 +1,143 @@
+package reportdatamysql
+
+import (
+	"database/sql"
+	"errors"
+	reportdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reasonSeparator joins the reasons of a queue item, it can not be typed in a reason.
+const reasonSeparator = "\x1f"
+
+const selectReport = "SELECT id, post_id, comment_id, community, reporter_id, reason, status, created, resolved FROM reports"
+
+func (dt *ReportDataMySQL) AddReport(report reportdata.Report) (reportdata.Report, error) {
+	created, err := time.Parse(time.RFC3339, report.Created)
+	if err != nil {
+		return report, err
+	}
+	res, err := dt.db.Exec("INSERT INTO reports (post_id, comment_id, community, reporter_id, reason, created) "+
+		"SELECT ?, ?, ?, ?, ?, ? FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM reports "+
+		"WHERE post_id = ? AND comment_id = ? AND reporter_id = ? AND status = 'open')",
+		report.PostID, report.CommentID, report.Community, report.ReporterID, report.Reason, created,
+		report.PostID, report.CommentID, report.ReporterID)
+	if err != nil {
+		return report, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return report, err
+	}
+	if affected == 0 {
+		return report, errors.New("already reported")
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return report, err
+	}
+	report.ID = strconv.FormatInt(id, 10)
+	report.Status = reportdata.StatusOpen
+	return report, nil
+}
+
+func (dt *ReportDataMySQL) GetQueue(community string, limit int) ([]reportdata.QueueItem, error) {
+	rows, err := dt.db.Query("SELECT post_id, comment_id, COUNT(*), MIN(created), "+
+		"GROUP_CONCAT(reason ORDER BY id SEPARATOR '"+reasonSeparator+"') FROM reports "+
+		"WHERE community = ? AND status = 'open' GROUP BY post_id, comment_id "+
+		"ORDER BY COUNT(*) DESC, MIN(created) LIMIT ?", community, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make([]reportdata.QueueItem, 0, 10)
+	for rows.Next() {
+		var first time.Time
+		var reasons string
+		item := reportdata.QueueItem{Community: community}
+		if err = rows.Scan(&item.PostID, &item.CommentID, &item.Count, &first, &reasons); err != nil {
+			return nil, err
+		}
+		item.First = first.UTC().Format("2006-01-02T15:04:05Z07:00")
+		item.Reasons = strings.Split(reasons, reasonSeparator)
+		res = append(res, item)
+	}
+	return res, rows.Err()
+}
+
+func (dt *ReportDataMySQL) HasOpen(community, postID, commentID string) (bool, error) {
+	var res bool
+	err := dt.db.QueryRow("SELECT EXISTS (SELECT 1 FROM reports WHERE community = ? AND post_id = ? "+
+		"AND comment_id = ? AND status = 'open')", community, postID, commentID).Scan(&res)
+	return res, err
+}
+
+func (dt *ReportDataMySQL) Resolve(community, postID, commentID, status, resolved string) ([]reportdata.Report, error) {
+	resolvedAt, err := time.Parse(time.RFC3339, resolved)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := dt.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	rows, err := tx.Query(selectReport+" WHERE community = ? AND post_id = ? AND comment_id = ? AND status = 'open' "+
+		"FOR UPDATE", community, postID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	res, err := scanReports(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, errors.New("invalid report")
+	}
+	_, err = tx.Exec("UPDATE reports SET status = ?, resolved = ? WHERE community = ? AND post_id = ? "+
+		"AND comment_id = ? AND status = 'open'", status, resolvedAt, community, postID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	for i := range res {
+		res[i].Status = status
+		res[i].Resolved = resolved
+	}
+	return res, nil
+}
+
+func (dt *ReportDataMySQL) GetByReporter(reporterID string, limit int) ([]reportdata.Report, error) {
+	rows, err := dt.db.Query(selectReport+" WHERE reporter_id = ? ORDER BY id DESC LIMIT ?", reporterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanReports(rows)
+}
+
+func scanReports(rows *sql.Rows) ([]reportdata.Report, error) {
+	defer rows.Close()
+	res := make([]reportdata.Report, 0, 10)
+	for rows.Next() {
+		var id, reporterID int
+		var created time.Time
+		var resolved sql.NullTime
+		var report reportdata.Report
+		if err := rows.Scan(&id, &report.PostID, &report.CommentID, &report.Community, &reporterID, &report.Reason,
+			&report.Status, &created, &resolved); err != nil {
+			return nil, err
+		}
+		report.ID = strconv.Itoa(id)
+		report.ReporterID = strconv.Itoa(reporterID)
+		report.Created = created.UTC().Format("2006-01-02T15:04:05Z07:00")
+		if resolved.Valid {
+			report.Resolved = resolved.Time.UTC().Format("2006-01-02T15:04:05Z07:00")
+		}
+		res = append(res, report)
+	}
+	return res, rows.Err()
+}
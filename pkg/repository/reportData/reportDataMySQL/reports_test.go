@@ -0,0 +1,132 @@
+package reportdatamysql
+
+import (
+	"errors"
+	reportdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReports_AddReport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewReportDataMySQL(db)
+	report := reportdata.Report{PostID: "p1", Community: "music", ReporterID: "1", Reason: "spam",
+		Created: "2022-11-04T17:55:14Z"}
+	created, _ := time.Parse(time.RFC3339, report.Created)
+	testTable := []struct {
+		name          string
+		mockBehaviour func()
+		id            string
+		err           error
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func() {
+				mock.ExpectExec("INSERT INTO reports").
+					WithArgs("p1", "", "music", "1", "spam", created, "p1", "", "1").
+					WillReturnResult(sqlmock.NewResult(7, 1))
+			},
+			id: "7",
+		},
+		{
+			name: "already reported",
+			mockBehaviour: func() {
+				mock.ExpectExec("INSERT INTO reports").
+					WithArgs("p1", "", "music", "1", "spam", created, "p1", "", "1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			err: errors.New("already reported"),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehaviour()
+			got, err := repo.AddReport(report)
+			if testCase.err != nil {
+				if err == nil || err.Error() != testCase.err.Error() {
+					t.Errorf("results not match, want %v, have %v", testCase.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected err: %s", err)
+				return
+			}
+			if got.ID != testCase.id || got.Status != reportdata.StatusOpen {
+				t.Errorf("results not match, want %v, have %v", testCase.id, got)
+			}
+		})
+	}
+}
+
+func TestReports_Resolve(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewReportDataMySQL(db)
+	created := time.Date(2022, 11, 4, 17, 55, 14, 0, time.UTC)
+	resolved := time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "post_id", "comment_id", "community", "reporter_id", "reason", "status", "created", "resolved"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM reports WHERE community").WithArgs("music", "p1", "c1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(3, "p1", "c1", "music", 2, "spam", "open", created, nil))
+	mock.ExpectExec("UPDATE reports SET status").WithArgs("removed", resolved, "music", "p1", "c1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	got, err := repo.Resolve("music", "p1", "c1", reportdata.StatusRemoved, "2022-11-05T00:00:00Z")
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	want := []reportdata.Report{{ID: "3", PostID: "p1", CommentID: "c1", Community: "music", ReporterID: "2",
+		Reason: "spam", Status: "removed", Created: "2022-11-04T17:55:14Z", Resolved: "2022-11-05T00:00:00Z"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results not match, want %v, have %v", want, got)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM reports WHERE community").WithArgs("music", "p1", "c1").
+		WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectRollback()
+	if _, err = repo.Resolve("music", "p1", "c1", reportdata.StatusRemoved, "2022-11-05T00:00:00Z"); err == nil ||
+		err.Error() != "invalid report" {
+		t.Errorf("results not match, want %v, have %v", "invalid report", err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestReports_HasOpen(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewReportDataMySQL(db)
+	mock.ExpectQuery("SELECT EXISTS").WithArgs("music", "p1", "c1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	if ok, err := repo.HasOpen("music", "p1", "c1"); err != nil || !ok {
+		t.Errorf("results not match, want %v, have %v (%v)", true, ok, err)
+	}
+	mock.ExpectQuery("SELECT EXISTS").WithArgs("music", "p1", "").
+		WillReturnError(errors.New("connection refused"))
+	if _, err = repo.HasOpen("music", "p1", ""); err == nil {
+		t.Errorf("results not match, want %v, have %v", "error", err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,11 @@
+package spamdata
+
+import (
+	"gitlab.com/vk-go/lectures-2022-2/pkg/spam"
+)
+
+// SpamData keeps the counts the spam filter is trained to.
+type SpamData interface {
+	Train(tokens []string, isSpam bool) error
+	Counts(tokens []string) (spam.Counts, error)
+}
@@ -0,0 +1,23 @@
+package spamdatamap
+
+import (
+	spamdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/spamData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/spam"
+	"sync"
+)
+
+var _ spamdata.SpamData = (*spamDataMap)(nil)
+
+type spamDataMap struct {
+	spamDocs int
+	hamDocs  int
+	tokens   map[string]spam.TokenCount
+	mux      *sync.RWMutex
+}
+
+func NewSpamDataMap() *spamDataMap {
+	return &spamDataMap{
+		tokens: make(map[string]spam.TokenCount),
+		mux:    &sync.RWMutex{},
+	}
+}
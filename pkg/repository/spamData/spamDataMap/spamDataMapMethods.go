@@ -0,0 +1,37 @@
+package spamdatamap
+
+import (
+	"gitlab.com/vk-go/lectures-2022-2/pkg/spam"
+)
+
+func (dt *spamDataMap) Train(tokens []string, isSpam bool) error {
+	dt.mux.Lock()
+	defer dt.mux.Unlock()
+	if isSpam {
+		dt.spamDocs++
+	} else {
+		dt.hamDocs++
+	}
+	for _, el := range tokens {
+		count := dt.tokens[el]
+		if isSpam {
+			count.Spam++
+		} else {
+			count.Ham++
+		}
+		dt.tokens[el] = count
+	}
+	return nil
+}
+
+func (dt *spamDataMap) Counts(tokens []string) (spam.Counts, error) {
+	dt.mux.RLock()
+	defer dt.mux.RUnlock()
+	res := spam.Counts{SpamDocs: dt.spamDocs, HamDocs: dt.hamDocs, Tokens: make(map[string]spam.TokenCount, len(tokens))}
+	for _, el := range tokens {
+		if count, ok := dt.tokens[el]; ok {
+			res.Tokens[el] = count
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,16 @@
+package spamdatamysql
+
+import (
+	"database/sql"
+	spamdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/spamData"
+)
+
+var _ spamdata.SpamData = (*SpamDataMySQL)(nil)
+
+type SpamDataMySQL struct {
+	db *sql.DB
+}
+
+func NewSpamDataMySQL(db *sql.DB) *SpamDataMySQL {
+	return &SpamDataMySQL{db: db}
+}
@@ -0,0 +1,66 @@
+package spamdatamysql
+
+import (
+	"gitlab.com/vk-go/lectures-2022-2/pkg/spam"
+	"strings"
+)
+
+// Train adds one document to the class, the column is chosen here and never
+// comes from the caller.
+func (dt *SpamDataMySQL) Train(tokens []string, isSpam bool) error {
+	column := "ham"
+	if isSpam {
+		column = "spam"
+	}
+	tx, err := dt.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err = tx.Exec("UPDATE spam_docs SET " + column + " = " + column + " + 1 WHERE id = 1"); err != nil {
+		return err
+	}
+	if len(tokens) != 0 {
+		args := make([]interface{}, 0, len(tokens))
+		for _, el := range tokens {
+			args = append(args, el)
+		}
+		_, err = tx.Exec("INSERT INTO spam_tokens (token, "+column+") VALUES "+
+			strings.TrimSuffix(strings.Repeat("(?, 1), ", len(tokens)), ", ")+
+			" ON DUPLICATE KEY UPDATE "+column+" = "+column+" + 1", args...)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (dt *SpamDataMySQL) Counts(tokens []string) (spam.Counts, error) {
+	res := spam.Counts{Tokens: make(map[string]spam.TokenCount, len(tokens))}
+	row := dt.db.QueryRow("SELECT spam, ham FROM spam_docs WHERE id = 1")
+	if err := row.Scan(&res.SpamDocs, &res.HamDocs); err != nil {
+		return spam.Counts{}, err
+	}
+	if len(tokens) == 0 {
+		return res, nil
+	}
+	args := make([]interface{}, 0, len(tokens))
+	for _, el := range tokens {
+		args = append(args, el)
+	}
+	rows, err := dt.db.Query("SELECT token, spam, ham FROM spam_tokens WHERE token IN ("+
+		strings.TrimSuffix(strings.Repeat("?, ", len(tokens)), ", ")+")", args...)
+	if err != nil {
+		return spam.Counts{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var token string
+		var count spam.TokenCount
+		if err = rows.Scan(&token, &count.Spam, &count.Ham); err != nil {
+			return spam.Counts{}, err
+		}
+		res.Tokens[token] = count
+	}
+	return res, rows.Err()
+}
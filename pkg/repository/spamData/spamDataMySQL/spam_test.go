@@ -0,0 +1,91 @@
+package spamdatamysql
+
+import (
+	"errors"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/spam"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"reflect"
+	"testing"
+)
+
+func TestSpam_Train(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewSpamDataMySQL(db)
+	testTable := []struct {
+		name          string
+		mockBehaviour func()
+		err           error
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE spam_docs SET spam = spam \\+ 1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO spam_tokens \\(token, spam\\) VALUES \\(\\?, 1\\), \\(\\?, 1\\) "+
+					"ON DUPLICATE KEY UPDATE spam = spam \\+ 1").
+					WithArgs("cheap", "pills").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "db error",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE spam_docs SET spam = spam \\+ 1").
+					WillReturnError(errors.New("db error"))
+				mock.ExpectRollback()
+			},
+			err: errors.New("db error"),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehaviour()
+			err := repo.Train([]string{"cheap", "pills"}, true)
+			if testCase.err != nil {
+				if err == nil || err.Error() != testCase.err.Error() {
+					t.Errorf("results not match, want %v, have %v", testCase.err, err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected err: %s", err)
+			}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestSpam_Counts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewSpamDataMySQL(db)
+	mock.ExpectQuery("SELECT spam, ham FROM spam_docs").
+		WillReturnRows(sqlmock.NewRows([]string{"spam", "ham"}).AddRow(12, 30))
+	mock.ExpectQuery("SELECT token, spam, ham FROM spam_tokens WHERE token IN \\(\\?, \\?\\)").
+		WithArgs("cheap", "go").
+		WillReturnRows(sqlmock.NewRows([]string{"token", "spam", "ham"}).AddRow("cheap", 10, 1))
+	got, err := repo.Counts([]string{"cheap", "go"})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	want := spam.Counts{SpamDocs: 12, HamDocs: 30, Tokens: map[string]spam.TokenCount{"cheap": {Spam: 10, Ham: 1}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results not match, want %v, have %v", want, got)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
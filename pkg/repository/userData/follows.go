@@ -0,0 +1,4 @@
+package userdata
+
+// MaxFollowing is how many users one account can follow.
+const MaxFollowing = 1000
@@ -0,0 +1,21 @@
+package userdata
+
+// Lists a user can put posts and comments into.
+const (
+	ListSaved  = "saved"
+	ListHidden = "hidden"
+)
+
+// MaxHidden is how many items a user can hide, the listings leave out all of them.
+const MaxHidden = 1000
+
+// ItemRef points to a post or, with CommentID, to a comment in one of the lists.
+type ItemRef struct {
+	PostID    string `json:"post_id"`
+	CommentID string `json:"comment_id,omitempty"`
+	Created   string `json:"created"`
+}
+
+func ValidList(list string) bool {
+	return list == ListSaved || list == ListHidden
+}
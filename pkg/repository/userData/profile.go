@@ -0,0 +1,11 @@
+package userdata
+
+// Profile is what everybody can see about an account.
+type Profile struct {
+	Login        string `json:"username"`
+	Created      string `json:"created,omitempty"`
+	AgeDays      int    `json:"account_age_days"`
+	PostKarma    int    `json:"post_karma"`
+	CommentKarma int    `json:"comment_karma"`
+	Karma        int    `json:"karma"`
+}
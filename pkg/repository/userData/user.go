@@ -1,7 +1,62 @@
 package userdata
 
+import (
+	"time"
+)
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+var roleRank = map[string]int{RoleUser: 0, RoleModerator: 1, RoleAdmin: 2}
+
 type User struct {
-	ID       string `json:"-"`
-	Login    string `json:"username" valid:",required"`
-	Password string `json:"password" valid:",required"`
+	ID        string `json:"-"`
+	Login     string `json:"username" valid:",required"`
+	Password  string `json:"password" valid:",required"`
+	Role      string `json:"-"`
+	Suspended bool   `json:"-"`
+	Created   string `json:"-"`
+}
+
+// UserInfo is what the admin API shows about an account.
+type UserInfo struct {
+	ID        string `json:"id"`
+	Login     string `json:"username"`
+	Role      string `json:"role"`
+	Suspended bool   `json:"suspended"`
+}
+
+func (u User) Info() UserInfo {
+	return UserInfo{ID: u.ID, Login: u.Login, Role: RoleOf(u.Role), Suspended: u.Suspended}
+}
+
+func ValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// RoleOf treats accounts created before roles existed as users.
+func RoleOf(role string) string {
+	if role == "" {
+		return RoleUser
+	}
+	return role
+}
+
+// HasRole reports whether role grants at least the rights of required.
+func HasRole(role, required string) bool {
+	have, ok := roleRank[RoleOf(role)]
+	return ok && have >= roleRank[required]
+}
+
+// Age is how long ago the account was created, zero if that is not known.
+func (u User) Age(now time.Time) time.Duration {
+	created, err := time.Parse(time.RFC3339, u.Created)
+	if err != nil || created.After(now) {
+		return 0
+	}
+	return now.Sub(created)
 }
@@ -4,4 +4,14 @@ type UserData interface {
 	InsertUser(user User) (User, error)
 	GetUser(id string) (User, error)
 	CheckUser(login string) (string, error)
+	UpdatePassword(id, password string) error
+	GetUsers(limit, offset int) ([]User, error)
+	SetRole(id, role string) error
+	SetSuspended(id string, suspended bool) error
+	AddItem(userID, list string, item ItemRef) error
+	RemoveItem(userID, list, postID, commentID string) error
+	Items(userID, list string, limit int) ([]ItemRef, error)
+	Follow(userID, followedID string) error
+	Unfollow(userID, followedID string) error
+	Following(userID string) ([]string, error)
 }
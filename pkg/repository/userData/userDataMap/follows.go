@@ -0,0 +1,40 @@
+package userdatamap
+
+import (
+	"errors"
+)
+
+// Follow keeps the users in the order they were followed, following twice changes nothing.
+func (usData *userDataMap) Follow(userID, followedID string) error {
+	usData.mux.Lock()
+	defer usData.mux.Unlock()
+	if _, ok := usData.data[followedID]; !ok {
+		return errors.New("invalid id")
+	}
+	for _, el := range usData.follows[userID] {
+		if el == followedID {
+			return nil
+		}
+	}
+	usData.follows[userID] = append(usData.follows[userID], followedID)
+	return nil
+}
+
+func (usData *userDataMap) Unfollow(userID, followedID string) error {
+	usData.mux.Lock()
+	defer usData.mux.Unlock()
+	following := usData.follows[userID]
+	for i, el := range following {
+		if el == followedID {
+			usData.follows[userID] = append(following[:i:i], following[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (usData *userDataMap) Following(userID string) ([]string, error) {
+	usData.mux.RLock()
+	defer usData.mux.RUnlock()
+	return append(make([]string, 0, len(usData.follows[userID])), usData.follows[userID]...), nil
+}
@@ -0,0 +1,52 @@
+package userdatamap
+
+import (
+	"errors"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+)
+
+// AddItem keeps the list in the order the items were added, adding an item twice changes nothing.
+func (usData *userDataMap) AddItem(userID, list string, item userdata.ItemRef) error {
+	usData.mux.Lock()
+	defer usData.mux.Unlock()
+	if _, ok := usData.data[userID]; !ok {
+		return errors.New("invalid id")
+	}
+	lists, ok := usData.items[userID]
+	if !ok {
+		lists = make(map[string][]userdata.ItemRef)
+		usData.items[userID] = lists
+	}
+	for _, el := range lists[list] {
+		if el.PostID == item.PostID && el.CommentID == item.CommentID {
+			return nil
+		}
+	}
+	lists[list] = append(lists[list], item)
+	return nil
+}
+
+func (usData *userDataMap) RemoveItem(userID, list, postID, commentID string) error {
+	usData.mux.Lock()
+	defer usData.mux.Unlock()
+	items := usData.items[userID][list]
+	for i, el := range items {
+		if el.PostID == postID && el.CommentID == commentID {
+			usData.items[userID][list] = append(items[:i:i], items[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("invalid item")
+}
+
+// Items returns the most recently added items first.
+func (usData *userDataMap) Items(userID, list string, limit int) ([]userdata.ItemRef, error) {
+	usData.mux.RLock()
+	defer usData.mux.RUnlock()
+	items := usData.items[userID][list]
+	res := make([]userdata.ItemRef, 0, len(items))
+	for i := len(items) - 1; i >= 0 && len(res) < limit; i-- {
+		res = append(res, items[i])
+	}
+	return res, nil
+}
@@ -8,13 +8,17 @@ import (
 var _ userdata.UserData = (*userDataMap)(nil)
 
 type userDataMap struct {
-	data map[string]userdata.User
-	mux  *sync.RWMutex
+	data    map[string]userdata.User
+	items   map[string]map[string][]userdata.ItemRef
+	follows map[string][]string
+	mux     *sync.RWMutex
 }
 
 func NewUserDataMap() *userDataMap {
 	return &userDataMap{
-		data: make(map[string]userdata.User, 10),
-		mux:  &sync.RWMutex{},
+		data:    make(map[string]userdata.User, 10),
+		items:   make(map[string]map[string][]userdata.ItemRef),
+		follows: make(map[string][]string),
+		mux:     &sync.RWMutex{},
 	}
 }
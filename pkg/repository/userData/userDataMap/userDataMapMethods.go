@@ -4,6 +4,8 @@ import (
 	"errors"
 	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"sort"
+	"time"
 )
 
 func (usData *userDataMap) CheckUser(login string) (string, error) {
@@ -20,6 +22,10 @@ func (usData *userDataMap) CheckUser(login string) (string, error) {
 func (usData *userDataMap) InsertUser(user userdata.User) (userdata.User, error) {
 	id := utils.RandomHex()
 	user.ID = id
+	user.Role = userdata.RoleOf(user.Role)
+	if user.Created == "" {
+		user.Created = time.Now().UTC().Format("2006-01-02T15:04:05Z07:00")
+	}
 	usData.mux.Lock()
 	defer usData.mux.Unlock()
 	usData.data[id] = user
@@ -35,3 +41,59 @@ func (usData *userDataMap) GetUser(id string) (userdata.User, error) {
 	}
 	return user, nil
 }
+
+func (usData *userDataMap) UpdatePassword(id, password string) error {
+	usData.mux.Lock()
+	defer usData.mux.Unlock()
+	user, ok := usData.data[id]
+	if !ok {
+		return errors.New("invalid id")
+	}
+	user.Password = password
+	usData.data[id] = user
+	return nil
+}
+
+func (usData *userDataMap) GetUsers(limit, offset int) ([]userdata.User, error) {
+	usData.mux.RLock()
+	res := make([]userdata.User, 0, len(usData.data))
+	for _, el := range usData.data {
+		res = append(res, el)
+	}
+	usData.mux.RUnlock()
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Login < res[j].Login
+	})
+	if offset >= len(res) {
+		return []userdata.User{}, nil
+	}
+	res = res[offset:]
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+func (usData *userDataMap) SetRole(id, role string) error {
+	return usData.update(id, func(user *userdata.User) {
+		user.Role = role
+	})
+}
+
+func (usData *userDataMap) SetSuspended(id string, suspended bool) error {
+	return usData.update(id, func(user *userdata.User) {
+		user.Suspended = suspended
+	})
+}
+
+func (usData *userDataMap) update(id string, change func(user *userdata.User)) error {
+	usData.mux.Lock()
+	defer usData.mux.Unlock()
+	user, ok := usData.data[id]
+	if !ok {
+		return errors.New("invalid id")
+	}
+	change(&user)
+	usData.data[id] = user
+	return nil
+}
@@ -0,0 +1,39 @@
+package userdatamysql
+
+import (
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"strconv"
+)
+
+func (usData *UserDataMySQL) Follow(userID, followedID string) error {
+	usID, _ := strconv.Atoi(userID)
+	followedUsID, _ := strconv.Atoi(followedID)
+	_, err := usData.db.Exec("INSERT IGNORE INTO follows (user_id, followed_id) VALUES (?, ?)", usID, followedUsID)
+	return err
+}
+
+func (usData *UserDataMySQL) Unfollow(userID, followedID string) error {
+	usID, _ := strconv.Atoi(userID)
+	followedUsID, _ := strconv.Atoi(followedID)
+	_, err := usData.db.Exec("DELETE FROM follows WHERE user_id = ? AND followed_id = ?", usID, followedUsID)
+	return err
+}
+
+func (usData *UserDataMySQL) Following(userID string) ([]string, error) {
+	usID, _ := strconv.Atoi(userID)
+	rows, err := usData.db.Query("SELECT followed_id FROM follows WHERE user_id = ? ORDER BY created LIMIT ?",
+		usID, userdata.MaxFollowing)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make([]string, 0, 10)
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		res = append(res, strconv.Itoa(id))
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,34 @@
+package userdatamysql
+
+import (
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"reflect"
+	"testing"
+)
+
+func TestUser_Following(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewUserDataMySql(db)
+	mock.ExpectExec("INSERT IGNORE INTO follows").WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT followed_id FROM follows").WithArgs(1, userdata.MaxFollowing).
+		WillReturnRows(sqlmock.NewRows([]string{"followed_id"}).AddRow(2).AddRow(5))
+	if err = repo.Follow("1", "2"); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	got, err := repo.Following("1")
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if want := []string{"2", "5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("results not match, want %v, have %v", want, got)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,57 @@
+package userdatamysql
+
+import (
+	"errors"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"strconv"
+	"time"
+)
+
+func (usData *UserDataMySQL) AddItem(userID, list string, item userdata.ItemRef) error {
+	usID, _ := strconv.Atoi(userID)
+	created, err := time.Parse(time.RFC3339, item.Created)
+	if err != nil {
+		return err
+	}
+	_, err = usData.db.Exec("INSERT IGNORE INTO user_items (user_id, list, post_id, comment_id, created) "+
+		"VALUES (?, ?, ?, ?, ?)", usID, list, item.PostID, item.CommentID, created)
+	return err
+}
+
+func (usData *UserDataMySQL) RemoveItem(userID, list, postID, commentID string) error {
+	usID, _ := strconv.Atoi(userID)
+	res, err := usData.db.Exec("DELETE FROM user_items WHERE user_id = ? AND list = ? AND post_id = ? AND comment_id = ?",
+		usID, list, postID, commentID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("invalid item")
+	}
+	return nil
+}
+
+func (usData *UserDataMySQL) Items(userID, list string, limit int) ([]userdata.ItemRef, error) {
+	usID, _ := strconv.Atoi(userID)
+	rows, err := usData.db.Query("SELECT post_id, comment_id, created FROM user_items WHERE user_id = ? AND list = ? "+
+		"ORDER BY created DESC, id DESC LIMIT ?", usID, list, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make([]userdata.ItemRef, 0, 10)
+	for rows.Next() {
+		var item userdata.ItemRef
+		var created time.Time
+		if err = rows.Scan(&item.PostID, &item.CommentID, &created); err != nil {
+			return nil, err
+		}
+		item.Created = created.UTC().Format("2006-01-02T15:04:05Z07:00")
+		res = append(res, item)
+	}
+	return res, rows.Err()
+}
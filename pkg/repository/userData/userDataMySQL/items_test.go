@@ -0,0 +1,92 @@
+package userdatamysql
+
+import (
+	"errors"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUser_RemoveItem(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewUserDataMySql(db)
+	testTable := []struct {
+		name          string
+		mockBehaviour func()
+		err           error
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func() {
+				mock.ExpectExec("DELETE FROM user_items WHERE").
+					WithArgs(1, userdata.ListSaved, "p1", "c1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "not saved",
+			mockBehaviour: func() {
+				mock.ExpectExec("DELETE FROM user_items WHERE").
+					WithArgs(1, userdata.ListSaved, "p1", "c1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			err: errors.New("invalid item"),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehaviour()
+			err := repo.RemoveItem("1", userdata.ListSaved, "p1", "c1")
+			if testCase.err != nil {
+				if err == nil || err.Error() != testCase.err.Error() {
+					t.Errorf("results not match, want %v, have %v", testCase.err, err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected err: %s", err)
+			}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestUser_Items(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewUserDataMySql(db)
+	created := time.Date(2022, 11, 4, 17, 55, 14, 0, time.UTC)
+	mock.ExpectExec("INSERT IGNORE INTO user_items").
+		WithArgs(1, userdata.ListHidden, "p1", "", created).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT post_id, comment_id, created FROM user_items WHERE").
+		WithArgs(1, userdata.ListHidden, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "comment_id", "created"}).AddRow("p1", "", created))
+
+	item := userdata.ItemRef{PostID: "p1", Created: "2022-11-04T17:55:14Z"}
+	if err = repo.AddItem("1", userdata.ListHidden, item); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	got, err := repo.Items("1", userdata.ListHidden, 10)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if want := []userdata.ItemRef{item}; !reflect.DeepEqual(got, want) {
+		t.Errorf("results not match, want %v, have %v", want, got)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
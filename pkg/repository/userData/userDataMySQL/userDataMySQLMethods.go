@@ -1,8 +1,10 @@
 package userdatamysql
 
 import (
+	"errors"
 	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
 	"strconv"
+	"time"
 )
 
 func (usData *UserDataMySQL) CheckUser(login string) (string, error) {
@@ -29,14 +31,70 @@ func (usData *UserDataMySQL) InsertUser(user userdata.User) (userdata.User, erro
 
 func (usData *UserDataMySQL) GetUser(id string) (userdata.User, error) {
 	usID, _ := strconv.Atoi(id)
-	var login, password string
+	var login, password, role string
+	var suspended bool
+	var created time.Time
 	var user userdata.User
-	row := usData.db.QueryRow("SELECT login, password FROM userDB WHERE user_id = ?", usID)
-	if err := row.Scan(&login, &password); err != nil {
+	row := usData.db.QueryRow("SELECT login, password, role, suspended, created FROM userDB WHERE user_id = ?", usID)
+	if err := row.Scan(&login, &password, &role, &suspended, &created); err != nil {
 		return user, err
 	}
 	user.Login = login
 	user.Password = password
+	user.Role = role
+	user.Suspended = suspended
+	user.Created = created.UTC().Format("2006-01-02T15:04:05Z07:00")
 	user.ID = id
 	return user, nil
 }
+
+func (usData *UserDataMySQL) UpdatePassword(id, password string) error {
+	usID, _ := strconv.Atoi(id)
+	_, err := usData.db.Exec("UPDATE userDB SET password = ? WHERE user_id = ?", password, usID)
+	return err
+}
+
+func (usData *UserDataMySQL) GetUsers(limit, offset int) ([]userdata.User, error) {
+	rows, err := usData.db.Query("SELECT user_id, login, role, suspended FROM userDB ORDER BY login LIMIT ? OFFSET ?",
+		limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make([]userdata.User, 0, limit)
+	for rows.Next() {
+		var userID int
+		var user userdata.User
+		if err = rows.Scan(&userID, &user.Login, &user.Role, &user.Suspended); err != nil {
+			return nil, err
+		}
+		user.ID = strconv.Itoa(userID)
+		res = append(res, user)
+	}
+	return res, rows.Err()
+}
+
+func (usData *UserDataMySQL) SetRole(id, role string) error {
+	usID, _ := strconv.Atoi(id)
+	return usData.update("UPDATE userDB SET role = ? WHERE user_id = ?", role, usID)
+}
+
+func (usData *UserDataMySQL) SetSuspended(id string, suspended bool) error {
+	usID, _ := strconv.Atoi(id)
+	return usData.update("UPDATE userDB SET suspended = ? WHERE user_id = ?", suspended, usID)
+}
+
+func (usData *UserDataMySQL) update(query string, args ...interface{}) error {
+	res, err := usData.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("invalid user id")
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func TestUser_Check(t *testing.T) {
@@ -181,8 +182,9 @@ func TestUser_GetUser(t *testing.T) {
 		{
 			name: "ok",
 			mockBehaviour: func(login, password string, userID int) {
-				rows := sqlmock.NewRows([]string{"login", "password"}).AddRow(login, password)
-				mock.ExpectQuery("SELECT login, password FROM userDB WHERE").
+				rows := sqlmock.NewRows([]string{"login", "password", "role", "suspended", "created"}).
+					AddRow(login, password, "user", false, time.Date(2022, 11, 4, 17, 55, 14, 0, time.UTC))
+				mock.ExpectQuery("SELECT login, password, role, suspended, created FROM userDB WHERE").
 					WithArgs(userID).
 					WillReturnRows(rows)
 			},
@@ -194,8 +196,8 @@ func TestUser_GetUser(t *testing.T) {
 		{
 			name: "invalid user id",
 			mockBehaviour: func(login, password string, userID int) {
-				rows := sqlmock.NewRows([]string{"login", "password"}).RowError(1, errors.New("invalid user id"))
-				mock.ExpectQuery("SELECT login, password FROM userDB WHERE").
+				rows := sqlmock.NewRows([]string{"login", "password", "role", "suspended", "created"}).RowError(1, errors.New("invalid user id"))
+				mock.ExpectQuery("SELECT login, password, role, suspended, created FROM userDB WHERE").
 					WithArgs(userID).
 					WillReturnRows(rows)
 			},
@@ -228,11 +230,15 @@ func TestUser_GetUser(t *testing.T) {
 					ID:       strconv.Itoa(testCase.userID),
 					Login:    testCase.login,
 					Password: testCase.password,
+					Role:     "user",
+					Created:  "2022-11-04T17:55:14Z",
 				}) {
 					t.Errorf("results not match, want %v, have %v", userdata.User{
 						ID:       strconv.Itoa(testCase.userID),
 						Login:    testCase.login,
 						Password: testCase.password,
+						Role:     "user",
+						Created:  "2022-11-04T17:55:14Z",
 					}, got)
 					return
 				}
@@ -240,3 +246,66 @@ func TestUser_GetUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUser_UpdatePassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewUserDataMySql(db)
+	type mockBehaviour func(password string, userID int)
+	testTable := []struct {
+		name          string
+		mockBehaviour mockBehaviour
+		password      string
+		userID        int
+		err           error
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func(password string, userID int) {
+				mock.ExpectExec("UPDATE userDB SET password").
+					WithArgs(password, userID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			password: "$argon2id$v=19$m=65536,t=1,p=4$c2FsdA$aGFzaA",
+			userID:   1,
+			err:      nil,
+		},
+		{
+			name: "invalid update",
+			mockBehaviour: func(password string, userID int) {
+				mock.ExpectExec("UPDATE userDB SET password").
+					WithArgs(password, userID).
+					WillReturnError(errors.New("invalid update"))
+			},
+			password: "456",
+			userID:   1,
+			err:      errors.New("invalid update"),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehaviour(testCase.password, testCase.userID)
+			err := repo.UpdatePassword(strconv.Itoa(testCase.userID), testCase.password)
+			if testCase.err != nil {
+				if err == nil || !reflect.DeepEqual(err.Error(), testCase.err.Error()) {
+					t.Errorf("results not match, want %v, have %v", testCase.err, err)
+					return
+				}
+			} else {
+				if err != nil {
+					t.Errorf("unexpected err: %s", err)
+					return
+				}
+				if err = mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("there were unfulfilled expectations: %s", err)
+					return
+				}
+			}
+		})
+	}
+}
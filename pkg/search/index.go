@@ -0,0 +1,74 @@
+package search
+
+import (
+	"sync"
+)
+
+// Index is an inverted index from words to the ids of the documents containing them.
+type Index struct {
+	mux   *sync.RWMutex
+	terms map[string]map[string]bool
+	docs  map[string][]string
+}
+
+func NewIndex() *Index {
+	return &Index{
+		mux:   &sync.RWMutex{},
+		terms: make(map[string]map[string]bool),
+		docs:  make(map[string][]string),
+	}
+}
+
+// Set replaces the indexed texts of the document.
+func (idx *Index) Set(id string, texts ...string) {
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+	idx.remove(id)
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, el := range Tokenize(text) {
+			if seen[el] {
+				continue
+			}
+			seen[el] = true
+			if idx.terms[el] == nil {
+				idx.terms[el] = make(map[string]bool)
+			}
+			idx.terms[el][id] = true
+			idx.docs[id] = append(idx.docs[id], el)
+		}
+	}
+}
+
+func (idx *Index) Remove(id string) {
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+	idx.remove(id)
+}
+
+func (idx *Index) remove(id string) {
+	for _, el := range idx.docs[id] {
+		delete(idx.terms[el], id)
+		if len(idx.terms[el]) == 0 {
+			delete(idx.terms, el)
+		}
+	}
+	delete(idx.docs, id)
+}
+
+// Lookup returns the documents containing at least one of the terms.
+func (idx *Index) Lookup(terms []string) []string {
+	idx.mux.RLock()
+	defer idx.mux.RUnlock()
+	seen := make(map[string]bool)
+	res := make([]string, 0, 8)
+	for _, term := range terms {
+		for id := range idx.terms[term] {
+			if !seen[id] {
+				seen[id] = true
+				res = append(res, id)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,114 @@
+package search
+
+import (
+	"html"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "this": true, "to": true, "was": true, "with": true,
+}
+
+// RecencyHalfLife is the age at which a match counts half as much as a fresh one.
+const RecencyHalfLife = 30 * 24 * time.Hour
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Tokenize splits text into lower case words without stop words.
+func Tokenize(text string) []string {
+	res := make([]string, 0, 8)
+	for _, el := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !isWordRune(r) }) {
+		if !stopWords[el] {
+			res = append(res, el)
+		}
+	}
+	return res
+}
+
+// Relevance grows with every query term found in the text, repeated terms add
+// less and less so long texts do not win only by size.
+func Relevance(terms []string, text string) float64 {
+	freq := make(map[string]int)
+	for _, el := range Tokenize(text) {
+		freq[el]++
+	}
+	score := 0.0
+	for _, el := range terms {
+		tf := float64(freq[el])
+		score += tf / (tf + 1.2)
+	}
+	return score
+}
+
+// Decay lowers the score of old matches, see RecencyHalfLife.
+func Decay(score float64, age time.Duration) float64 {
+	if age < 0 {
+		age = 0
+	}
+	return score * math.Pow(0.5, float64(age)/float64(RecencyHalfLife))
+}
+
+// Snippet cuts about width runes of text around the first match and wraps
+// matched words into <mark>. The rest of the text is HTML escaped.
+func Snippet(text string, terms []string, width int) string {
+	match := make(map[string]bool, len(terms))
+	for _, el := range terms {
+		match[el] = true
+	}
+	runes := []rune(text)
+	type word struct{ start, end int }
+	words := make([]word, 0, 16)
+	first := -1
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isWordRune(runes[j]) {
+			j++
+		}
+		if match[strings.ToLower(string(runes[i:j]))] {
+			words = append(words, word{i, j})
+			if first == -1 {
+				first = i
+			}
+		}
+		i = j
+	}
+	start, end := 0, len(runes)
+	if len(runes) > width {
+		if first > width/3 {
+			start = first - width/3
+		}
+		end = start + width
+		if end > len(runes) {
+			end, start = len(runes), len(runes)-width
+		}
+	}
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	pos := start
+	for _, el := range words {
+		if el.start < start || el.end > end {
+			continue
+		}
+		sb.WriteString(html.EscapeString(string(runes[pos:el.start])))
+		sb.WriteString("<mark>" + html.EscapeString(string(runes[el.start:el.end])) + "</mark>")
+		pos = el.end
+	}
+	sb.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
@@ -0,0 +1,74 @@
+package search
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestTokenize(t *testing.T) {
+	want := []string{"go", "1", "18", "generics", "here"}
+	if res := Tokenize("Go 1.18: the generics are HERE!"); !reflect.DeepEqual(res, want) {
+		t.Errorf("results not match, want %v, have %v", want, res)
+	}
+}
+
+func TestRelevance(t *testing.T) {
+	terms := Tokenize("golang generics")
+	if Relevance(terms, "golang generics explained") <= Relevance(terms, "golang golang golang") {
+		t.Errorf("repeated term ranked above both terms")
+	}
+	if Relevance(terms, "rust traits") != 0 {
+		t.Errorf("unrelated text is relevant")
+	}
+	if Decay(1, RecencyHalfLife) != 0.5 || Decay(1, -time.Hour) != 1 {
+		t.Errorf("unexpected decay")
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	testCases := []struct {
+		name  string
+		text  string
+		width int
+		want  string
+	}{
+		{
+			name:  "short",
+			text:  "Go <generics> are great, go!",
+			width: 100,
+			want:  "<mark>Go</mark> &lt;<mark>generics</mark>&gt; are great, <mark>go</mark>!",
+		},
+		{
+			name:  "cut",
+			text:  "a long introduction before the word generics shows up and then the text goes on",
+			width: 30,
+			want:  "… the word <mark>generics</mark> shows up an…",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if res := Snippet(tc.text, []string{"go", "generics"}, tc.width); res != tc.want {
+				t.Errorf("results not match, want %q, have %q", tc.want, res)
+			}
+		})
+	}
+}
+
+func TestIndex(t *testing.T) {
+	idx := NewIndex()
+	idx.Set("1", "Learning Go", "generics in go")
+	idx.Set("2", "Rust traits")
+	idx.Set("3", "go away")
+	res := idx.Lookup([]string{"go", "traits"})
+	sort.Strings(res)
+	if want := []string{"1", "2", "3"}; !reflect.DeepEqual(res, want) {
+		t.Errorf("results not match, want %v, have %v", want, res)
+	}
+	idx.Set("3", "stay here")
+	idx.Remove("1")
+	if res = idx.Lookup([]string{"go"}); len(res) != 0 {
+		t.Errorf("removed documents found: %v", res)
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+func (s *Server) ListUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var limit, offset int
+	var err error
+	if param := query.Get("limit"); param != "" {
+		if limit, err = strconv.Atoi(param); err != nil || limit < 1 {
+			utils.NewRespError(w, "invalid limit", 400, s.log)
+			return
+		}
+	}
+	if param := query.Get("offset"); param != "" {
+		if offset, err = strconv.Atoi(param); err != nil || offset < 0 {
+			utils.NewRespError(w, "invalid offset", 400, s.log)
+			return
+		}
+	}
+	users, err := s.service.ListUsers(limit, offset)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.writeJSON(w, users, 200)
+}
+
+func (s *Server) SetRole(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	if err = r.Body.Close(); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	body := struct {
+		Role string `json:"role"`
+	}{}
+	if err = json.Unmarshal(data, &body); err != nil {
+		utils.NewRespError(w, "invalid json input", 400, s.log)
+		return
+	}
+	userID := mux.Vars(r)["user_id"]
+	s.changeUser(w, adminID, userID, func(adminID, userID string) (userdata.UserInfo, error) {
+		return s.service.SetRole(adminID, userID, body.Role)
+	}, "role change")
+}
+
+func (s *Server) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	s.changeUser(w, adminID, mux.Vars(r)["user_id"], s.service.SuspendUser, "suspension")
+}
+
+func (s *Server) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	s.changeUser(w, adminID, mux.Vars(r)["user_id"], s.service.UnsuspendUser, "unsuspension")
+}
+
+func (s *Server) changeUser(w http.ResponseWriter, adminID, userID string,
+	change func(adminID, userID string) (userdata.UserInfo, error), action string) {
+	user, err := change(adminID, userID)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	s.writeJSON(w, user, 200)
+	s.log.Printf("Successful user %s | adminID %s | userID %s \n", action, adminID, userID)
+}
+
+func (s *Server) AdminDeletePost(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	postID := mux.Vars(r)["post_id"]
+	if err := s.service.RemovePost(adminID, postID); err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	utils.NewRespError(w, "success", 200, s.log)
+	s.log.Printf("Successful post removal | adminID %s | postID %s \n", adminID, postID)
+}
+
+func (s *Server) AdminDeleteComment(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	postID := mux.Vars(r)["post_id"]
+	commID := mux.Vars(r)["comment_id"]
+	post, err := s.service.RemoveComment(adminID, postID, commID)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	resp, err := utils.MarshalPost(post)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	if _, err = w.Write(resp); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.log.Printf("Successful comment removal | adminID %s | postID %s | commentID %s \n", adminID, postID, commID)
+}
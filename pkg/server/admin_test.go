@@ -0,0 +1,156 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
+	mockservice "gitlab.com/vk-go/lectures-2022-2/pkg/service/mocks"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestServer_ListUsers(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAdmin)
+	testingTable := []struct {
+		name              string
+		url               string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "ok",
+			url:  "/admin/users?limit=10&offset=20",
+			mockBehavior: func(s *mockservice.MockAdmin) {
+				s.EXPECT().ListUsers(10, 20).Return([]userdata.UserInfo{
+					{ID: "1", Login: "admin", Role: "admin"},
+					{ID: "2", Login: "spammer", Role: "user", Suspended: true},
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`[{"id":"1","username":"admin","role":"admin","suspended":false},{"id":"2","username":"spammer","role":"user","suspended":true}]`),
+		},
+		{
+			name:              "invalid offset",
+			url:               "/admin/users?offset=-1",
+			mockBehavior:      func(s *mockservice.MockAdmin) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid offset"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			admin := mockservice.NewMockAdmin(c)
+			testCase.mockBehavior(admin)
+
+			services := &service.Service{Admin: admin}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("GET", testCase.url, bytes.NewBufferString(""))
+			w := httptest.NewRecorder()
+			handler.ListUsers(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_SetRole(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAdmin)
+	testingTable := []struct {
+		name              string
+		inputBody         string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name:      "ok",
+			inputBody: `{"role":"moderator"}`,
+			mockBehavior: func(s *mockservice.MockAdmin) {
+				s.EXPECT().SetRole("1", "2", "moderator").Return(userdata.UserInfo{ID: "2", Login: "bob", Role: "moderator"}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"role":"moderator"`),
+		},
+		{
+			name:      "invalid role",
+			inputBody: `{"role":"root"}`,
+			mockBehavior: func(s *mockservice.MockAdmin) {
+				s.EXPECT().SetRole("1", "2", "root").Return(userdata.UserInfo{}, errors.New("invalid role"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid role"`),
+		},
+		{
+			name:              "invalid json input",
+			inputBody:         `{"role"`,
+			mockBehavior:      func(s *mockservice.MockAdmin) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid json input"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			admin := mockservice.NewMockAdmin(c)
+			testCase.mockBehavior(admin)
+
+			services := &service.Service{Admin: admin}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("PUT", "/admin/users/2/role", bytes.NewBufferString(testCase.inputBody))
+			r = mux.SetURLVars(r, map[string]string{"user_id": "2"})
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1", Role: "admin"}}))
+			w := httptest.NewRecorder()
+			handler.SetRole(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_SuspendUser(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	admin := mockservice.NewMockAdmin(c)
+	admin.EXPECT().SuspendUser("1", "2").Return(userdata.UserInfo{ID: "2", Login: "bob", Role: "user", Suspended: true}, nil)
+	handler := NewServer(&service.Service{Admin: admin}, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+	r := httptest.NewRequest("POST", "/admin/users/2/suspend", bytes.NewBufferString(""))
+	r = mux.SetURLVars(r, map[string]string{"user_id": "2"})
+	r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1", Role: "admin"}}))
+	w := httptest.NewRecorder()
+	handler.SuspendUser(w, r)
+	body, _ := io.ReadAll(w.Result().Body)
+	if !bytes.Contains(body, []byte(`"suspended":true`)) {
+		t.Errorf("no text found")
+	}
+}
@@ -29,7 +29,7 @@ func (s *Server) Register(w http.ResponseWriter, r *http.Request) {
 		utils.NewRespError(w, "invalid struct fields", 400, s.log)
 		return
 	}
-	login := elem.Login
+	login, password := elem.Login, elem.Password
 	elem, err = s.service.CreateUser(elem)
 	if err != nil {
 		utils.NewRegisterError(w, utils.RegisterErrorList{
@@ -44,14 +44,12 @@ func (s *Server) Register(w http.ResponseWriter, r *http.Request) {
 		}, 422)
 		return
 	}
-	token, err := s.service.GenerateToken(elem.Login, elem.Password)
+	tokens, err := s.service.GenerateToken(elem.Login, password, clientInfo(r))
 	if err != nil {
 		utils.NewRespError(w, err.Error(), 500, s.log)
 		return
 	}
-	resp, err := json.Marshal(map[string]interface{}{
-		"token": token,
-	})
+	resp, err := json.Marshal(tokens)
 	if err != nil {
 		utils.NewRespError(w, err.Error(), 500, s.log)
 		return
@@ -85,14 +83,12 @@ func (s *Server) Login(w http.ResponseWriter, r *http.Request) {
 		utils.NewRespError(w, "invalid struct fields", 400, s.log)
 		return
 	}
-	token, err := s.service.GenerateToken(elem.Login, s.service.GetHash(elem.Password))
+	tokens, err := s.service.GenerateToken(elem.Login, elem.Password, clientInfo(r))
 	if err != nil {
 		utils.NewRespError(w, err.Error(), 401, s.log)
 		return
 	}
-	resp, err := json.Marshal(map[string]interface{}{
-		"token": token,
-	})
+	resp, err := json.Marshal(tokens)
 	if err != nil {
 		utils.NewRespError(w, err.Error(), 500, s.log)
 		return
@@ -105,3 +101,57 @@ func (s *Server) Login(w http.ResponseWriter, r *http.Request) {
 	}
 	s.log.Printf("Successful login | login %s \n", elem.Login)
 }
+
+func (s *Server) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var bd []byte
+	bd, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	if err = r.Body.Close(); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	elem := struct {
+		Refresh string `json:"refresh_token"`
+	}{}
+	if err = json.Unmarshal(bd, &elem); err != nil {
+		utils.NewRespError(w, "invalid json input", 400, s.log)
+		return
+	}
+	if elem.Refresh == "" {
+		utils.NewRespError(w, "invalid struct fields", 400, s.log)
+		return
+	}
+	tokens, err := s.service.RefreshToken(elem.Refresh)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 401, s.log)
+		return
+	}
+	resp, err := json.Marshal(tokens)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	if _, err = w.Write(resp); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+}
+
+func (s *Server) GetJWKS(w http.ResponseWriter, _ *http.Request) {
+	resp, err := json.Marshal(s.service.PublicKeys())
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	if _, err = w.Write(resp); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+}
@@ -7,6 +7,7 @@ import (
 	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
 	mockservice "gitlab.com/vk-go/lectures-2022-2/pkg/service/mocks"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
 	"io"
 	"log"
 	"net/http"
@@ -39,7 +40,7 @@ func TestServer_Register(t *testing.T) {
 					Login:    user.Login,
 					Password: "abcdef",
 				}, nil)
-				s.EXPECT().GenerateToken(user.Login, "abcdef").Return("123", nil)
+				s.EXPECT().GenerateToken(user.Login, user.Password, gomock.Any()).Return(session.TokenPair{Access: "123", Refresh: "456"}, nil)
 			},
 			expectStatusCode:  http.StatusCreated,
 			expectRequestBody: []byte(`"token":"123"`),
@@ -88,7 +89,7 @@ func TestServer_Register(t *testing.T) {
 					Login:    user.Login,
 					Password: "abcdef",
 				}, nil)
-				s.EXPECT().GenerateToken(user.Login, "abcdef").Return("", errors.New("invalid token generation"))
+				s.EXPECT().GenerateToken(user.Login, user.Password, gomock.Any()).Return(session.TokenPair{}, errors.New("invalid token generation"))
 			},
 			expectStatusCode:  200,
 			expectRequestBody: []byte(`{"message":"invalid token generation"}`),
@@ -137,8 +138,7 @@ func TestServer_Login(t *testing.T) {
 				Password: "12345678",
 			},
 			mockBehavior: func(s *mockservice.MockAuthorization, user userdata.User) {
-				s.EXPECT().GetHash("12345678").Return("abcd")
-				s.EXPECT().GenerateToken("test", "abcd").Return("123", nil)
+				s.EXPECT().GenerateToken("test", "12345678", gomock.Any()).Return(session.TokenPair{Access: "123", Refresh: "456"}, nil)
 			},
 			expectStatusCode:  200,
 			expectRequestBody: []byte(`"token":"123"`),
@@ -168,8 +168,7 @@ func TestServer_Login(t *testing.T) {
 				Password: "12345678",
 			},
 			mockBehavior: func(s *mockservice.MockAuthorization, user userdata.User) {
-				s.EXPECT().GetHash("12345678").Return("abcd")
-				s.EXPECT().GenerateToken("test", "abcd").Return("", errors.New("invalid password"))
+				s.EXPECT().GenerateToken("test", "12345678", gomock.Any()).Return(session.TokenPair{}, errors.New("invalid password"))
 			},
 			expectStatusCode:  401,
 			expectRequestBody: []byte(`{"message":"invalid password"}`),
@@ -183,8 +182,7 @@ func TestServer_Login(t *testing.T) {
 				Password: "12345678",
 			},
 			mockBehavior: func(s *mockservice.MockAuthorization, user userdata.User) {
-				s.EXPECT().GetHash("12345678").Return("abcd")
-				s.EXPECT().GenerateToken("test", "abcd").Return("", errors.New("invalid user"))
+				s.EXPECT().GenerateToken("test", "12345678", gomock.Any()).Return(session.TokenPair{}, errors.New("invalid user"))
 			},
 			expectStatusCode:  401,
 			expectRequestBody: []byte(`{"message":"invalid user"}`),
@@ -213,3 +211,72 @@ func TestServer_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_RefreshToken(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAuthorization)
+	testingTable := []struct {
+		name              string
+		inputBody         string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name:      "ok",
+			inputBody: `{"refresh_token": "abc"}`,
+			mockBehavior: func(s *mockservice.MockAuthorization) {
+				s.EXPECT().RefreshToken("abc").Return(session.TokenPair{Access: "123", Refresh: "def"}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`{"token":"123","refresh_token":"def"}`),
+		},
+		{
+			name:              "json error",
+			inputBody:         `{231321}`,
+			mockBehavior:      func(s *mockservice.MockAuthorization) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid json input"`),
+		},
+		{
+			name:              "no token",
+			inputBody:         `{}`,
+			mockBehavior:      func(s *mockservice.MockAuthorization) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid struct fields"`),
+		},
+		{
+			name:      "reused token",
+			inputBody: `{"refresh_token": "abc"}`,
+			mockBehavior: func(s *mockservice.MockAuthorization) {
+				s.EXPECT().RefreshToken("abc").Return(session.TokenPair{}, session.ErrRefreshReused)
+			},
+			expectStatusCode:  401,
+			expectRequestBody: []byte(`{"message":"refresh token reuse detected"}`),
+		},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockservice.NewMockAuthorization(c)
+			testCase.mockBehavior(auth)
+
+			services := &service.Service{Authorization: auth}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("POST", "/token/refresh", bytes.NewBufferString(testCase.inputBody))
+			w := httptest.NewRecorder()
+
+			handler.RefreshToken(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
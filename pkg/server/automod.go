@@ -0,0 +1,55 @@
+package server
+
+import (
+	"github.com/gorilla/mux"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"net/http"
+)
+
+func (s *Server) GetHeldItems(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	items, err := s.service.HeldItems(mux.Vars(r)["community"], userID)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	s.writeJSON(w, items, 200)
+}
+
+func (s *Server) ApprovePost(w http.ResponseWriter, r *http.Request) {
+	s.approve(w, r, "")
+}
+
+func (s *Server) ApproveComment(w http.ResponseWriter, r *http.Request) {
+	s.approve(w, r, mux.Vars(r)["comment_id"])
+}
+
+func (s *Server) approve(w http.ResponseWriter, r *http.Request, commID string) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	postID := mux.Vars(r)["post_id"]
+	post, err := s.service.Approve(postID, commID, userID)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	resp, err := utils.MarshalPost(post)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	if _, err = w.Write(resp); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.log.Printf("Successful approval | userID %s | postID %s | commentID %s \n", userID, postID, commID)
+}
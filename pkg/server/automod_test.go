@@ -0,0 +1,128 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
+	mockservice "gitlab.com/vk-go/lectures-2022-2/pkg/service/mocks"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestServer_GetHeldItems(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockModeration)
+	testingTable := []struct {
+		name              string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "ok",
+			mockBehavior: func(s *mockservice.MockModeration) {
+				s.EXPECT().HeldItems("music", "1").Return([]itemdata.HeldItem{
+					{PostID: "abcd", CommentID: "c1", Community: "music", Body: "buy now"},
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"comment_id":"c1"`),
+		},
+		{
+			name: "not a moderator",
+			mockBehavior: func(s *mockservice.MockModeration) {
+				s.EXPECT().HeldItems("music", "1").Return(nil, errors.New("invalid user id"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid user id"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			moderation := mockservice.NewMockModeration(c)
+			testCase.mockBehavior(moderation)
+
+			services := &service.Service{Moderation: moderation}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("GET", "/community/music/held", nil)
+			r = mux.SetURLVars(r, map[string]string{"community": "music"})
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			handler.GetHeldItems(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_ApproveComment(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockModeration)
+	testingTable := []struct {
+		name              string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "ok",
+			mockBehavior: func(s *mockservice.MockModeration) {
+				s.EXPECT().Approve("abcd", "c1", "1").Return(itemdata.Post{ID: "abcd", Type: "text",
+					Comments: []itemdata.Comment{{ID: "c1", Body: "fine after all"}}}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"body":"fine after all"`),
+		},
+		{
+			name: "nothing to approve",
+			mockBehavior: func(s *mockservice.MockModeration) {
+				s.EXPECT().Approve("abcd", "c1", "1").Return(itemdata.Post{}, errors.New("nothing to approve"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"nothing to approve"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			moderation := mockservice.NewMockModeration(c)
+			testCase.mockBehavior(moderation)
+
+			services := &service.Service{Moderation: moderation}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("POST", "/post/abcd/c1/approve", nil)
+			r = mux.SetURLVars(r, map[string]string{"post_id": "abcd", "comment_id": "c1"})
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			handler.ApproveComment(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
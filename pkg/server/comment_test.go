@@ -10,8 +10,10 @@ import (
 	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
 	mockservice "gitlab.com/vk-go/lectures-2022-2/pkg/service/mocks"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
 	"io"
 	"log"
+	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
@@ -36,7 +38,7 @@ func TestServer_CreateComment(t *testing.T) {
 			inputPostID:  "abcd",
 			inputComment: "123",
 			mockBehavior: func(s *mockservice.MockComments, userID string, postID string, comment string) {
-				s.EXPECT().CreateComm(postID, userID, comment).Return(itemdata.Post{
+				s.EXPECT().CreateComm(postID, userID, "", comment).Return(itemdata.Post{
 					ID: postID,
 					Ath: itemdata.Author{
 						ID:       userID,
@@ -70,7 +72,40 @@ func TestServer_CreateComment(t *testing.T) {
 				}, nil)
 			},
 			expectStatusCode:  201,
-			expectRequestBody: []byte(`{"id":"abcd","author":{"id":"1","username":"test"},"comments":[{"author":{"id":"1","username":"test"},"body":"123","created":"2022-11-04T17:55:14Z","id":"111"}],"category":"music","score":1,"type":"text","title":"123","created":"2022-11-04T17:55:14Z","upvotePercentage":100,"views":1,"votes":[{"user":"1","vote":1}],"text":"123"}`),
+			expectRequestBody: []byte(`{"id":"abcd","author":{"id":"1","username":"test"},"comments":[{"author":{"id":"1","username":"test"},"body":"123","created":"2022-11-04T17:55:14Z","id":"111","score":0}],"category":"music","score":1,"type":"text","title":"123","created":"2022-11-04T17:55:14Z","upvotePercentage":100,"views":1,"votes":[{"user":"1","vote":1}],"text":"123"}`),
+		},
+		{
+			name:         "reply",
+			inputBody:    `{"comment":"456","parent_id":"111"}`,
+			inputUserID:  "1",
+			inputPostID:  "abcd",
+			inputComment: "456",
+			mockBehavior: func(s *mockservice.MockComments, userID string, postID string, comment string) {
+				s.EXPECT().CreateComm(postID, userID, "111", comment).Return(itemdata.Post{
+					ID:  postID,
+					Ath: itemdata.Author{ID: userID, Username: "test"},
+					Comments: []itemdata.Comment{
+						{
+							Ath:     itemdata.Author{ID: userID, Username: "test"},
+							Body:    "123",
+							Created: "2022-11-04T17:55:14Z",
+							ID:      "111",
+						},
+						{
+							Ath:      itemdata.Author{ID: userID, Username: "test"},
+							Body:     comment,
+							Created:  "2022-11-04T17:56:14Z",
+							ID:       "222",
+							ParentID: "111",
+						},
+					},
+					Cat:   "music",
+					Type:  "text",
+					Title: "123",
+				}, nil)
+			},
+			expectStatusCode:  201,
+			expectRequestBody: []byte(`"comments":[{"author":{"id":"1","username":"test"},"body":"123","created":"2022-11-04T17:55:14Z","id":"111","score":0,"replies":[{"author":{"id":"1","username":"test"},"body":"456","created":"2022-11-04T17:56:14Z","id":"222","score":0,"parent_id":"111"}]}]`),
 		},
 		{
 			name:              "invalid user id",
@@ -99,7 +134,7 @@ func TestServer_CreateComment(t *testing.T) {
 			inputPostID:  "123",
 			inputComment: "123",
 			mockBehavior: func(s *mockservice.MockComments, userID string, postID string, comment string) {
-				s.EXPECT().CreateComm(postID, userID, comment).Return(itemdata.Post{}, errors.New("invalid user id"))
+				s.EXPECT().CreateComm(postID, userID, "", comment).Return(itemdata.Post{}, errors.New("invalid user id"))
 			},
 			expectStatusCode:  400,
 			expectRequestBody: []byte(`{"message":"invalid user id"}`),
@@ -124,7 +159,7 @@ func TestServer_CreateComment(t *testing.T) {
 			if testCase.name == "invalid user id" {
 				ctx = r.Context()
 			} else {
-				ctx = context.WithValue(r.Context(), "id", testCase.inputUserID)
+				ctx = session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: testCase.inputUserID}})
 			}
 			handler.CreateComment(w, r.WithContext(ctx))
 			resp := w.Result()
@@ -224,7 +259,7 @@ func TestServer_DeleteComment(t *testing.T) {
 			if testCase.name == "invalid user id" {
 				ctx = r.Context()
 			} else {
-				ctx = context.WithValue(r.Context(), "id", testCase.inputUserID)
+				ctx = session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: testCase.inputUserID}})
 			}
 			handler.DeleteComment(w, r.WithContext(ctx))
 			resp := w.Result()
@@ -319,7 +354,7 @@ func TestServer_Upvote(t *testing.T) {
 			if testCase.name == "invalid user id" {
 				ctx = r.Context()
 			} else {
-				ctx = context.WithValue(r.Context(), "id", testCase.inputUserID)
+				ctx = session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: testCase.inputUserID}})
 			}
 			handler.Upvote(w, r.WithContext(ctx))
 			resp := w.Result()
@@ -414,7 +449,7 @@ func TestServer_Downvote(t *testing.T) {
 			if testCase.name == "invalid user id" {
 				ctx = r.Context()
 			} else {
-				ctx = context.WithValue(r.Context(), "id", testCase.inputUserID)
+				ctx = session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: testCase.inputUserID}})
 			}
 			handler.Downvote(w, r.WithContext(ctx))
 			resp := w.Result()
@@ -509,7 +544,7 @@ func TestServer_Unvote(t *testing.T) {
 			if testCase.name == "invalid user id" {
 				ctx = r.Context()
 			} else {
-				ctx = context.WithValue(r.Context(), "id", testCase.inputUserID)
+				ctx = session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: testCase.inputUserID}})
 			}
 			handler.Unvote(w, r.WithContext(ctx))
 			resp := w.Result()
@@ -520,3 +555,225 @@ func TestServer_Unvote(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_VoteComment(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockComments, userID, postID, commID string)
+	testingTable := []struct {
+		name              string
+		handler           func(s *Server) func(w http.ResponseWriter, r *http.Request)
+		inputUserID       string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name:        "upvote",
+			handler:     func(s *Server) func(w http.ResponseWriter, r *http.Request) { return s.UpvoteComment },
+			inputUserID: "2",
+			mockBehavior: func(s *mockservice.MockComments, userID, postID, commID string) {
+				s.EXPECT().UpvoteComment(postID, commID, userID).Return(itemdata.Post{
+					ID:  postID,
+					Ath: itemdata.Author{ID: "1", Username: "test"},
+					Comments: []itemdata.Comment{
+						{
+							Ath:     itemdata.Author{ID: "1", Username: "test"},
+							Body:    "123",
+							Created: "2022-11-04T17:55:14Z",
+							ID:      commID,
+							Score:   1,
+							Votes:   []itemdata.Votes{{User: userID, Vote: 1}},
+						},
+					},
+					Type: "text",
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"id":"111","score":1,"votes":[{"user":"2","vote":1}]}]`),
+		},
+		{
+			name:        "downvote deleted comment",
+			handler:     func(s *Server) func(w http.ResponseWriter, r *http.Request) { return s.DownvoteComment },
+			inputUserID: "2",
+			mockBehavior: func(s *mockservice.MockComments, userID, postID, commID string) {
+				s.EXPECT().DownvoteComment(postID, commID, userID).Return(itemdata.Post{}, errors.New("invalid comment id"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid comment id"}`),
+		},
+		{
+			name:        "unvote without vote",
+			handler:     func(s *Server) func(w http.ResponseWriter, r *http.Request) { return s.UnvoteComment },
+			inputUserID: "2",
+			mockBehavior: func(s *mockservice.MockComments, userID, postID, commID string) {
+				s.EXPECT().UnvoteComment(postID, commID, userID).Return(itemdata.Post{}, errors.New("invalid vote"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid vote"}`),
+		},
+		{
+			name:              "invalid user id",
+			handler:           func(s *Server) func(w http.ResponseWriter, r *http.Request) { return s.UpvoteComment },
+			mockBehavior:      func(s *mockservice.MockComments, userID, postID, commID string) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid user id"}`),
+		},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			comments := mockservice.NewMockComments(c)
+			testCase.mockBehavior(comments, testCase.inputUserID, "abcd", "111")
+
+			services := &service.Service{Comments: comments}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+			r := httptest.NewRequest("GET", "/post/abcd/111/upvote", nil)
+			w := httptest.NewRecorder()
+			r = mux.SetURLVars(r, map[string]string{
+				"post_id":    "abcd",
+				"comment_id": "111",
+			})
+			if testCase.inputUserID != "" {
+				r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: testCase.inputUserID}}))
+			}
+			testCase.handler(handler)(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_EditComment(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockComments)
+	testingTable := []struct {
+		name              string
+		inputBody         string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name:      "ok",
+			inputBody: `{"comment":"fixed"}`,
+			mockBehavior: func(s *mockservice.MockComments) {
+				s.EXPECT().EditComm("abcd", "1", "111", "fixed").Return(itemdata.Post{
+					ID:   "abcd",
+					Type: "text",
+					Comments: []itemdata.Comment{
+						{
+							Ath:     itemdata.Author{ID: "1", Username: "test"},
+							Body:    "fixed",
+							Created: "2022-11-04T17:55:14Z",
+							ID:      "111",
+							Edited:  "2022-11-04T18:55:14Z",
+						},
+					},
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"body":"fixed","created":"2022-11-04T17:55:14Z","id":"111","score":0,"edited":"2022-11-04T18:55:14Z"}`),
+		},
+		{
+			name:      "foreign comment",
+			inputBody: `{"comment":"fixed"}`,
+			mockBehavior: func(s *mockservice.MockComments) {
+				s.EXPECT().EditComm("abcd", "1", "111", "fixed").Return(itemdata.Post{}, errors.New("invalid user id"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid user id"}`),
+		},
+		{
+			name:              "invalid json",
+			inputBody:         `{12}`,
+			mockBehavior:      func(s *mockservice.MockComments) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid json input"}`),
+		},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			comments := mockservice.NewMockComments(c)
+			testCase.mockBehavior(comments)
+
+			services := &service.Service{Comments: comments}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+			r := httptest.NewRequest("PUT", "/post/abcd/111", bytes.NewBufferString(testCase.inputBody))
+			r = mux.SetURLVars(r, map[string]string{"post_id": "abcd", "comment_id": "111"})
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			handler.EditComment(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_GetCommentHistory(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockComments)
+	testingTable := []struct {
+		name              string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "ok",
+			mockBehavior: func(s *mockservice.MockComments) {
+				s.EXPECT().CommentHistory("abcd", "1", "111").Return([]itemdata.Revision{
+					{CommentID: "111", Body: "old", Created: "2022-11-04T17:55:14Z"},
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`[{"comment_id":"111","body":"old","created":"2022-11-04T17:55:14Z"}]`),
+		},
+		{
+			name: "foreign comment",
+			mockBehavior: func(s *mockservice.MockComments) {
+				s.EXPECT().CommentHistory("abcd", "1", "111").Return(nil, errors.New("invalid user id"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid user id"}`),
+		},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			comments := mockservice.NewMockComments(c)
+			testCase.mockBehavior(comments)
+
+			services := &service.Service{Comments: comments}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+			r := httptest.NewRequest("GET", "/post/abcd/111/history", nil)
+			r = mux.SetURLVars(r, map[string]string{"post_id": "abcd", "comment_id": "111"})
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			handler.GetCommentHistory(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
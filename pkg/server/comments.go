@@ -3,6 +3,7 @@ package server
 import (
 	"encoding/json"
 	"github.com/gorilla/mux"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
 	"io"
 	"net/http"
@@ -10,7 +11,7 @@ import (
 
 func (s *Server) CreateComment(w http.ResponseWriter, r *http.Request) {
 	postID := mux.Vars(r)["post_id"]
-	userID, ok := r.Context().Value("id").(string)
+	userID, ok := currentUser(r)
 	if !ok {
 		utils.NewRespError(w, "invalid user id", 400, s.log)
 		return
@@ -26,13 +27,14 @@ func (s *Server) CreateComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	text := struct {
-		Comment string `json:"comment"`
+		Comment  string `json:"comment"`
+		ParentID string `json:"parent_id"`
 	}{}
 	if err = json.Unmarshal(data, &text); err != nil {
 		utils.NewRespError(w, "invalid json input", 400, s.log)
 		return
 	}
-	post, err := s.service.CreateComm(postID, userID, text.Comment)
+	post, err := s.service.CreateComm(postID, userID, text.ParentID, text.Comment)
 	if err != nil {
 		utils.NewRespError(w, err.Error(), 400, nil)
 		return
@@ -51,10 +53,53 @@ func (s *Server) CreateComment(w http.ResponseWriter, r *http.Request) {
 	s.log.Printf("Successful comment creating | userID %s | postID %s \n", userID, postID)
 }
 
+func (s *Server) EditComment(w http.ResponseWriter, r *http.Request) {
+	postID := mux.Vars(r)["post_id"]
+	commID := mux.Vars(r)["comment_id"]
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	if err = r.Body.Close(); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	text := struct {
+		Comment string `json:"comment"`
+	}{}
+	if err = json.Unmarshal(data, &text); err != nil {
+		utils.NewRespError(w, "invalid json input", 400, s.log)
+		return
+	}
+	post, err := s.service.EditComm(postID, userID, commID, text.Comment)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	resp, err := utils.MarshalPost(post)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	if _, err = w.Write(resp); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.log.Printf("Successful comment editing | userID %s | postID %s | commentID %s \n", userID, postID, commID)
+}
+
 func (s *Server) DeleteComment(w http.ResponseWriter, r *http.Request) {
 	postID := mux.Vars(r)["post_id"]
 	commID := mux.Vars(r)["comment_id"]
-	userID, ok := r.Context().Value("id").(string)
+	userID, ok := currentUser(r)
 	if !ok {
 		utils.NewRespError(w, "invalid user id", 400, s.log)
 		return
@@ -79,7 +124,7 @@ func (s *Server) DeleteComment(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) Upvote(w http.ResponseWriter, r *http.Request) {
-	userID, ok := r.Context().Value("id").(string)
+	userID, ok := currentUser(r)
 	if !ok {
 		utils.NewRespError(w, "invalid user id", 400, s.log)
 		return
@@ -105,7 +150,7 @@ func (s *Server) Upvote(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) Downvote(w http.ResponseWriter, r *http.Request) {
-	userID, ok := r.Context().Value("id").(string)
+	userID, ok := currentUser(r)
 	if !ok {
 		utils.NewRespError(w, "invalid user id", 500, s.log)
 		return
@@ -131,7 +176,7 @@ func (s *Server) Downvote(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) Unvote(w http.ResponseWriter, r *http.Request) {
-	userID, ok := r.Context().Value("id").(string)
+	userID, ok := currentUser(r)
 	if !ok {
 		utils.NewRespError(w, "invalid user id", 400, s.log)
 		return
@@ -155,3 +200,68 @@ func (s *Server) Unvote(w http.ResponseWriter, r *http.Request) {
 	}
 	s.log.Printf("Successful post unvote | userID %s | postID %s \n", userID, postID)
 }
+
+func (s *Server) UpvoteComment(w http.ResponseWriter, r *http.Request) {
+	s.voteComment(w, r, s.service.UpvoteComment, "upvote")
+}
+
+func (s *Server) DownvoteComment(w http.ResponseWriter, r *http.Request) {
+	s.voteComment(w, r, s.service.DownvoteComment, "downvote")
+}
+
+func (s *Server) UnvoteComment(w http.ResponseWriter, r *http.Request) {
+	s.voteComment(w, r, s.service.UnvoteComment, "unvote")
+}
+
+func (s *Server) voteComment(w http.ResponseWriter, r *http.Request,
+	vote func(postID, commentID, userID string) (itemdata.Post, error), action string) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	postID := mux.Vars(r)["post_id"]
+	commID := mux.Vars(r)["comment_id"]
+	post, err := vote(postID, commID, userID)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	resp, err := utils.MarshalPost(post)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	if _, err = w.Write(resp); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.log.Printf("Successful comment %s | userID %s | postID %s | commentID %s \n", action, userID, postID, commID)
+}
+
+func (s *Server) GetCommentHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	vars := mux.Vars(r)
+	revisions, err := s.service.CommentHistory(vars["post_id"], userID, vars["comment_id"])
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	resp, err := json.Marshal(revisions)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	if _, err = w.Write(resp); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"io"
+	"net/http"
+)
+
+func (s *Server) GetCommunities(w http.ResponseWriter, r *http.Request) {
+	communities, err := s.service.GetCommunities()
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.writeJSON(w, communities, 200)
+}
+
+func (s *Server) GetCommunity(w http.ResponseWriter, r *http.Request) {
+	community, err := s.service.GetCommunity(mux.Vars(r)["community"])
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 404, s.log)
+		return
+	}
+	s.writeJSON(w, community, 200)
+}
+
+func (s *Server) CreateCommunity(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	body, ok := s.readCommunity(w, r)
+	if !ok {
+		return
+	}
+	community, err := s.service.CreateCommunity(body.Name, body.Description, userID)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	s.writeJSON(w, community, 201)
+	s.log.Printf("Successful community creating | userID %s | community %s \n", userID, community.Name)
+}
+
+func (s *Server) EditCommunity(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	body, ok := s.readCommunity(w, r)
+	if !ok {
+		return
+	}
+	name := mux.Vars(r)["community"]
+	community, err := s.service.EditCommunity(name, userID, body.Description)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	s.writeJSON(w, community, 200)
+	s.log.Printf("Successful community editing | userID %s | community %s \n", userID, name)
+}
+
+func (s *Server) Subscribe(w http.ResponseWriter, r *http.Request) {
+	s.subscription(w, r, s.service.Subscribe, "subscribe")
+}
+
+func (s *Server) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	s.subscription(w, r, s.service.Unsubscribe, "unsubscribe")
+}
+
+func (s *Server) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	names, err := s.service.Subscriptions(userID)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.writeJSON(w, names, 200)
+}
+
+func (s *Server) subscription(w http.ResponseWriter, r *http.Request,
+	change func(name, userID string) (communitydata.Community, error), action string) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	name := mux.Vars(r)["community"]
+	community, err := change(name, userID)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	s.writeJSON(w, community, 200)
+	s.log.Printf("Successful community %s | userID %s | community %s \n", action, userID, name)
+}
+
+type communityBody struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (s *Server) readCommunity(w http.ResponseWriter, r *http.Request) (communityBody, bool) {
+	var body communityBody
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return body, false
+	}
+	if err = r.Body.Close(); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return body, false
+	}
+	if err = json.Unmarshal(data, &body); err != nil {
+		utils.NewRespError(w, "invalid json input", 400, s.log)
+		return body, false
+	}
+	return body, true
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, v interface{}, code int) {
+	resp, err := json.Marshal(v)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	if _, err = w.Write(resp); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
+	mockservice "gitlab.com/vk-go/lectures-2022-2/pkg/service/mocks"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestServer_CreateCommunity(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockCommunities)
+	testingTable := []struct {
+		name              string
+		inputBody         string
+		userID            string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name:      "ok",
+			inputBody: `{"name":"golang","description":"gophers"}`,
+			userID:    "1",
+			mockBehavior: func(s *mockservice.MockCommunities) {
+				s.EXPECT().CreateCommunity("golang", "gophers", "1").Return(communitydata.Community{
+					Name: "golang", Description: "gophers", OwnerID: "1", Created: "2022-11-04T17:55:14Z", Subscribers: 1,
+				}, nil)
+			},
+			expectStatusCode:  201,
+			expectRequestBody: []byte(`{"name":"golang","description":"gophers","owner_id":"1","created":"2022-11-04T17:55:14Z","subscribers":1}`),
+		},
+		{
+			name:              "invalid user id",
+			inputBody:         `{"name":"golang"}`,
+			mockBehavior:      func(s *mockservice.MockCommunities) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid user id"`),
+		},
+		{
+			name:              "invalid json input",
+			inputBody:         `{"name":`,
+			userID:            "1",
+			mockBehavior:      func(s *mockservice.MockCommunities) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid json input"`),
+		},
+		{
+			name:      "already exists",
+			inputBody: `{"name":"music"}`,
+			userID:    "1",
+			mockBehavior: func(s *mockservice.MockCommunities) {
+				s.EXPECT().CreateCommunity("music", "", "1").Return(communitydata.Community{}, errors.New("community already exists"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"community already exists"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			communities := mockservice.NewMockCommunities(c)
+			testCase.mockBehavior(communities)
+
+			services := &service.Service{Communities: communities}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("POST", "/communities", bytes.NewBufferString(testCase.inputBody))
+			if testCase.userID != "" {
+				r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: testCase.userID}}))
+			}
+			w := httptest.NewRecorder()
+			handler.CreateCommunity(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_Subscribe(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockCommunities)
+	testingTable := []struct {
+		name              string
+		unsubscribe       bool
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "subscribe",
+			mockBehavior: func(s *mockservice.MockCommunities) {
+				s.EXPECT().Subscribe("golang", "1").Return(communitydata.Community{Name: "golang", Subscribers: 2}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"subscribers":2`),
+		},
+		{
+			name:        "unsubscribe",
+			unsubscribe: true,
+			mockBehavior: func(s *mockservice.MockCommunities) {
+				s.EXPECT().Unsubscribe("golang", "1").Return(communitydata.Community{Name: "golang", Subscribers: 1}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"subscribers":1`),
+		},
+		{
+			name:        "invalid subscription",
+			unsubscribe: true,
+			mockBehavior: func(s *mockservice.MockCommunities) {
+				s.EXPECT().Unsubscribe("golang", "1").Return(communitydata.Community{}, errors.New("invalid subscription"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid subscription"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			communities := mockservice.NewMockCommunities(c)
+			testCase.mockBehavior(communities)
+
+			services := &service.Service{Communities: communities}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("POST", "/community/golang/subscribe", bytes.NewBufferString(""))
+			r = mux.SetURLVars(r, map[string]string{"community": "golang"})
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			if testCase.unsubscribe {
+				handler.Unsubscribe(w, r)
+			} else {
+				handler.Subscribe(w, r)
+			}
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
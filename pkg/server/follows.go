@@ -0,0 +1,28 @@
+package server
+
+import (
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"net/http"
+)
+
+func (s *Server) GetFollowing(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	logins, err := s.service.Following(userID)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.writeJSON(w, logins, 200)
+}
+
+func (s *Server) FollowUser(w http.ResponseWriter, r *http.Request) {
+	s.changeRelation(w, r, s.service.FollowUser, "follow")
+}
+
+func (s *Server) UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	s.changeRelation(w, r, s.service.UnfollowUser, "unfollow")
+}
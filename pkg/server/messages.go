@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"io"
+	"net/http"
+)
+
+func (s *Server) SendMessage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	if err = r.Body.Close(); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	body := struct {
+		To   string `json:"to"`
+		Body string `json:"body"`
+	}{}
+	if err = json.Unmarshal(data, &body); err != nil || body.To == "" {
+		utils.NewRespError(w, "invalid json input", 400, s.log)
+		return
+	}
+	msg, err := s.service.SendMessage(userID, body.To, body.Body)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	s.writeJSON(w, msg, 201)
+	s.log.Printf("Successful message | userID %s | to %s \n", userID, body.To)
+}
+
+// GetInbox lists the received messages newest first, ?unread=true leaves out the read ones.
+func (s *Server) GetInbox(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	limit, err := limitParam(r)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	messages, err := s.service.Inbox(userID, limit, r.URL.Query().Get("unread") == "true")
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.writeJSON(w, messages, 200)
+}
+
+func (s *Server) GetSent(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	limit, err := limitParam(r)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	messages, err := s.service.Sent(userID, limit)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.writeJSON(w, messages, 200)
+}
+
+func (s *Server) GetConversation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	messages, err := s.service.Conversation(mux.Vars(r)["conversation_id"], userID)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 404, s.log)
+		return
+	}
+	s.writeJSON(w, messages, 200)
+}
+
+func (s *Server) GetBlocked(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	logins, err := s.service.BlockedUsers(userID)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.writeJSON(w, logins, 200)
+}
+
+func (s *Server) BlockUser(w http.ResponseWriter, r *http.Request) {
+	s.changeRelation(w, r, s.service.BlockUser, "block")
+}
+
+func (s *Server) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	s.changeRelation(w, r, s.service.UnblockUser, "unblock")
+}
+
+// changeRelation blocks, follows or undoes that for the user in the path.
+func (s *Server) changeRelation(w http.ResponseWriter, r *http.Request, change func(userID, login string) error,
+	action string) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	login := mux.Vars(r)["user_login"]
+	if err := change(userID, login); err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	utils.NewRespError(w, "success", 200, s.log)
+	s.log.Printf("Successful %s | userID %s | login %s \n", action, userID, login)
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"github.com/golang/mock/gomock"
+	messagedata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/messageData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
+	mockservice "gitlab.com/vk-go/lectures-2022-2/pkg/service/mocks"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestServer_SendMessage(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockMessages)
+	testingTable := []struct {
+		name              string
+		inputBody         string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name:      "ok",
+			inputBody: `{"to": "bob", "body": "hi"}`,
+			mockBehavior: func(s *mockservice.MockMessages) {
+				s.EXPECT().SendMessage("1", "bob", "hi").Return(messagedata.Message{ID: "4", Conversation: "1-2",
+					From: "alice", To: "bob", Body: "hi"}, nil)
+			},
+			expectStatusCode:  201,
+			expectRequestBody: []byte(`"conversation_id":"1-2","from":"alice","to":"bob"`),
+		},
+		{
+			name:      "blocked",
+			inputBody: `{"to": "bob", "body": "hi"}`,
+			mockBehavior: func(s *mockservice.MockMessages) {
+				s.EXPECT().SendMessage("1", "bob", "hi").Return(messagedata.Message{},
+					errors.New("blocked by the recipient"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"blocked by the recipient"`),
+		},
+		{
+			name:              "no recipient",
+			inputBody:         `{"body": "hi"}`,
+			mockBehavior:      func(s *mockservice.MockMessages) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid json input"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			messages := mockservice.NewMockMessages(c)
+			testCase.mockBehavior(messages)
+
+			services := &service.Service{Messages: messages}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("POST", "/api/messages", strings.NewReader(testCase.inputBody))
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			handler.SendMessage(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
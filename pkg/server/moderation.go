@@ -0,0 +1,174 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+func (s *Server) GetModerators(w http.ResponseWriter, r *http.Request) {
+	moderators, err := s.service.Moderators(mux.Vars(r)["community"])
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 404, s.log)
+		return
+	}
+	s.writeJSON(w, moderators, 200)
+}
+
+func (s *Server) AddModerator(w http.ResponseWriter, r *http.Request) {
+	s.changeModerators(w, r, s.service.AddModerator, "adding")
+}
+
+func (s *Server) RemoveModerator(w http.ResponseWriter, r *http.Request) {
+	s.changeModerators(w, r, s.service.RemoveModerator, "removing")
+}
+
+func (s *Server) changeModerators(w http.ResponseWriter, r *http.Request,
+	change func(name, userID, login string) ([]string, error), action string) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	name := mux.Vars(r)["community"]
+	login := mux.Vars(r)["user_login"]
+	moderators, err := change(name, userID, login)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	s.writeJSON(w, moderators, 200)
+	s.log.Printf("Successful moderator %s | userID %s | community %s | login %s \n", action, userID, name, login)
+}
+
+func (s *Server) LockPost(w http.ResponseWriter, r *http.Request) {
+	s.moderatePost(w, r, s.service.LockPost, "lock")
+}
+
+func (s *Server) UnlockPost(w http.ResponseWriter, r *http.Request) {
+	s.moderatePost(w, r, s.service.UnlockPost, "unlock")
+}
+
+func (s *Server) PinPost(w http.ResponseWriter, r *http.Request) {
+	s.moderatePost(w, r, s.service.PinPost, "pin")
+}
+
+func (s *Server) UnpinPost(w http.ResponseWriter, r *http.Request) {
+	s.moderatePost(w, r, s.service.UnpinPost, "unpin")
+}
+
+func (s *Server) moderatePost(w http.ResponseWriter, r *http.Request,
+	change func(postID, userID, reason string) (itemdata.Post, error), action string) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	reason, ok := s.readReason(w, r)
+	if !ok {
+		return
+	}
+	postID := mux.Vars(r)["post_id"]
+	post, err := change(postID, userID, reason)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	resp, err := utils.MarshalPost(post)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	if _, err = w.Write(resp); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.log.Printf("Successful post %s | userID %s | postID %s \n", action, userID, postID)
+}
+
+func (s *Server) BanUser(w http.ResponseWriter, r *http.Request) {
+	s.changeBan(w, r, s.service.BanUser, "ban")
+}
+
+func (s *Server) UnbanUser(w http.ResponseWriter, r *http.Request) {
+	s.changeBan(w, r, s.service.UnbanUser, "unban")
+}
+
+func (s *Server) changeBan(w http.ResponseWriter, r *http.Request,
+	change func(name, userID, login, reason string) error, action string) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	reason, ok := s.readReason(w, r)
+	if !ok {
+		return
+	}
+	name := mux.Vars(r)["community"]
+	login := mux.Vars(r)["user_login"]
+	if err := change(name, userID, login, reason); err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	utils.NewRespError(w, "success", 200, s.log)
+	s.log.Printf("Successful user %s | userID %s | community %s | login %s \n", action, userID, name, login)
+}
+
+func (s *Server) GetModLog(w http.ResponseWriter, r *http.Request) {
+	limit, err := limitParam(r)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	actions, err := s.service.ModLog(mux.Vars(r)["community"], limit)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 404, s.log)
+		return
+	}
+	s.writeJSON(w, actions, 200)
+}
+
+// limitParam reads ?limit=, DefaultPageSize when it is missing.
+func limitParam(r *http.Request) (int, error) {
+	param := r.URL.Query().Get("limit")
+	if param == "" {
+		return itemdata.DefaultPageSize, nil
+	}
+	limit, err := strconv.Atoi(param)
+	if err != nil || limit < 1 || limit > itemdata.MaxPageSize {
+		return 0, errors.New("invalid limit")
+	}
+	return limit, nil
+}
+
+// readReason reads the optional {"reason": ...} body of moderation requests.
+func (s *Server) readReason(w http.ResponseWriter, r *http.Request) (string, bool) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return "", false
+	}
+	if err = r.Body.Close(); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return "", false
+	}
+	if len(data) == 0 {
+		return "", true
+	}
+	body := struct {
+		Reason string `json:"reason"`
+	}{}
+	if err = json.Unmarshal(data, &body); err != nil {
+		utils.NewRespError(w, "invalid json input", 400, s.log)
+		return "", false
+	}
+	return body.Reason, true
+}
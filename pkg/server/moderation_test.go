@@ -0,0 +1,209 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
+	mockservice "gitlab.com/vk-go/lectures-2022-2/pkg/service/mocks"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestServer_LockPost(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockModeration)
+	testingTable := []struct {
+		name              string
+		inputBody         string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name:      "ok",
+			inputBody: `{"reason":"off topic"}`,
+			mockBehavior: func(s *mockservice.MockModeration) {
+				s.EXPECT().LockPost("abcd", "1", "off topic").Return(itemdata.Post{ID: "abcd", Type: "text", Locked: true}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"locked":true`),
+		},
+		{
+			name: "without reason",
+			mockBehavior: func(s *mockservice.MockModeration) {
+				s.EXPECT().LockPost("abcd", "1", "").Return(itemdata.Post{ID: "abcd", Type: "text", Locked: true}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"locked":true`),
+		},
+		{
+			name:      "not a moderator",
+			inputBody: `{}`,
+			mockBehavior: func(s *mockservice.MockModeration) {
+				s.EXPECT().LockPost("abcd", "1", "").Return(itemdata.Post{}, errors.New("invalid user id"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid user id"`),
+		},
+		{
+			name:              "invalid json input",
+			inputBody:         `{"reason":`,
+			mockBehavior:      func(s *mockservice.MockModeration) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid json input"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			moderation := mockservice.NewMockModeration(c)
+			testCase.mockBehavior(moderation)
+
+			services := &service.Service{Moderation: moderation}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("POST", "/post/abcd/lock", bytes.NewBufferString(testCase.inputBody))
+			r = mux.SetURLVars(r, map[string]string{"post_id": "abcd"})
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			handler.LockPost(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_BanUser(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockModeration)
+	testingTable := []struct {
+		name              string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "ok",
+			mockBehavior: func(s *mockservice.MockModeration) {
+				s.EXPECT().BanUser("golang", "1", "spammer", "spam").Return(nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"message":"success"`),
+		},
+		{
+			name: "moderator",
+			mockBehavior: func(s *mockservice.MockModeration) {
+				s.EXPECT().BanUser("golang", "1", "spammer", "spam").Return(errors.New("moderator can not be banned"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"moderator can not be banned"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			moderation := mockservice.NewMockModeration(c)
+			testCase.mockBehavior(moderation)
+
+			services := &service.Service{Moderation: moderation}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("PUT", "/community/golang/bans/spammer", bytes.NewBufferString(`{"reason":"spam"}`))
+			r = mux.SetURLVars(r, map[string]string{"community": "golang", "user_login": "spammer"})
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			handler.BanUser(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_GetModLog(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockModeration)
+	testingTable := []struct {
+		name              string
+		url               string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "ok",
+			url:  "/community/golang/modlog?limit=1",
+			mockBehavior: func(s *mockservice.MockModeration) {
+				s.EXPECT().ModLog("golang", 1).Return([]communitydata.ModAction{
+					{ID: "1", Community: "golang", Moderator: "admin", Action: "pin", Target: "abcd", Created: "2022-11-04T17:55:14Z"},
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`[{"id":"1","community":"golang","moderator":"admin","action":"pin","target":"abcd","created":"2022-11-04T17:55:14Z"}]`),
+		},
+		{
+			name:              "invalid limit",
+			url:               "/community/golang/modlog?limit=0",
+			mockBehavior:      func(s *mockservice.MockModeration) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid limit"`),
+		},
+		{
+			name: "invalid community",
+			url:  "/community/golang/modlog",
+			mockBehavior: func(s *mockservice.MockModeration) {
+				s.EXPECT().ModLog("golang", 25).Return(nil, errors.New("invalid community"))
+			},
+			expectStatusCode:  404,
+			expectRequestBody: []byte(`"message":"invalid community"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			moderation := mockservice.NewMockModeration(c)
+			testCase.mockBehavior(moderation)
+
+			services := &service.Service{Moderation: moderation}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("GET", testCase.url, bytes.NewBufferString(""))
+			r = mux.SetURLVars(r, map[string]string{"community": "golang"})
+			w := httptest.NewRecorder()
+			handler.GetModLog(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"github.com/gorilla/mux"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"net/http"
+)
+
+// GetNotifications lists the newest notifications first, ?unread=true leaves out the read ones.
+func (s *Server) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	limit, err := limitParam(r)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	notes, err := s.service.ListNotifications(userID, limit, r.URL.Query().Get("unread") == "true")
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.writeJSON(w, notes, 200)
+}
+
+func (s *Server) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	count, err := s.service.UnreadCount(userID)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.writeJSON(w, struct {
+		Unread int `json:"unread"`
+	}{Unread: count}, 200)
+}
+
+func (s *Server) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	if err := s.service.MarkRead(userID, mux.Vars(r)["notification_id"]); err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	utils.NewRespError(w, "success", 200, s.log)
+}
+
+func (s *Server) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	if err := s.service.MarkAllRead(userID); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	utils.NewRespError(w, "success", 200, s.log)
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
+	mockservice "gitlab.com/vk-go/lectures-2022-2/pkg/service/mocks"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestServer_GetNotifications(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockNotifications)
+	testingTable := []struct {
+		name              string
+		query             string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name:  "unread",
+			query: "?unread=true&limit=5",
+			mockBehavior: func(s *mockservice.MockNotifications) {
+				s.EXPECT().ListNotifications("1", 5, true).Return([]notificationdata.Notification{
+					{ID: "3", Kind: notificationdata.KindReply, Actor: "bob", PostID: "abcd"},
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"kind":"reply","actor":"bob"`),
+		},
+		{
+			name:              "bad limit",
+			query:             "?limit=0",
+			mockBehavior:      func(s *mockservice.MockNotifications) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid limit"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			notifications := mockservice.NewMockNotifications(c)
+			testCase.mockBehavior(notifications)
+
+			services := &service.Service{Notifications: notifications}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("GET", "/api/notifications"+testCase.query, nil)
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			handler.GetNotifications(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_MarkNotificationRead(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockNotifications)
+	testingTable := []struct {
+		name              string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "ok",
+			mockBehavior: func(s *mockservice.MockNotifications) {
+				s.EXPECT().MarkRead("1", "3").Return(nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"message":"success"`),
+		},
+		{
+			name: "someone else's",
+			mockBehavior: func(s *mockservice.MockNotifications) {
+				s.EXPECT().MarkRead("1", "3").Return(errors.New("invalid notification"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid notification"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			notifications := mockservice.NewMockNotifications(c)
+			testCase.mockBehavior(notifications)
+
+			services := &service.Service{Notifications: notifications}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("POST", "/api/notifications/3/read", nil)
+			r = mux.SetURLVars(r, map[string]string{"notification_id": "3"})
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			handler.MarkNotificationRead(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
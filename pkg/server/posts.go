@@ -1,36 +1,41 @@
 package server
 
 import (
+	"encoding/json"
+	"errors"
 	"github.com/asaskevich/govalidator"
 	"github.com/gorilla/mux"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/imaging"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
 	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	multipartMemory   = 1 << 20
+	multipartOverhead = 1 << 20
 )
 
 func (s *Server) CreatePost(w http.ResponseWriter, r *http.Request) {
-	id, ok := r.Context().Value("id").(string)
+	id, ok := currentUser(r)
 	if !ok {
 		utils.NewRespError(w, "invalid user id", 400, s.log)
 		return
 	}
-	var buf []byte
-	post := itemdata.CreatePost{}
-	buf, err := io.ReadAll(r.Body)
-	if err != nil {
-		utils.NewRespError(w, err.Error(), 500, s.log)
-		return
-	}
-	if err = r.Body.Close(); err != nil {
-		utils.NewRespError(w, err.Error(), 500, s.log)
-		return
+	var post itemdata.CreatePost
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		post, ok = s.readImagePost(w, r)
+	} else {
+		post, ok = s.readPost(w, r)
 	}
-	if err = utils.UnmarshalCreatPost(buf, &post); err != nil {
-		utils.NewRespError(w, "invalid json input", 400, s.log)
+	if !ok {
 		return
 	}
-	if _, err = govalidator.ValidateStruct(post); err != nil {
+	if _, err := govalidator.ValidateStruct(post); err != nil {
 		utils.NewRespError(w, "invalid struct fields", 400, s.log)
 		return
 	}
@@ -53,37 +58,216 @@ func (s *Server) CreatePost(w http.ResponseWriter, r *http.Request) {
 	s.log.Printf("Successful post creating | userID %s | postID %s \n", id, resPost.ID)
 }
 
-func (s *Server) GetPosts(w http.ResponseWriter, _ *http.Request) {
-	posts, err := s.service.GetPosts()
+func (s *Server) readPost(w http.ResponseWriter, r *http.Request) (itemdata.CreatePost, bool) {
+	post := itemdata.CreatePost{}
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return post, false
+	}
+	if err = r.Body.Close(); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return post, false
+	}
+	if err = utils.UnmarshalCreatPost(buf, &post); err != nil {
+		utils.NewRespError(w, "invalid json input", 400, s.log)
+		return post, false
+	}
+	return post, true
+}
+
+// readImagePost reads an image post from a multipart form with the category,
+// title and text fields and the file in the image field.
+func (s *Server) readImagePost(w http.ResponseWriter, r *http.Request) (itemdata.CreatePost, bool) {
+	post := itemdata.CreatePost{}
+	r.Body = http.MaxBytesReader(w, r.Body, imaging.MaxUploadSize+multipartOverhead)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		utils.NewRespError(w, "invalid multipart input", 400, s.log)
+		return post, false
+	}
+	defer r.MultipartForm.RemoveAll()
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		utils.NewRespError(w, "invalid image", 400, s.log)
+		return post, false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, imaging.MaxUploadSize+1))
 	if err != nil {
 		utils.NewRespError(w, err.Error(), 500, s.log)
+		return post, false
+	}
+	if len(data) > imaging.MaxUploadSize {
+		utils.NewRespError(w, imaging.ErrTooLarge.Error(), 413, s.log)
+		return post, false
+	}
+	post.Cat = r.FormValue("category")
+	post.Title = r.FormValue("title")
+	post.Type = "image"
+	post.Text = r.FormValue("text")
+	post.Image = data
+	return post, true
+}
+
+func (s *Server) GetPosts(w http.ResponseWriter, r *http.Request) {
+	opts, paged, err := listOptions(r)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
 		return
 	}
-	resp, err := utils.MarshalSlice(posts)
+	page, err := s.service.GetPosts(opts)
 	if err != nil {
 		utils.NewRespError(w, err.Error(), 500, s.log)
 		return
 	}
-	w.Header().Add("content-type", "application/json; charset=utf-8")
-	w.WriteHeader(200)
-	if _, err = w.Write(resp); err != nil {
+	s.writePosts(w, r, page, paged)
+}
+
+// GetFeed is the home listing of the logged in user, the global one for anonymous requests.
+func (s *Server) GetFeed(w http.ResponseWriter, r *http.Request) {
+	opts, paged, err := listOptions(r)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	page, err := s.service.GetFeed(opts)
+	if err != nil {
 		utils.NewRespError(w, err.Error(), 500, s.log)
 		return
 	}
+	s.writePosts(w, r, page, paged)
 }
 
 func (s *Server) GetCategory(w http.ResponseWriter, r *http.Request) {
 	cat := mux.Vars(r)["category"]
-	if !govalidator.IsIn(cat, "music", "funny", "videos", "programming", "news", "fashion", "all") {
-		utils.NewRespError(w, "invalid category", 400, s.log)
+	if cat != communitydata.AllCommunities {
+		if _, err := s.service.GetCommunity(cat); err != nil {
+			utils.NewRespError(w, "invalid category", 400, s.log)
+			return
+		}
+	}
+	opts, paged, err := listOptions(r)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	page, err := s.service.GetCategory(cat, opts)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.writePosts(w, r, page, paged)
+}
+
+func (s *Server) GetUser(w http.ResponseWriter, r *http.Request) {
+	usr := mux.Vars(r)["user_login"]
+	opts, paged, err := listOptions(r)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	page, err := s.service.GetName(usr, opts)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
 		return
 	}
-	posts, err := s.service.GetCategory(cat)
+	s.writePosts(w, r, page, paged)
+}
+
+// listOptions reads ?sort=, ?t=, ?limit= and ?after=. Requests without limit
+// and after get the posts as a plain array, like before pagination was added,
+// see writePosts. A logged in user is the viewer of the listing.
+func listOptions(r *http.Request) (itemdata.ListOptions, bool, error) {
+	query := r.URL.Query()
+	opts := itemdata.ListOptions{Limit: itemdata.MaxPageSize, Sort: itemdata.SortTop}
+	opts.Viewer, _ = currentUser(r)
+	if sort := query.Get("sort"); sort != "" {
+		if !itemdata.ValidSort(sort) {
+			return opts, false, errors.New("invalid sort")
+		}
+		opts.Sort = sort
+	}
+	if opts.Sort == itemdata.SortTop || opts.Sort == itemdata.SortControversial {
+		if t := query.Get("t"); t != "" {
+			window, ok := itemdata.Windows[t]
+			if !ok {
+				return opts, false, errors.New("invalid time window")
+			}
+			opts.Window = window
+		}
+	}
+	if !query.Has("limit") && !query.Has("after") {
+		return opts, false, nil
+	}
+	opts.Limit = itemdata.DefaultPageSize
+	if query.Has("limit") {
+		limit, err := strconv.Atoi(query.Get("limit"))
+		if err != nil || limit < 1 || limit > itemdata.MaxPageSize {
+			return opts, true, errors.New("invalid limit")
+		}
+		opts.Limit = limit
+	}
+	if after := query.Get("after"); after != "" {
+		cursor, err := itemdata.DecodeCursor(after)
+		if err != nil {
+			return opts, true, err
+		}
+		if cursor.Sort != opts.Sort {
+			return opts, true, errors.New("invalid cursor")
+		}
+		opts.After = cursor
+	}
+	return opts, true, nil
+}
+
+// writePosts answers a paged request with a page and an unpaged one with a
+// plain array. When an unpaged listing hits the MaxPageSize cap, the rest is
+// linked in the Link header, the shape of the body does not change.
+func (s *Server) writePosts(w http.ResponseWriter, r *http.Request, page itemdata.PostPage, paged bool) {
+	var resp []byte
+	var err error
+	if paged {
+		resp, err = utils.MarshalPage(page)
+	} else {
+		resp, err = utils.MarshalSlice(page.Posts)
+	}
 	if err != nil {
 		utils.NewRespError(w, err.Error(), 500, s.log)
 		return
 	}
-	resp, err := utils.MarshalSlice(posts)
+	if !paged && page.Next != "" {
+		next := *r.URL
+		query := next.Query()
+		query.Set("limit", strconv.Itoa(itemdata.MaxPageSize))
+		query.Set("after", page.Next)
+		next.RawQuery = query.Encode()
+		w.Header().Add("Link", "<"+next.RequestURI()+`>; rel="next"`)
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	if _, err = w.Write(resp); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+}
+
+func (s *Server) GetPostID(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["post_id"]
+	order := r.URL.Query().Get("sort")
+	if order == "" {
+		order = "old"
+	}
+	if !itemdata.ValidCommentOrder(order) {
+		utils.NewRespError(w, "invalid sort", 400, s.log)
+		return
+	}
+	viewerID, _ := currentUser(r)
+	post, err := s.service.GetPostID(id, order, viewerID)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, nil)
+		return
+	}
+	resp, err := utils.MarshalPost(post)
 	if err != nil {
 		utils.NewRespError(w, err.Error(), 500, s.log)
 		return
@@ -96,14 +280,44 @@ func (s *Server) GetCategory(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (s *Server) GetUser(w http.ResponseWriter, r *http.Request) {
-	usr := mux.Vars(r)["user_login"]
-	posts, err := s.service.GetName(usr)
+func (s *Server) EditPost(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	postID := mux.Vars(r)["post_id"]
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	if err = r.Body.Close(); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	body := struct {
+		Text string `json:"text"`
+		URL  string `json:"url"`
+	}{}
+	if err = json.Unmarshal(buf, &body); err != nil {
+		utils.NewRespError(w, "invalid json input", 400, s.log)
+		return
+	}
+	text := body.Text
+	if body.URL != "" {
+		text = body.URL
+	}
+	if text == "" {
+		utils.NewRespError(w, "empty text", 400, s.log)
+		return
+	}
+	post, err := s.service.EditPost(postID, userID, text)
 	if err != nil {
 		utils.NewRespError(w, err.Error(), 400, s.log)
 		return
 	}
-	resp, err := utils.MarshalSlice(posts)
+	resp, err := utils.MarshalPost(post)
 	if err != nil {
 		utils.NewRespError(w, err.Error(), 500, s.log)
 		return
@@ -114,16 +328,22 @@ func (s *Server) GetUser(w http.ResponseWriter, r *http.Request) {
 		utils.NewRespError(w, err.Error(), 500, s.log)
 		return
 	}
+	s.log.Printf("Successful post editing | userID %s | postID %s \n", userID, postID)
 }
 
-func (s *Server) GetPostID(w http.ResponseWriter, r *http.Request) {
-	id := mux.Vars(r)["post_id"]
-	post, err := s.service.GetPostID(id)
+func (s *Server) GetPostHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	postID := mux.Vars(r)["post_id"]
+	revisions, err := s.service.PostHistory(postID, userID)
 	if err != nil {
-		utils.NewRespError(w, err.Error(), 400, nil)
+		utils.NewRespError(w, err.Error(), 400, s.log)
 		return
 	}
-	resp, err := utils.MarshalPost(post)
+	resp, err := json.Marshal(revisions)
 	if err != nil {
 		utils.NewRespError(w, err.Error(), 500, s.log)
 		return
@@ -137,7 +357,7 @@ func (s *Server) GetPostID(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) DeletePost(w http.ResponseWriter, r *http.Request) {
-	userID, ok := r.Context().Value("id").(string)
+	userID, ok := currentUser(r)
 	if !ok {
 		utils.NewRespError(w, "invalid user id", 400, s.log)
 		return
@@ -6,14 +6,19 @@ import (
 	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/imaging"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
 	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
 	mockservice "gitlab.com/vk-go/lectures-2022-2/pkg/service/mocks"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
 	"io"
 	"log"
+	"mime/multipart"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestServer_CreatePost(t *testing.T) {
@@ -125,7 +130,7 @@ func TestServer_CreatePost(t *testing.T) {
 			if testCase.name == "invalid user id" {
 				ctx = r.Context()
 			} else {
-				ctx = context.WithValue(r.Context(), "id", testCase.inputUserID)
+				ctx = session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: testCase.inputUserID}})
 			}
 			handler.CreatePost(w, r.WithContext(ctx))
 			resp := w.Result()
@@ -137,6 +142,129 @@ func TestServer_CreatePost(t *testing.T) {
 	}
 }
 
+func TestServer_CreateImagePost(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockPosts, post itemdata.CreatePost)
+	testingTable := []struct {
+		name              string
+		fields            map[string]string
+		image             []byte
+		inputPost         itemdata.CreatePost
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name:   "ok",
+			fields: map[string]string{"category": "music", "title": "cat", "text": "my cat"},
+			image:  []byte("\x89PNG"),
+			inputPost: itemdata.CreatePost{
+				Cat:   "music",
+				Title: "cat",
+				Type:  "image",
+				Text:  "my cat",
+				Image: []byte("\x89PNG"),
+			},
+			mockBehavior: func(s *mockservice.MockPosts, post itemdata.CreatePost) {
+				s.EXPECT().CreatePost(post, "1").Return(itemdata.Post{
+					ID:    "abcd",
+					Cat:   post.Cat,
+					Type:  post.Type,
+					Title: post.Title,
+					Text:  post.Text,
+					Image: &itemdata.Image{URL: "/media/a.png", ThumbnailURL: "/media/a_thumb.png",
+						ContentType: "image/png", Width: 2, Height: 1, Keys: []string{"a.png", "a_thumb.png"}},
+				}, nil)
+			},
+			expectStatusCode:  201,
+			expectRequestBody: []byte(`"image":{"url":"/media/a.png","thumbnail_url":"/media/a_thumb.png","content_type":"image/png","width":2,"height":1},"text":"my cat"}`),
+		},
+		{
+			name:              "no image",
+			fields:            map[string]string{"category": "music", "title": "cat"},
+			mockBehavior:      func(s *mockservice.MockPosts, post itemdata.CreatePost) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid image"}`),
+		},
+		{
+			name:              "too large",
+			fields:            map[string]string{"category": "music", "title": "cat"},
+			image:             make([]byte, imaging.MaxUploadSize+1),
+			mockBehavior:      func(s *mockservice.MockPosts, post itemdata.CreatePost) {},
+			expectStatusCode:  413,
+			expectRequestBody: []byte(`{"message":"image is too large"}`),
+		},
+		{
+			name:              "invalid fields",
+			fields:            map[string]string{"title": "cat"},
+			image:             []byte("\x89PNG"),
+			mockBehavior:      func(s *mockservice.MockPosts, post itemdata.CreatePost) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid struct fields"`),
+		},
+		{
+			name:   "unsupported image",
+			fields: map[string]string{"category": "music", "title": "cat"},
+			image:  []byte("GIF89"),
+			inputPost: itemdata.CreatePost{
+				Cat:   "music",
+				Title: "cat",
+				Type:  "image",
+				Image: []byte("GIF89"),
+			},
+			mockBehavior: func(s *mockservice.MockPosts, post itemdata.CreatePost) {
+				s.EXPECT().CreatePost(post, "1").Return(itemdata.Post{}, imaging.ErrInvalid)
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid image"}`),
+		},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			posts := mockservice.NewMockPosts(c)
+			testCase.mockBehavior(posts, testCase.inputPost)
+
+			services := &service.Service{Posts: posts}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			var buf bytes.Buffer
+			form := multipart.NewWriter(&buf)
+			for key, value := range testCase.fields {
+				if err := form.WriteField(key, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if testCase.image != nil {
+				file, err := form.CreateFormFile("image", "cat.png")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err = file.Write(testCase.image); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := form.Close(); err != nil {
+				t.Fatal(err)
+			}
+			r := httptest.NewRequest("POST", "/posts", &buf)
+			r.Header.Set("Content-Type", form.FormDataContentType())
+			w := httptest.NewRecorder()
+			ctx := session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}})
+			handler.CreatePost(w, r.WithContext(ctx))
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
 func TestServer_GetPosts(t *testing.T) {
 	type mockBehavior func(s *mockservice.MockPosts)
 	testingTable := []struct {
@@ -148,7 +276,7 @@ func TestServer_GetPosts(t *testing.T) {
 		{
 			name: "ok",
 			mockBehavior: func(s *mockservice.MockPosts) {
-				s.EXPECT().GetPosts().Return([]itemdata.Post{
+				s.EXPECT().GetPosts(itemdata.ListOptions{Limit: 100, Sort: "top"}).Return(itemdata.PostPage{Posts: []itemdata.Post{
 					{
 						ID: "1",
 						Ath: itemdata.Author{
@@ -171,7 +299,7 @@ func TestServer_GetPosts(t *testing.T) {
 							},
 						},
 					},
-				}, nil)
+				}}, nil)
 			},
 			expectStatusCode:  200,
 			expectRequestBody: []byte(`{"id":"1","author":{"id":"1","username":"123"},"comments":[],"category":"music","score":1,"type":"text","title":"123","created":"2022-11-04T17:55:14Z","upvotePercentage":100,"views":1,"votes":[{"user":"1","vote":1}],"text":"123"}`),
@@ -179,7 +307,7 @@ func TestServer_GetPosts(t *testing.T) {
 		{
 			name: "get server problems",
 			mockBehavior: func(s *mockservice.MockPosts) {
-				s.EXPECT().GetPosts().Return(nil, errors.New("invalid collection"))
+				s.EXPECT().GetPosts(itemdata.ListOptions{Limit: 100, Sort: "top"}).Return(itemdata.PostPage{}, errors.New("invalid collection"))
 			},
 			expectStatusCode:  500,
 			expectRequestBody: []byte(`"message":"invalid collection"`),
@@ -221,7 +349,7 @@ func TestServer_GetCategory(t *testing.T) {
 		{
 			name: "ok",
 			mockBehavior: func(s *mockservice.MockPosts, category string) {
-				s.EXPECT().GetCategory(category).Return([]itemdata.Post{
+				s.EXPECT().GetCategory(category, itemdata.ListOptions{Limit: 100, Sort: "top"}).Return(itemdata.PostPage{Posts: []itemdata.Post{
 					{
 						ID: "1",
 						Ath: itemdata.Author{
@@ -244,7 +372,7 @@ func TestServer_GetCategory(t *testing.T) {
 							},
 						},
 					},
-				}, nil)
+				}}, nil)
 			},
 			category:          "music",
 			expectStatusCode:  200,
@@ -260,7 +388,7 @@ func TestServer_GetCategory(t *testing.T) {
 		{
 			name: "invalid collection",
 			mockBehavior: func(s *mockservice.MockPosts, category string) {
-				s.EXPECT().GetCategory(category).Return(nil, errors.New("invalid collection"))
+				s.EXPECT().GetCategory(category, itemdata.ListOptions{Limit: 100, Sort: "top"}).Return(itemdata.PostPage{}, errors.New("invalid collection"))
 			},
 			category:          "music",
 			expectStatusCode:  500,
@@ -275,8 +403,11 @@ func TestServer_GetCategory(t *testing.T) {
 
 			posts := mockservice.NewMockPosts(c)
 			testCase.mockBehavior(posts, testCase.category)
+			communities := mockservice.NewMockCommunities(c)
+			communities.EXPECT().GetCommunity("music").Return(communitydata.Community{Name: "music"}, nil).AnyTimes()
+			communities.EXPECT().GetCommunity("humans").Return(communitydata.Community{}, errors.New("invalid community")).AnyTimes()
 
-			services := &service.Service{Posts: posts}
+			services := &service.Service{Posts: posts, Communities: communities}
 			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
 
 			r := httptest.NewRequest("GET", "/posts/", bytes.NewBufferString(""))
@@ -306,7 +437,7 @@ func TestServer_GetUser(t *testing.T) {
 		{
 			name: "ok",
 			mockBehavior: func(s *mockservice.MockPosts, login string) {
-				s.EXPECT().GetName(login).Return([]itemdata.Post{
+				s.EXPECT().GetName(login, itemdata.ListOptions{Limit: 100, Sort: "top"}).Return(itemdata.PostPage{Posts: []itemdata.Post{
 					{
 						ID: "1",
 						Ath: itemdata.Author{
@@ -329,7 +460,7 @@ func TestServer_GetUser(t *testing.T) {
 							},
 						},
 					},
-				}, nil)
+				}}, nil)
 			},
 			login:             "123",
 			expectStatusCode:  200,
@@ -338,7 +469,7 @@ func TestServer_GetUser(t *testing.T) {
 		{
 			name: "invalid user login",
 			mockBehavior: func(s *mockservice.MockPosts, login string) {
-				s.EXPECT().GetName(login).Return(nil, errors.New("invalid user login"))
+				s.EXPECT().GetName(login, itemdata.ListOptions{Limit: 100, Sort: "top"}).Return(itemdata.PostPage{}, errors.New("invalid user login"))
 			},
 			login:             "123",
 			expectStatusCode:  400,
@@ -378,13 +509,14 @@ func TestServer_GetPostID(t *testing.T) {
 		name              string
 		mockBehavior      mockBehavior
 		postID            string
+		query             string
 		expectStatusCode  int
 		expectRequestBody []byte
 	}{
 		{
 			name: "ok",
 			mockBehavior: func(s *mockservice.MockPosts, postID string) {
-				s.EXPECT().GetPostID(postID).Return(itemdata.Post{
+				s.EXPECT().GetPostID(postID, "old", "").Return(itemdata.Post{
 					ID: postID,
 					Ath: itemdata.Author{
 						ID:       "1",
@@ -414,12 +546,21 @@ func TestServer_GetPostID(t *testing.T) {
 		{
 			name: "invalid post id",
 			mockBehavior: func(s *mockservice.MockPosts, postID string) {
-				s.EXPECT().GetPostID(postID).Return(itemdata.Post{}, errors.New("invalid post id"))
+				s.EXPECT().GetPostID(postID, "top", "").Return(itemdata.Post{}, errors.New("invalid post id"))
 			},
 			postID:            "1",
+			query:             "?sort=top",
 			expectStatusCode:  400,
 			expectRequestBody: []byte(`"message":"invalid post id"`),
 		},
+		{
+			name:              "invalid sort",
+			mockBehavior:      func(s *mockservice.MockPosts, postID string) {},
+			postID:            "1",
+			query:             "?sort=random",
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid sort"`),
+		},
 	}
 
 	for _, testCase := range testingTable {
@@ -433,7 +574,7 @@ func TestServer_GetPostID(t *testing.T) {
 			services := &service.Service{Posts: posts}
 			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
 
-			r := httptest.NewRequest("GET", "/post/", bytes.NewBufferString(""))
+			r := httptest.NewRequest("GET", "/post/"+testCase.postID+testCase.query, bytes.NewBufferString(""))
 			w := httptest.NewRecorder()
 			r = mux.SetURLVars(r, map[string]string{
 				"post_id": testCase.postID,
@@ -441,6 +582,9 @@ func TestServer_GetPostID(t *testing.T) {
 			handler.GetPostID(w, r)
 			resp := w.Result()
 			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
 			if !bytes.Contains(body, testCase.expectRequestBody) {
 				t.Errorf("no text found")
 			}
@@ -508,7 +652,7 @@ func TestServer_DeletePost(t *testing.T) {
 			if testCase.name == "invalid user id" {
 				ctx = r.Context()
 			} else {
-				ctx = context.WithValue(r.Context(), "id", testCase.userID)
+				ctx = session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: testCase.userID}})
 			}
 			handler.DeletePost(w, r.WithContext(ctx))
 			resp := w.Result()
@@ -519,3 +663,315 @@ func TestServer_DeletePost(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_EditPost(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockPosts, postID, userID, text string)
+	testingTable := []struct {
+		name              string
+		inputBody         string
+		inputText         string
+		userID            string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name:      "ok",
+			inputBody: `{"text":"new text"}`,
+			inputText: "new text",
+			userID:    "1",
+			mockBehavior: func(s *mockservice.MockPosts, postID, userID, text string) {
+				s.EXPECT().EditPost(postID, userID, text).Return(itemdata.Post{
+					ID:      postID,
+					Ath:     itemdata.Author{ID: userID, Username: "123"},
+					Type:    "text",
+					Text:    text,
+					Created: "2022-11-04T17:55:14Z",
+					Edited:  "2022-11-04T18:55:14Z",
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"edited":"2022-11-04T18:55:14Z","text":"new text"}`),
+		},
+		{
+			name:      "foreign post",
+			inputBody: `{"url":"https://example.com"}`,
+			inputText: "https://example.com",
+			userID:    "2",
+			mockBehavior: func(s *mockservice.MockPosts, postID, userID, text string) {
+				s.EXPECT().EditPost(postID, userID, text).Return(itemdata.Post{}, errors.New("invalid user id"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid user id"}`),
+		},
+		{
+			name:              "empty text",
+			inputBody:         `{"text":""}`,
+			userID:            "1",
+			mockBehavior:      func(s *mockservice.MockPosts, postID, userID, text string) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"empty text"}`),
+		},
+		{
+			name:              "invalid json",
+			inputBody:         `{12}`,
+			userID:            "1",
+			mockBehavior:      func(s *mockservice.MockPosts, postID, userID, text string) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid json input"}`),
+		},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			posts := mockservice.NewMockPosts(c)
+			testCase.mockBehavior(posts, "abcd", testCase.userID, testCase.inputText)
+
+			services := &service.Service{Posts: posts}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+			r := httptest.NewRequest("PUT", "/post/abcd", bytes.NewBufferString(testCase.inputBody))
+			r = mux.SetURLVars(r, map[string]string{"post_id": "abcd"})
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: testCase.userID}}))
+			w := httptest.NewRecorder()
+			handler.EditPost(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_GetPostHistory(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockPosts)
+	testingTable := []struct {
+		name              string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "ok",
+			mockBehavior: func(s *mockservice.MockPosts) {
+				s.EXPECT().PostHistory("abcd", "1").Return([]itemdata.Revision{
+					{Body: "old", Created: "2022-11-04T17:55:14Z"},
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`[{"body":"old","created":"2022-11-04T17:55:14Z"}]`),
+		},
+		{
+			name: "foreign post",
+			mockBehavior: func(s *mockservice.MockPosts) {
+				s.EXPECT().PostHistory("abcd", "1").Return(nil, errors.New("invalid user id"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid user id"}`),
+		},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			posts := mockservice.NewMockPosts(c)
+			testCase.mockBehavior(posts)
+
+			services := &service.Service{Posts: posts}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+			r := httptest.NewRequest("GET", "/post/abcd/history", nil)
+			r = mux.SetURLVars(r, map[string]string{"post_id": "abcd"})
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			handler.GetPostHistory(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_GetPostsPaged(t *testing.T) {
+	cursor := itemdata.Cursor{Sort: "hot", Key: 1.5, Created: "2022-11-04T17:55:14Z", ID: "1", AsOf: "2022-11-05T00:00:00Z"}
+	type mockBehavior func(s *mockservice.MockPosts)
+	testingTable := []struct {
+		name              string
+		query             string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+		expectLink        string
+	}{
+		{
+			name:  "first page",
+			query: "?limit=1&sort=top&t=week",
+			mockBehavior: func(s *mockservice.MockPosts) {
+				s.EXPECT().GetPosts(itemdata.ListOptions{Limit: 1, Sort: "top", Window: 7 * 24 * time.Hour}).Return(itemdata.PostPage{
+					Posts: []itemdata.Post{{ID: "1", Type: "text", Text: "123"}},
+					Next:  cursor.Encode(),
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`,"text":"123"}],"next":"` + cursor.Encode() + `"}`),
+		},
+		{
+			name:  "last page",
+			query: "?sort=hot&t=day&after=" + cursor.Encode(),
+			mockBehavior: func(s *mockservice.MockPosts) {
+				s.EXPECT().GetPosts(itemdata.ListOptions{Limit: itemdata.DefaultPageSize, After: cursor, Sort: "hot"}).
+					Return(itemdata.PostPage{Posts: []itemdata.Post{}}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`{"posts":[]}`),
+		},
+		{
+			name:  "unpaged over the cap",
+			query: "?sort=top",
+			mockBehavior: func(s *mockservice.MockPosts) {
+				s.EXPECT().GetPosts(itemdata.ListOptions{Limit: itemdata.MaxPageSize, Sort: "top"}).Return(itemdata.PostPage{
+					Posts: []itemdata.Post{{ID: "1", Type: "text", Text: "123"}},
+					Next:  cursor.Encode(),
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`[{"id":"1"`),
+			expectLink:        `</posts/?after=` + cursor.Encode() + `&limit=100&sort=top>; rel="next"`,
+		},
+		{
+			name:              "invalid limit",
+			query:             "?limit=1000",
+			mockBehavior:      func(s *mockservice.MockPosts) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid limit"}`),
+		},
+		{
+			name:              "cursor of another sort",
+			query:             "?sort=new&after=" + cursor.Encode(),
+			mockBehavior:      func(s *mockservice.MockPosts) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid cursor"}`),
+		},
+		{
+			name:              "invalid sort",
+			query:             "?sort=random",
+			mockBehavior:      func(s *mockservice.MockPosts) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid sort"}`),
+		},
+		{
+			name:              "invalid time window",
+			query:             "?sort=controversial&t=decade",
+			mockBehavior:      func(s *mockservice.MockPosts) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid time window"}`),
+		},
+		{
+			name:              "invalid cursor",
+			query:             "?after=abc",
+			mockBehavior:      func(s *mockservice.MockPosts) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid cursor"}`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			posts := mockservice.NewMockPosts(c)
+			testCase.mockBehavior(posts)
+
+			services := &service.Service{Posts: posts}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("GET", "/posts/"+testCase.query, nil)
+			w := httptest.NewRecorder()
+			handler.GetPosts(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+			if link := resp.Header.Get("Link"); link != testCase.expectLink {
+				t.Errorf("results not match, want %v, have %v", testCase.expectLink, link)
+			}
+			if testCase.expectLink != "" && !bytes.HasPrefix(body, []byte("[")) {
+				t.Errorf("results not match, want %v, have %s", "array", body)
+			}
+		})
+	}
+}
+
+func TestServer_GetFeed(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockPosts)
+	testingTable := []struct {
+		name              string
+		userID            string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name:   "logged in",
+			userID: "1",
+			mockBehavior: func(s *mockservice.MockPosts) {
+				s.EXPECT().GetFeed(itemdata.ListOptions{Limit: 1, Sort: "new", Viewer: "1"}).Return(itemdata.PostPage{
+					Posts: []itemdata.Post{{ID: "abcd", Type: "text", Text: "123"}},
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"posts":[{"id":"abcd"`),
+		},
+		{
+			name: "anonymous",
+			mockBehavior: func(s *mockservice.MockPosts) {
+				s.EXPECT().GetFeed(itemdata.ListOptions{Limit: 1, Sort: "new"}).Return(itemdata.PostPage{}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"posts":[]`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			posts := mockservice.NewMockPosts(c)
+			testCase.mockBehavior(posts)
+
+			services := &service.Service{Posts: posts}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("GET", "/api/feed?sort=new&limit=1", nil)
+			if testCase.userID != "" {
+				r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: testCase.userID}}))
+			}
+			w := httptest.NewRecorder()
+			handler.GetFeed(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
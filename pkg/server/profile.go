@@ -0,0 +1,63 @@
+package server
+
+import (
+	"errors"
+	"github.com/gorilla/mux"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"net/http"
+	"strconv"
+)
+
+func (s *Server) GetProfile(w http.ResponseWriter, r *http.Request) {
+	profile, err := s.service.Profile(mux.Vars(r)["user_login"])
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	s.writeJSON(w, profile, 200)
+}
+
+func (s *Server) GetUserComments(w http.ResponseWriter, r *http.Request) {
+	s.activity(w, r, s.service.UserComments)
+}
+
+func (s *Server) GetOverview(w http.ResponseWriter, r *http.Request) {
+	s.activity(w, r, s.service.Overview)
+}
+
+func (s *Server) activity(w http.ResponseWriter, r *http.Request,
+	list func(login string, opts itemdata.ListOptions) (itemdata.ActivityPage, error)) {
+	opts, err := activityOptions(r)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	page, err := list(mux.Vars(r)["user_login"], opts)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	s.writeJSON(w, page, 200)
+}
+
+// activityOptions reads ?limit= and ?after=, a history is always sorted by new.
+func activityOptions(r *http.Request) (itemdata.ListOptions, error) {
+	query := r.URL.Query()
+	opts := itemdata.ListOptions{Limit: itemdata.DefaultPageSize, Sort: itemdata.SortNew}
+	if query.Has("limit") {
+		limit, err := strconv.Atoi(query.Get("limit"))
+		if err != nil || limit < 1 || limit > itemdata.MaxPageSize {
+			return opts, errors.New("invalid limit")
+		}
+		opts.Limit = limit
+	}
+	if after := query.Get("after"); after != "" {
+		cursor, err := itemdata.DecodeCursor(after)
+		if err != nil || cursor.Sort != itemdata.SortNew {
+			return opts, errors.New("invalid cursor")
+		}
+		opts.After = cursor
+	}
+	return opts, nil
+}
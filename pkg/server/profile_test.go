@@ -0,0 +1,140 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
+	mockservice "gitlab.com/vk-go/lectures-2022-2/pkg/service/mocks"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestServer_GetProfile(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockProfiles)
+	testingTable := []struct {
+		name              string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "ok",
+			mockBehavior: func(s *mockservice.MockProfiles) {
+				s.EXPECT().Profile("bob").Return(userdata.Profile{Login: "bob", AgeDays: 3, PostKarma: 5,
+					CommentKarma: 2, Karma: 7}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"account_age_days":3,"post_karma":5,"comment_karma":2,"karma":7`),
+		},
+		{
+			name: "unknown user",
+			mockBehavior: func(s *mockservice.MockProfiles) {
+				s.EXPECT().Profile("bob").Return(userdata.Profile{}, errors.New("invalid user login"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid user login"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			profiles := mockservice.NewMockProfiles(c)
+			testCase.mockBehavior(profiles)
+
+			services := &service.Service{Profiles: profiles}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("GET", "/api/user/bob/profile", nil)
+			r = mux.SetURLVars(r, map[string]string{"user_login": "bob"})
+			w := httptest.NewRecorder()
+			handler.GetProfile(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_GetOverview(t *testing.T) {
+	cursor := itemdata.Cursor{Sort: itemdata.SortNew, Created: "2022-11-04T10:00:00Z", ID: "c1", AsOf: "2022-11-05T00:00:00Z"}
+	type mockBehavior func(s *mockservice.MockProfiles)
+	testingTable := []struct {
+		name              string
+		query             string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name:  "ok",
+			query: "?limit=1&after=" + cursor.Encode(),
+			mockBehavior: func(s *mockservice.MockProfiles) {
+				s.EXPECT().Overview("bob", itemdata.ListOptions{Limit: 1, Sort: itemdata.SortNew, After: cursor}).
+					Return(itemdata.ActivityPage{Items: []itemdata.Activity{
+						{Kind: itemdata.ActivityComment, PostID: "abcd", CommentID: "c0", Title: "first"},
+					}, Next: "next"}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"kind":"comment","post_id":"abcd","comment_id":"c0"`),
+		},
+		{
+			name:  "cursor of another sort",
+			query: "?after=" + itemdata.Cursor{Sort: itemdata.SortHot, ID: "c1", AsOf: "2022-11-05T00:00:00Z"}.Encode(),
+			mockBehavior: func(s *mockservice.MockProfiles) {
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid cursor"`),
+		},
+		{
+			name:  "unknown user",
+			query: "",
+			mockBehavior: func(s *mockservice.MockProfiles) {
+				s.EXPECT().Overview("bob", itemdata.ListOptions{Limit: itemdata.DefaultPageSize, Sort: itemdata.SortNew}).
+					Return(itemdata.ActivityPage{}, errors.New("invalid user login"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid user login"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			profiles := mockservice.NewMockProfiles(c)
+			testCase.mockBehavior(profiles)
+
+			services := &service.Service{Profiles: profiles}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("GET", "/api/user/bob/overview"+testCase.query, nil)
+			r = mux.SetURLVars(r, map[string]string{"user_login": "bob"})
+			w := httptest.NewRecorder()
+			handler.GetOverview(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	reportdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"io"
+	"net/http"
+)
+
+func (s *Server) ReportPost(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	reason, ok := s.readReason(w, r)
+	if !ok {
+		return
+	}
+	postID := mux.Vars(r)["post_id"]
+	report, err := s.service.ReportPost(postID, userID, reason)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	s.writeJSON(w, report, 201)
+	s.log.Printf("Successful post report | userID %s | postID %s \n", userID, postID)
+}
+
+func (s *Server) ReportComment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	reason, ok := s.readReason(w, r)
+	if !ok {
+		return
+	}
+	postID := mux.Vars(r)["post_id"]
+	commID := mux.Vars(r)["comment_id"]
+	report, err := s.service.ReportComment(postID, commID, userID, reason)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	s.writeJSON(w, report, 201)
+	s.log.Printf("Successful comment report | userID %s | postID %s | commentID %s \n", userID, postID, commID)
+}
+
+func (s *Server) GetReportQueue(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	limit, err := limitParam(r)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	queue, err := s.service.ReportQueue(mux.Vars(r)["community"], userID, limit)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	s.writeJSON(w, queue, 200)
+}
+
+func (s *Server) ApproveReport(w http.ResponseWriter, r *http.Request) {
+	s.resolveReport(w, r, s.service.ApproveReported, "approve")
+}
+
+func (s *Server) RemoveReport(w http.ResponseWriter, r *http.Request) {
+	s.resolveReport(w, r, s.service.RemoveReported, "remove")
+}
+
+func (s *Server) resolveReport(w http.ResponseWriter, r *http.Request,
+	resolve func(community, userID, postID, commentID string) ([]reportdata.Report, error), action string) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	if err = r.Body.Close(); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	target := struct {
+		PostID    string `json:"post_id"`
+		CommentID string `json:"comment_id"`
+	}{}
+	if err = json.Unmarshal(data, &target); err != nil || target.PostID == "" {
+		utils.NewRespError(w, "invalid json input", 400, s.log)
+		return
+	}
+	community := mux.Vars(r)["community"]
+	reports, err := resolve(community, userID, target.PostID, target.CommentID)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	s.writeJSON(w, reports, 200)
+	s.log.Printf("Successful report %s | userID %s | community %s | postID %s \n", action, userID, community,
+		target.PostID)
+}
+
+func (s *Server) GetMyReports(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	limit, err := limitParam(r)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	reports, err := s.service.MyReports(userID, limit)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.writeJSON(w, reports, 200)
+}
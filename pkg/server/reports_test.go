@@ -0,0 +1,144 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	reportdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
+	mockservice "gitlab.com/vk-go/lectures-2022-2/pkg/service/mocks"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestServer_ReportComment(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockReports)
+	testingTable := []struct {
+		name              string
+		inputBody         string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name:      "ok",
+			inputBody: `{"reason":"spam"}`,
+			mockBehavior: func(s *mockservice.MockReports) {
+				s.EXPECT().ReportComment("abcd", "111", "1", "spam").Return(reportdata.Report{
+					ID: "1", PostID: "abcd", CommentID: "111", Community: "music", ReporterID: "1", Reason: "spam",
+					Status: "open", Created: "2022-11-04T17:55:14Z",
+				}, nil)
+			},
+			expectStatusCode:  201,
+			expectRequestBody: []byte(`{"id":"1","post_id":"abcd","comment_id":"111","community":"music","reason":"spam","status":"open","created":"2022-11-04T17:55:14Z"}`),
+		},
+		{
+			name:      "already reported",
+			inputBody: `{"reason":"spam"}`,
+			mockBehavior: func(s *mockservice.MockReports) {
+				s.EXPECT().ReportComment("abcd", "111", "1", "spam").Return(reportdata.Report{}, errors.New("already reported"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"already reported"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			reports := mockservice.NewMockReports(c)
+			testCase.mockBehavior(reports)
+
+			services := &service.Service{Reports: reports}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("POST", "/post/abcd/111/report", bytes.NewBufferString(testCase.inputBody))
+			r = mux.SetURLVars(r, map[string]string{"post_id": "abcd", "comment_id": "111"})
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			handler.ReportComment(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_RemoveReport(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockReports)
+	testingTable := []struct {
+		name              string
+		inputBody         string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name:      "ok",
+			inputBody: `{"post_id":"abcd"}`,
+			mockBehavior: func(s *mockservice.MockReports) {
+				s.EXPECT().RemoveReported("music", "1", "abcd", "").Return([]reportdata.Report{
+					{ID: "1", PostID: "abcd", Community: "music", Reason: "spam", Status: "removed",
+						Created: "2022-11-04T17:55:14Z", Resolved: "2022-11-05T00:00:00Z"},
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"status":"removed"`),
+		},
+		{
+			name:              "no post id",
+			inputBody:         `{"comment_id":"111"}`,
+			mockBehavior:      func(s *mockservice.MockReports) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid json input"`),
+		},
+		{
+			name:      "not a moderator",
+			inputBody: `{"post_id":"abcd"}`,
+			mockBehavior: func(s *mockservice.MockReports) {
+				s.EXPECT().RemoveReported("music", "1", "abcd", "").Return(nil, errors.New("invalid user id"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid user id"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			reports := mockservice.NewMockReports(c)
+			testCase.mockBehavior(reports)
+
+			services := &service.Service{Reports: reports}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("POST", "/community/music/reports/remove", bytes.NewBufferString(testCase.inputBody))
+			r = mux.SetURLVars(r, map[string]string{"community": "music"})
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			handler.RemoveReport(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
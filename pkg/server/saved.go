@@ -0,0 +1,66 @@
+package server
+
+import (
+	"github.com/gorilla/mux"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"net/http"
+)
+
+// Save, Unsave, Hide and Unhide serve both the post and the comment routes,
+// comment_id is empty for a post.
+
+func (s *Server) Save(w http.ResponseWriter, r *http.Request) {
+	s.changeList(w, r, s.service.SaveItem, "save")
+}
+
+func (s *Server) Unsave(w http.ResponseWriter, r *http.Request) {
+	s.changeList(w, r, s.service.UnsaveItem, "unsave")
+}
+
+func (s *Server) Hide(w http.ResponseWriter, r *http.Request) {
+	s.changeList(w, r, s.service.HideItem, "hide")
+}
+
+func (s *Server) Unhide(w http.ResponseWriter, r *http.Request) {
+	s.changeList(w, r, s.service.UnhideItem, "unhide")
+}
+
+func (s *Server) changeList(w http.ResponseWriter, r *http.Request,
+	change func(postID, commentID, userID string) error, action string) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	postID := mux.Vars(r)["post_id"]
+	commID := mux.Vars(r)["comment_id"]
+	if err := change(postID, commID, userID); err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	utils.NewRespError(w, "success", 200, s.log)
+	s.log.Printf("Successful %s | userID %s | postID %s | commentID %s \n", action, userID, postID, commID)
+}
+
+func (s *Server) GetSaved(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	limit, err := limitParam(r)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	items, err := s.service.SavedItems(mux.Vars(r)["user_login"], userID, limit)
+	if err != nil {
+		code := 400
+		if err.Error() == "invalid user id" {
+			code = 403
+		}
+		utils.NewRespError(w, err.Error(), code, s.log)
+		return
+	}
+	s.writeJSON(w, items, 200)
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
+	mockservice "gitlab.com/vk-go/lectures-2022-2/pkg/service/mocks"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestServer_Save(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockSaved)
+	testingTable := []struct {
+		name              string
+		vars              map[string]string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "post",
+			vars: map[string]string{"post_id": "abcd"},
+			mockBehavior: func(s *mockservice.MockSaved) {
+				s.EXPECT().SaveItem("abcd", "", "1").Return(nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"message":"success"`),
+		},
+		{
+			name: "comment",
+			vars: map[string]string{"post_id": "abcd", "comment_id": "c1"},
+			mockBehavior: func(s *mockservice.MockSaved) {
+				s.EXPECT().SaveItem("abcd", "c1", "1").Return(nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"message":"success"`),
+		},
+		{
+			name: "invalid comment",
+			vars: map[string]string{"post_id": "abcd", "comment_id": "c2"},
+			mockBehavior: func(s *mockservice.MockSaved) {
+				s.EXPECT().SaveItem("abcd", "c2", "1").Return(errors.New("invalid comment id"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid comment id"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			saved := mockservice.NewMockSaved(c)
+			testCase.mockBehavior(saved)
+
+			services := &service.Service{Saved: saved}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("POST", "/post/abcd/save", nil)
+			r = mux.SetURLVars(r, testCase.vars)
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			handler.Save(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_GetSaved(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockSaved)
+	testingTable := []struct {
+		name              string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "ok",
+			mockBehavior: func(s *mockservice.MockSaved) {
+				s.EXPECT().SavedItems("alice", "1", itemdata.DefaultPageSize).Return([]itemdata.SavedItem{
+					{PostID: "abcd", Title: "Go 1.18", Saved: "2022-11-04T17:55:14Z"},
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`"title":"Go 1.18"`),
+		},
+		{
+			name: "someone else's list",
+			mockBehavior: func(s *mockservice.MockSaved) {
+				s.EXPECT().SavedItems("alice", "1", itemdata.DefaultPageSize).Return(nil, errors.New("invalid user id"))
+			},
+			expectStatusCode:  403,
+			expectRequestBody: []byte(`"message":"invalid user id"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			saved := mockservice.NewMockSaved(c)
+			testCase.mockBehavior(saved)
+
+			services := &service.Service{Saved: saved}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("GET", "/user/alice/saved", nil)
+			r = mux.SetURLVars(r, map[string]string{"user_login": "alice"})
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{User: session.User{ID: "1"}}))
+			w := httptest.NewRecorder()
+			handler.GetSaved(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/asaskevich/govalidator"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func (s *Server) Search(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := itemdata.SearchQuery{
+		Text:     params.Get("q"),
+		Category: params.Get("category"),
+		Author:   params.Get("author"),
+		Type:     params.Get("type"),
+		From:     params.Get("from"),
+		To:       params.Get("to"),
+	}
+	if len(query.Terms()) == 0 {
+		utils.NewRespError(w, "invalid query", 400, s.log)
+		return
+	}
+	if query.Category != "" {
+		if _, err := s.service.GetCommunity(query.Category); err != nil {
+			utils.NewRespError(w, "invalid category", 400, s.log)
+			return
+		}
+	}
+	if query.Type != "" && !govalidator.IsIn(query.Type, "text", "link", "image") {
+		utils.NewRespError(w, "invalid type", 400, s.log)
+		return
+	}
+	for _, el := range []*string{&query.From, &query.To} {
+		if *el == "" {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, *el)
+		if err != nil {
+			utils.NewRespError(w, "invalid date", 400, s.log)
+			return
+		}
+		*el = date.UTC().Format("2006-01-02T15:04:05Z07:00")
+	}
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > itemdata.MaxPageSize {
+			utils.NewRespError(w, "invalid limit", 400, s.log)
+			return
+		}
+		query.Limit = n
+	}
+	hits, err := s.service.Search(query)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	resp, err := json.Marshal(hits)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	if _, err = w.Write(resp); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	s.log.Printf("Successful search | query %q | hits %d \n", query.Text, len(hits))
+}
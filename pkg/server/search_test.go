@@ -0,0 +1,123 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"github.com/golang/mock/gomock"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
+	mockservice "gitlab.com/vk-go/lectures-2022-2/pkg/service/mocks"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestServer_Search(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockPosts)
+	testingTable := []struct {
+		name              string
+		url               string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "ok",
+			url:  "/search?q=generics&category=programming&type=text&from=2022-11-04T20:00:00%2B03:00&limit=10",
+			mockBehavior: func(s *mockservice.MockPosts) {
+				s.EXPECT().Search(itemdata.SearchQuery{
+					Text: "generics", Category: "programming", Type: "text", From: "2022-11-04T17:00:00Z", Limit: 10,
+				}).Return([]itemdata.SearchHit{
+					{
+						PostID:    "1",
+						CommentID: "c1",
+						Title:     "Golang",
+						Category:  "programming",
+						Type:      "text",
+						Author:    itemdata.Author{ID: "1", Username: "123"},
+						Created:   "2022-11-04T17:55:14Z",
+						Snippet:   "<mark>generics</mark>",
+						Score:     1,
+					},
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`[{"post_id":"1","comment_id":"c1","title":"Golang","category":"programming","type":"text","author":{"id":"1","username":"123"},"created":"2022-11-04T17:55:14Z","snippet":"\u003cmark\u003egenerics\u003c/mark\u003e"}]`),
+		},
+		{
+			name:              "empty query",
+			url:               "/search?q=the",
+			mockBehavior:      func(s *mockservice.MockPosts) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid query"`),
+		},
+		{
+			name:              "invalid category",
+			url:               "/search?q=go&category=cats",
+			mockBehavior:      func(s *mockservice.MockPosts) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid category"`),
+		},
+		{
+			name:              "invalid type",
+			url:               "/search?q=go&type=video",
+			mockBehavior:      func(s *mockservice.MockPosts) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid type"`),
+		},
+		{
+			name:              "invalid date",
+			url:               "/search?q=go&to=yesterday",
+			mockBehavior:      func(s *mockservice.MockPosts) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid date"`),
+		},
+		{
+			name:              "invalid limit",
+			url:               "/search?q=go&limit=101",
+			mockBehavior:      func(s *mockservice.MockPosts) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`"message":"invalid limit"`),
+		},
+		{
+			name: "get server problems",
+			url:  "/search?q=go",
+			mockBehavior: func(s *mockservice.MockPosts) {
+				s.EXPECT().Search(itemdata.SearchQuery{Text: "go"}).Return(nil, errors.New("invalid collection"))
+			},
+			expectStatusCode:  500,
+			expectRequestBody: []byte(`"message":"invalid collection"`),
+		},
+	}
+
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			posts := mockservice.NewMockPosts(c)
+			testCase.mockBehavior(posts)
+			communities := mockservice.NewMockCommunities(c)
+			communities.EXPECT().GetCommunity("programming").Return(communitydata.Community{Name: "programming"}, nil).AnyTimes()
+			communities.EXPECT().GetCommunity("cats").Return(communitydata.Community{}, errors.New("invalid community")).AnyTimes()
+
+			services := &service.Service{Posts: posts, Communities: communities}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("GET", testCase.url, bytes.NewBufferString(""))
+			w := httptest.NewRecorder()
+			handler.Search(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package server
 
 import (
 	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
 	"log"
+	"net"
+	"net/http"
 )
 
 type Server struct {
@@ -16,3 +19,23 @@ func NewServer(service *service.Service, log *log.Logger) *Server {
 		log:     log,
 	}
 }
+
+func currentSession(r *http.Request) (*session.Claims, bool) {
+	return session.ClaimsFromContext(r.Context())
+}
+
+func currentUser(r *http.Request) (string, bool) {
+	claims, ok := currentSession(r)
+	if !ok {
+		return "", false
+	}
+	return claims.User.ID, true
+}
+
+func clientInfo(r *http.Request) session.Client {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return session.Client{UserAgent: r.UserAgent(), IP: ip}
+}
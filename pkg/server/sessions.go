@@ -0,0 +1,78 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"net/http"
+)
+
+func (s *Server) Logout(w http.ResponseWriter, r *http.Request) {
+	claims, ok := currentSession(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	if err := s.service.DeleteSession(claims.User.ID, claims.Id); err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	utils.NewRespError(w, "success", 200, s.log)
+	s.log.Printf("Successful logout | userID %s | sessionID %s \n", claims.User.ID, claims.Id)
+}
+
+func (s *Server) GetSessions(w http.ResponseWriter, r *http.Request) {
+	claims, ok := currentSession(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	sessions, err := s.service.ListSessions(claims.User.ID, claims.Id)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	resp, err := json.Marshal(sessions)
+	if err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	if _, err = w.Write(resp); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+}
+
+func (s *Server) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	sessionID := mux.Vars(r)["session_id"]
+	if err := s.service.DeleteSession(userID, sessionID); err != nil {
+		utils.NewRespError(w, err.Error(), 400, s.log)
+		return
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	utils.NewRespError(w, "success", 200, s.log)
+	s.log.Printf("Successful session revoking | userID %s | sessionID %s \n", userID, sessionID)
+}
+
+func (s *Server) DeleteSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(r)
+	if !ok {
+		utils.NewRespError(w, "invalid user id", 400, s.log)
+		return
+	}
+	if err := s.service.DeleteSessions(userID); err != nil {
+		utils.NewRespError(w, err.Error(), 500, s.log)
+		return
+	}
+	w.Header().Add("content-type", "application/json; charset=utf-8")
+	utils.NewRespError(w, "success", 200, s.log)
+	s.log.Printf("Successful logout from all sessions | userID %s \n", userID)
+}
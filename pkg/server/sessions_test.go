@@ -0,0 +1,156 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/golang/mock/gomock"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/service"
+	mockservice "gitlab.com/vk-go/lectures-2022-2/pkg/service/mocks"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestServer_Logout(t *testing.T) {
+	type mockBehavior func(s *mockservice.MockAuthorization)
+	testingTable := []struct {
+		name              string
+		claims            *session.Claims
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "ok",
+			claims: &session.Claims{
+				User:           session.User{ID: "1", Login: "test"},
+				StandardClaims: jwt.StandardClaims{Id: "abc"},
+			},
+			mockBehavior: func(s *mockservice.MockAuthorization) {
+				s.EXPECT().DeleteSession("1", "abc").Return(nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`{"message":"success"}`),
+		},
+		{
+			name:              "no session",
+			claims:            nil,
+			mockBehavior:      func(s *mockservice.MockAuthorization) {},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid user id"}`),
+		},
+		{
+			name: "already revoked",
+			claims: &session.Claims{
+				User:           session.User{ID: "1", Login: "test"},
+				StandardClaims: jwt.StandardClaims{Id: "abc"},
+			},
+			mockBehavior: func(s *mockservice.MockAuthorization) {
+				s.EXPECT().DeleteSession("1", "abc").Return(errors.New("invalid session id"))
+			},
+			expectStatusCode:  400,
+			expectRequestBody: []byte(`{"message":"invalid session id"}`),
+		},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockservice.NewMockAuthorization(c)
+			testCase.mockBehavior(auth)
+
+			services := &service.Service{Authorization: auth}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("POST", "/logout", nil)
+			if testCase.claims != nil {
+				r = r.WithContext(session.ContextWithClaims(r.Context(), testCase.claims))
+			}
+			w := httptest.NewRecorder()
+
+			handler.Logout(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
+
+func TestServer_GetSessions(t *testing.T) {
+	created := time.Date(2022, 11, 4, 17, 55, 14, 0, time.UTC)
+	type mockBehavior func(s *mockservice.MockAuthorization)
+	testingTable := []struct {
+		name              string
+		mockBehavior      mockBehavior
+		expectStatusCode  int
+		expectRequestBody []byte
+	}{
+		{
+			name: "ok",
+			mockBehavior: func(s *mockservice.MockAuthorization) {
+				s.EXPECT().ListSessions("1", "abc").Return([]session.Session{
+					{
+						ID:        "abc",
+						UserID:    "1",
+						Created:   created,
+						LastSeen:  created,
+						Expires:   created,
+						UserAgent: "curl",
+						IP:        "127.0.0.1",
+						Current:   true,
+					},
+				}, nil)
+			},
+			expectStatusCode:  200,
+			expectRequestBody: []byte(`[{"id":"abc","created":"2022-11-04T17:55:14Z","lastSeen":"2022-11-04T17:55:14Z","expires":"2022-11-04T17:55:14Z","userAgent":"curl","ip":"127.0.0.1","current":true}]`),
+		},
+		{
+			name: "get server problems",
+			mockBehavior: func(s *mockservice.MockAuthorization) {
+				s.EXPECT().ListSessions("1", "abc").Return(nil, errors.New("invalid connection"))
+			},
+			expectStatusCode:  500,
+			expectRequestBody: []byte(`{"message":"invalid connection"}`),
+		},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mockservice.NewMockAuthorization(c)
+			testCase.mockBehavior(auth)
+
+			services := &service.Service{Authorization: auth}
+			handler := NewServer(services, log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile))
+
+			r := httptest.NewRequest("GET", "/sessions", nil)
+			r = r.WithContext(session.ContextWithClaims(r.Context(), &session.Claims{
+				User:           session.User{ID: "1", Login: "test"},
+				StandardClaims: jwt.StandardClaims{Id: "abc"},
+			}))
+			w := httptest.NewRecorder()
+
+			handler.GetSessions(w, r)
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != testCase.expectStatusCode {
+				t.Errorf("results not match, want %v, have %v", testCase.expectStatusCode, resp.StatusCode)
+			}
+			if !bytes.Contains(body, testCase.expectRequestBody) {
+				t.Errorf("no text found")
+			}
+		})
+	}
+}
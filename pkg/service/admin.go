@@ -0,0 +1,171 @@
+package service
+
+import (
+	"errors"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/blob"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	spamdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/spamData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"log"
+)
+
+var _ Admin = (*AdminService)(nil)
+
+type AdminService struct {
+	dbUser        userdata.UserData
+	dbPosts       itemdata.ItemData
+	dbCommunities communitydata.CommunityData
+	dbSpam        spamdata.SpamData
+	dbNotes       notificationdata.NotificationData
+	sessionDB     session.SesManager
+	blobs         blob.BlobStore
+	logger        *log.Logger
+}
+
+func NewAdminService(dbUser userdata.UserData, dbPosts itemdata.ItemData, dbCommunities communitydata.CommunityData,
+	dbSpam spamdata.SpamData, dbNotes notificationdata.NotificationData, sessionDB session.SesManager,
+	blobs blob.BlobStore, logger *log.Logger) *AdminService {
+	return &AdminService{
+		dbUser:        dbUser,
+		dbPosts:       dbPosts,
+		dbCommunities: dbCommunities,
+		dbSpam:        dbSpam,
+		dbNotes:       dbNotes,
+		sessionDB:     sessionDB,
+		blobs:         blobs,
+		logger:        logger,
+	}
+}
+
+// GrantAdmins makes admins of the existing accounts with the logins. A login
+// without an account is skipped, registering it later does not make an admin.
+func GrantAdmins(dbUser userdata.UserData, logins []string) error {
+	for _, el := range logins {
+		id, err := dbUser.CheckUser(el)
+		if err != nil {
+			continue
+		}
+		if err = dbUser.SetRole(id, userdata.RoleAdmin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (admServ *AdminService) ListUsers(limit, offset int) ([]userdata.UserInfo, error) {
+	if limit <= 0 || limit > itemdata.MaxPageSize {
+		limit = itemdata.DefaultPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	users, err := admServ.dbUser.GetUsers(limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]userdata.UserInfo, 0, len(users))
+	for _, el := range users {
+		res = append(res, el.Info())
+	}
+	return res, nil
+}
+
+// SetRole revokes the sessions of the user, their tokens carry the old role.
+func (admServ *AdminService) SetRole(adminID, userID, role string) (userdata.UserInfo, error) {
+	if !userdata.ValidRole(role) {
+		return userdata.UserInfo{}, errors.New("invalid role")
+	}
+	return admServ.changeUser(adminID, userID, func() error {
+		if err := admServ.dbUser.SetRole(userID, role); err != nil {
+			return err
+		}
+		return admServ.sessionDB.DeleteAllForUser(userID)
+	})
+}
+
+// SuspendUser also revokes every session, so the account is locked out at once.
+func (admServ *AdminService) SuspendUser(adminID, userID string) (userdata.UserInfo, error) {
+	return admServ.changeUser(adminID, userID, func() error {
+		if err := admServ.dbUser.SetSuspended(userID, true); err != nil {
+			return err
+		}
+		return admServ.sessionDB.DeleteAllForUser(userID)
+	})
+}
+
+func (admServ *AdminService) UnsuspendUser(adminID, userID string) (userdata.UserInfo, error) {
+	return admServ.changeUser(adminID, userID, func() error {
+		return admServ.dbUser.SetSuspended(userID, false)
+	})
+}
+
+func (admServ *AdminService) changeUser(adminID, userID string, change func() error) (userdata.UserInfo, error) {
+	if adminID == userID {
+		return userdata.UserInfo{}, errors.New("can not change own account")
+	}
+	if _, err := admServ.dbUser.GetUser(userID); err != nil {
+		return userdata.UserInfo{}, errors.New("invalid user id")
+	}
+	if err := change(); err != nil {
+		return userdata.UserInfo{}, err
+	}
+	user, err := admServ.dbUser.GetUser(userID)
+	if err != nil {
+		return userdata.UserInfo{}, err
+	}
+	return user.Info(), nil
+}
+
+func (admServ *AdminService) RemovePost(adminID, postID string) error {
+	post, err := admServ.dbPosts.GetPostID(postID)
+	if err != nil {
+		return err
+	}
+	if err = admServ.dbPosts.DeletePost(postID); err != nil {
+		return err
+	}
+	logFailure(admServ.logger, "image removal", deleteImage(admServ.blobs, post.Image))
+	if err = logModAction(admServ.dbUser, admServ.dbCommunities, post.Cat, adminID,
+		communitydata.ActionRemovePost, post.ID, ""); err != nil {
+		return err
+	}
+	if err = trainSpam(admServ.dbSpam, postText(post), true); err != nil {
+		return err
+	}
+	logFailure(admServ.logger, "removal notification", notifyRemoval(admServ.dbNotes, post, nil, ""))
+	return nil
+}
+
+func (admServ *AdminService) RemoveComment(adminID, postID, commID string) (itemdata.Post, error) {
+	var post itemdata.Post
+	var target itemdata.Comment
+	err := retryOnConflict(func() error {
+		var err error
+		post, err = admServ.dbPosts.GetPostID(postID)
+		if err != nil {
+			return err
+		}
+		comment, ok := post.FindComment(commID)
+		if !ok || comment.Deleted {
+			return errors.New("invalid comment id")
+		}
+		target = comment
+		post.DeleteComment(commID)
+		return admServ.dbPosts.SetPost(post)
+	})
+	if err != nil {
+		return itemdata.Post{}, err
+	}
+	if err = logModAction(admServ.dbUser, admServ.dbCommunities, post.Cat, adminID,
+		communitydata.ActionRemoveComment, post.ID+"/"+commID, ""); err != nil {
+		return itemdata.Post{}, err
+	}
+	if err = trainSpam(admServ.dbSpam, target.Body, true); err != nil {
+		return itemdata.Post{}, err
+	}
+	logFailure(admServ.logger, "removal notification", notifyRemoval(admServ.dbNotes, post, &target, ""))
+	return post, nil
+}
@@ -1,30 +1,44 @@
 package service
 
 import (
-	"crypto/md5"
-	"encoding/hex"
 	"errors"
 	"github.com/dgrijalva/jwt-go"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/hasher"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/keyring"
 	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
 	"time"
 )
 
 var _ Authorization = (*AuthService)(nil)
 
+var errSuspended = errors.New("user is suspended")
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
 type AuthService struct {
 	db        userdata.UserData
 	sessionDB session.SesManager
-	key       []byte
+	hasher    hasher.PasswordHasher
+	keys      *keyring.KeyRing
 }
 
-func NewAuthService(bd userdata.UserData, sessionDB session.SesManager, key []byte) *AuthService {
-	return &AuthService{db: bd, sessionDB: sessionDB, key: key}
+func NewAuthService(bd userdata.UserData, sessionDB session.SesManager, hasher hasher.PasswordHasher,
+	keys *keyring.KeyRing) *AuthService {
+	return &AuthService{db: bd, sessionDB: sessionDB, hasher: hasher, keys: keys}
 }
 
 func (ser *AuthService) CreateUser(user userdata.User) (userdata.User, error) {
-	user.Password = ser.GetHash(user.Password)
-	_, err := ser.db.CheckUser(user.Login)
+	hash, err := ser.hasher.Hash(user.Password)
+	if err != nil {
+		return userdata.User{}, err
+	}
+	user.Password = hash
+	_, err = ser.db.CheckUser(user.Login)
 	if err == nil {
 		return userdata.User{}, err
 	}
@@ -32,38 +46,123 @@ func (ser *AuthService) CreateUser(user userdata.User) (userdata.User, error) {
 	return user, err
 }
 
-func (ser *AuthService) GetHash(password string) string {
-	hashPass := md5.New()
-	hashPass.Write([]byte(password))
-	return hex.EncodeToString(hashPass.Sum(nil))
-}
-
-func (ser *AuthService) GenerateToken(login, password string) (string, error) {
+func (ser *AuthService) GenerateToken(login, password string, client session.Client) (session.TokenPair, error) {
 	id, err := ser.db.CheckUser(login)
 	if err != nil {
-		return "", err
+		return session.TokenPair{}, err
 	}
 	userDB, err := ser.db.GetUser(id)
 	if err != nil {
-		return "", err
+		return session.TokenPair{}, err
+	}
+	ok, rehash, err := ser.hasher.Verify(password, userDB.Password)
+	if err != nil {
+		return session.TokenPair{}, err
+	}
+	if !ok {
+		return session.TokenPair{}, errors.New("invalid password")
+	}
+	if userDB.Suspended {
+		return session.TokenPair{}, errSuspended
+	}
+	if rehash {
+		ser.upgradeHash(userDB.ID, password)
+	}
+	sessionID := utils.RandomHex()
+	access, err := ser.signAccess(userDB, sessionID)
+	if err != nil {
+		return session.TokenPair{}, err
+	}
+	refresh := utils.RandomHex()
+	expires := time.Now().Add(refreshTokenTTL)
+	if err = ser.sessionDB.Create(session.NewSession(sessionID, access, userDB.ID, client, expires)); err != nil {
+		return session.TokenPair{}, err
+	}
+	if err = ser.sessionDB.CreateRefresh(session.NewRefreshToken(refresh, sessionID, userDB.ID, expires)); err != nil {
+		return session.TokenPair{}, err
+	}
+	return session.TokenPair{Access: access, Refresh: refresh}, nil
+}
+
+// RefreshToken exchanges a refresh token for a new pair. A token that was
+// already exchanged means it leaked, so the whole session is revoked.
+func (ser *AuthService) RefreshToken(refresh string) (session.TokenPair, error) {
+	old, err := ser.sessionDB.UseRefresh(refresh)
+	if errors.Is(err, session.ErrRefreshReused) {
+		_ = ser.sessionDB.Delete(old.SessionID, old.UserID)
+		return session.TokenPair{}, err
+	}
+	if err != nil {
+		return session.TokenPair{}, err
 	}
-	if userDB.Password != password {
-		return "", errors.New("invalid password")
+	userDB, err := ser.db.GetUser(old.UserID)
+	if err != nil {
+		return session.TokenPair{}, err
+	}
+	if userDB.Suspended {
+		_ = ser.sessionDB.Delete(old.SessionID, old.UserID)
+		return session.TokenPair{}, errSuspended
+	}
+	access, err := ser.signAccess(userDB, old.SessionID)
+	if err != nil {
+		return session.TokenPair{}, err
+	}
+	next := utils.RandomHex()
+	expires := time.Now().Add(refreshTokenTTL)
+	if err = ser.sessionDB.Rotate(old.SessionID, access, expires); err != nil {
+		return session.TokenPair{}, err
+	}
+	if err = ser.sessionDB.CreateRefresh(session.NewRefreshToken(next, old.SessionID, old.UserID, expires)); err != nil {
+		return session.TokenPair{}, err
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user": struct {
-			ID    string `json:"id"`
-			Login string `json:"username"`
-		}{
-			userDB.ID, userDB.Login,
+	return session.TokenPair{Access: access, Refresh: next}, nil
+}
+
+func (ser *AuthService) signAccess(user userdata.User, sessionID string) (string, error) {
+	now := time.Now()
+	return ser.keys.Sign(session.Claims{
+		User: session.User{
+			ID:    user.ID,
+			Login: user.Login,
+			Role:  userdata.RoleOf(user.Role),
+		},
+		StandardClaims: jwt.StandardClaims{
+			Id:        sessionID,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(accessTokenTTL).Unix(),
 		},
-		"iat": time.Now().Unix(),
-		"exp": time.Now().AddDate(0, 0, 7).Unix(),
 	})
-	resToken, err := token.SignedString(ser.key)
+}
+
+func (ser *AuthService) PublicKeys() keyring.JWKSet {
+	return ser.keys.PublicJWKS()
+}
+
+func (ser *AuthService) ListSessions(userID, currentID string) ([]session.Session, error) {
+	sessions, err := ser.sessionDB.ListForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+	return sessions, nil
+}
+
+func (ser *AuthService) DeleteSession(userID, sessionID string) error {
+	return ser.sessionDB.Delete(sessionID, userID)
+}
+
+func (ser *AuthService) DeleteSessions(userID string) error {
+	return ser.sessionDB.DeleteAllForUser(userID)
+}
+
+// upgradeHash replaces a hash made with outdated parameters or a legacy algorithm.
+// A failure only postpones the upgrade until the next login.
+func (ser *AuthService) upgradeHash(userID, password string) {
+	hash, err := ser.hasher.Hash(password)
 	if err != nil {
-		return "", err
+		return
 	}
-	err = ser.sessionDB.Create(resToken, userDB.ID)
-	return resToken, err
+	_ = ser.db.UpdatePassword(userID, hash)
 }
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"gitlab.com/vk-go/lectures-2022-2/pkg/automod"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"time"
+)
+
+// AutoModerator is the author of the automatic replies and the moderator of
+// the automatic actions in the log.
+const AutoModerator = "AutoModerator"
+
+// runAutomod checks a new post or comment against the rules, a nil engine lets everything through.
+func runAutomod(engine *automod.Engine, dbPosts itemdata.ItemData, user userdata.User,
+	kind, category, title, body string) (automod.Decision, error) {
+	karma, err := automodKarma(engine, dbPosts, user.ID)
+	if err != nil {
+		return automod.Decision{}, err
+	}
+	return evaluateAutomod(engine, user, karma, kind, category, title, body), nil
+}
+
+// automodKarma reads the karma the rules see. Karma is summed over all posts,
+// so the callers that retry on conflicts read it once, before the retries.
+func automodKarma(engine *automod.Engine, dbPosts itemdata.ItemData, userID string) (int, error) {
+	if engine == nil {
+		return 0, nil
+	}
+	karma, err := dbPosts.GetKarma(userID)
+	if err != nil {
+		return 0, err
+	}
+	return karma.Total(), nil
+}
+
+func evaluateAutomod(engine *automod.Engine, user userdata.User, karma int,
+	kind, category, title, body string) automod.Decision {
+	if engine == nil {
+		return automod.Decision{}
+	}
+	return engine.Evaluate(automod.Item{
+		Kind:       kind,
+		Category:   category,
+		Title:      title,
+		Body:       body,
+		AccountAge: automod.Duration{Duration: user.Age(time.Now())},
+		Karma:      karma,
+	})
+}
+
+func automodStatus(decision automod.Decision) string {
+	switch {
+	case decision.Remove:
+		return itemdata.StatusRemoved
+	case decision.Hold:
+		return itemdata.StatusHeld
+	}
+	return ""
+}
+
+func automodReplies(decision automod.Decision, parentID string) []itemdata.Comment {
+	res := make([]itemdata.Comment, 0, len(decision.Replies))
+	for _, el := range decision.Replies {
+		res = append(res, itemdata.Comment{
+			Ath:      itemdata.Author{Username: AutoModerator},
+			Body:     el,
+			Created:  time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
+			ID:       utils.RandomHex(),
+			ParentID: parentID,
+		})
+	}
+	return res
+}
+
+// logAutomod records removing or holding the content, target is like in logModAction.
+func logAutomod(dbCommunities communitydata.CommunityData, name, target, removeAction string,
+	decision automod.Decision) error {
+	action := removeAction
+	switch {
+	case decision.Remove:
+	case decision.Hold:
+		action = communitydata.ActionHold
+	default:
+		return nil
+	}
+	return dbCommunities.AddModAction(communitydata.ModAction{
+		Community: name,
+		Moderator: AutoModerator,
+		Action:    action,
+		Target:    target,
+		Reason:    decision.Reason,
+		Created:   time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
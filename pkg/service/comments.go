@@ -2,27 +2,44 @@ package service
 
 import (
 	"errors"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/automod"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
 	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	spamdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/spamData"
 	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+	"log"
 	"time"
 )
 
 var _ Comments = (*CommentService)(nil)
 
 type CommentService struct {
-	dbUser  userdata.UserData
-	dbItems itemdata.ItemData
+	dbUser        userdata.UserData
+	dbItems       itemdata.ItemData
+	dbCommunities communitydata.CommunityData
+	automod       *automod.Engine
+	dbSpam        spamdata.SpamData
+	dbNotes       notificationdata.NotificationData
+	logger        *log.Logger
 }
 
-func NewCommentService(dbUser userdata.UserData, dbItems itemdata.ItemData) *CommentService {
+func NewCommentService(dbUser userdata.UserData, dbItems itemdata.ItemData, dbCommunities communitydata.CommunityData,
+	engine *automod.Engine, dbSpam spamdata.SpamData, dbNotes notificationdata.NotificationData,
+	logger *log.Logger) *CommentService {
 	return &CommentService{
-		dbUser:  dbUser,
-		dbItems: dbItems,
+		dbUser:        dbUser,
+		dbItems:       dbItems,
+		dbCommunities: dbCommunities,
+		automod:       engine,
+		dbSpam:        dbSpam,
+		dbNotes:       dbNotes,
+		logger:        logger,
 	}
 }
 
-func (cmServ *CommentService) CreateComm(postID, userID, comment string) (itemdata.Post, error) {
+func (cmServ *CommentService) CreateComm(postID, userID, parentID, comment string) (itemdata.Post, error) {
 	user, err := cmServ.dbUser.GetUser(userID)
 	if err != nil {
 		return itemdata.Post{}, err
@@ -32,39 +49,155 @@ func (cmServ *CommentService) CreateComm(postID, userID, comment string) (itemda
 			Username: user.Login,
 			ID:       user.ID,
 		},
-		Body:    comment,
-		Created: time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
-		ID:      utils.RandomHex(),
+		Body:     comment,
+		Created:  time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
+		ID:       utils.RandomHex(),
+		ParentID: parentID,
 	}
-	post, err := cmServ.dbItems.GetPostID(postID)
+	karma, err := automodKarma(cmServ.automod, cmServ.dbItems, userID)
 	if err != nil {
 		return itemdata.Post{}, err
 	}
-	post.Comments = append(post.Comments, comm)
-	if err = cmServ.dbItems.SetPost(post); err != nil {
-		return post, err
+	var post itemdata.Post
+	var decision automod.Decision
+	err = retryOnConflict(func() error {
+		post, err = cmServ.dbItems.GetPostID(postID)
+		if err != nil {
+			return err
+		}
+		if post.Status != "" {
+			return errors.New("invalid post id")
+		}
+		if post.Locked {
+			return errors.New("post is locked")
+		}
+		if err = checkBanned(cmServ.dbCommunities, post.Cat, userID); err != nil {
+			return err
+		}
+		decision = evaluateAutomod(cmServ.automod, user, karma, automod.KindComment, post.Cat, "", comment)
+		if err = checkSpam(cmServ.dbSpam, &decision, comment); err != nil {
+			return err
+		}
+		comm.Status = automodStatus(decision)
+		if err = post.AddComment(comm); err != nil {
+			return err
+		}
+		post.Comments = append(post.Comments, automodReplies(decision, comm.ID)...)
+		return cmServ.dbItems.SetPost(post)
+	})
+	if err != nil {
+		return itemdata.Post{}, err
 	}
+	if err = logAutomod(cmServ.dbCommunities, post.Cat, post.ID+"/"+comm.ID, communitydata.ActionRemoveComment,
+		decision); err != nil {
+		return itemdata.Post{}, err
+	}
+	logFailure(cmServ.logger, "comment notification", notifyComment(cmServ.dbNotes, cmServ.dbUser, post, comm))
 	return post, nil
 }
 
-func (cmServ *CommentService) DeleteComm(postID, userID, commID string) (itemdata.Post, error) {
-	post, err := cmServ.dbItems.GetPostID(postID)
+// EditComm checks the new text like a new comment, an edit can hold or remove a published comment.
+func (cmServ *CommentService) EditComm(postID, userID, commID, comment string) (itemdata.Post, error) {
+	user, err := cmServ.dbUser.GetUser(userID)
 	if err != nil {
 		return itemdata.Post{}, err
 	}
-	comment, ok := cmServ.findComment(post, commID)
-	if !ok {
-		return itemdata.Post{}, errors.New("invalid comment id")
+	karma, err := automodKarma(cmServ.automod, cmServ.dbItems, userID)
+	if err != nil {
+		return itemdata.Post{}, err
+	}
+	var post itemdata.Post
+	var decision automod.Decision
+	err = retryOnConflict(func() error {
+		var err error
+		post, err = cmServ.dbItems.GetPostID(postID)
+		if err != nil {
+			return err
+		}
+		old, ok := post.FindComment(commID)
+		if !ok || old.Deleted {
+			return errors.New("invalid comment id")
+		}
+		if old.Ath.ID != userID {
+			return errors.New("invalid user id")
+		}
+		if err = post.EditComment(commID, comment, time.Now().UTC().Format("2006-01-02T15:04:05Z07:00")); err != nil {
+			return err
+		}
+		decision = automod.Decision{}
+		if old.Status == "" {
+			decision = evaluateAutomod(cmServ.automod, user, karma, automod.KindComment, post.Cat, "", comment)
+			if err = checkSpam(cmServ.dbSpam, &decision, comment); err != nil {
+				return err
+			}
+			if status := automodStatus(decision); status != "" {
+				if err = post.SetCommentStatus(commID, status); err != nil {
+					return err
+				}
+			}
+		}
+		return cmServ.dbItems.SetPost(post)
+	})
+	if err != nil {
+		return itemdata.Post{}, err
+	}
+	return post, logAutomod(cmServ.dbCommunities, post.Cat, post.ID+"/"+commID, communitydata.ActionRemoveComment, decision)
+}
+
+// CommentHistory is shown to the author of the comment and to the moderators of the community.
+func (cmServ *CommentService) CommentHistory(postID, userID, commID string) ([]itemdata.Revision, error) {
+	post, err := cmServ.dbItems.GetPostID(postID)
+	if err != nil {
+		return nil, err
+	}
+	comment, ok := post.FindComment(commID)
+	if !ok || comment.Deleted {
+		return nil, errors.New("invalid comment id")
 	}
 	if comment.Ath.ID != userID {
-		return itemdata.Post{}, errors.New("invalid user id")
+		if err = checkModerator(cmServ.dbUser, cmServ.dbCommunities, post.Cat, userID); err != nil {
+			return nil, err
+		}
 	}
-	post, ok = cmServ.deleteComment(comment, post)
-	if !ok {
-		return itemdata.Post{}, errors.New("invalid comment id")
+	return post.CommentRevisions(commID), nil
+}
+
+func (cmServ *CommentService) DeleteComm(postID, userID, commID string) (itemdata.Post, error) {
+	var post itemdata.Post
+	var removed bool
+	var target itemdata.Comment
+	err := retryOnConflict(func() error {
+		var err error
+		post, err = cmServ.dbItems.GetPostID(postID)
+		if err != nil {
+			return err
+		}
+		comment, ok := post.FindComment(commID)
+		if !ok || comment.Deleted {
+			return errors.New("invalid comment id")
+		}
+		removed = comment.Ath.ID != userID
+		target = comment
+		if removed {
+			if err = checkModerator(cmServ.dbUser, cmServ.dbCommunities, post.Cat, userID); err != nil {
+				return err
+			}
+		}
+		post.DeleteComment(commID)
+		return cmServ.dbItems.SetPost(post)
+	})
+	if err != nil {
+		return itemdata.Post{}, err
 	}
-	if err = cmServ.dbItems.SetPost(post); err != nil {
-		return post, err
+	if removed {
+		if err = logModAction(cmServ.dbUser, cmServ.dbCommunities, post.Cat, userID,
+			communitydata.ActionRemoveComment, post.ID+"/"+commID, ""); err != nil {
+			return itemdata.Post{}, err
+		}
+		if err = trainSpam(cmServ.dbSpam, target.Body, true); err != nil {
+			return itemdata.Post{}, err
+		}
+		logFailure(cmServ.logger, "removal notification", notifyRemoval(cmServ.dbNotes, post, &target, ""))
 	}
 	return post, nil
 }
@@ -78,91 +211,21 @@ func (cmServ *CommentService) Downvote(postID, userID string) (itemdata.Post, er
 }
 
 func (cmServ *CommentService) Unvote(postID, userID string) (itemdata.Post, error) {
-	post, err := cmServ.dbItems.GetPostID(postID)
-	if err != nil {
-		return itemdata.Post{}, err
-	}
-	old, ok := cmServ.findVote(post, userID)
-	if !ok {
-		return itemdata.Post{}, errors.New("invalid vote")
-	}
-	post = cmServ.deleteVote(post, userID)
-	post.Score -= old
-	post.UpvotePercentage = cmServ.getPercentage(post)
-	if err = cmServ.dbItems.SetPost(post); err != nil {
-		return post, err
-	}
-	return post, nil
-}
-
-func (cmServ CommentService) findComment(post itemdata.Post, commID string) (itemdata.Comment, bool) {
-	for _, el := range post.Comments {
-		if el.ID == commID {
-			return el, true
-		}
-	}
-	return itemdata.Comment{}, false
+	return cmServ.dbItems.RemoveVote(postID, userID)
 }
 
-func (cmServ CommentService) deleteComment(comment itemdata.Comment, post itemdata.Post) (itemdata.Post, bool) {
-	for i, el := range post.Comments {
-		if el.ID == comment.ID {
-			post.Comments = append(post.Comments[:i], post.Comments[i+1:]...)
-			return post, true
-		}
-	}
-	return itemdata.Post{}, false
+func (cmServ *CommentService) UpvoteComment(postID, commentID, userID string) (itemdata.Post, error) {
+	return cmServ.dbItems.ApplyCommentVote(postID, commentID, userID, 1)
 }
 
-func (cmServ CommentService) findVote(post itemdata.Post, userID string) (int, bool) {
-	for _, el := range post.Vote {
-		if el.User == userID {
-			return el.Vote, true
-		}
-	}
-	return 0, false
+func (cmServ *CommentService) DownvoteComment(postID, commentID, userID string) (itemdata.Post, error) {
+	return cmServ.dbItems.ApplyCommentVote(postID, commentID, userID, -1)
 }
 
-func (cmServ CommentService) deleteVote(post itemdata.Post, userID string) itemdata.Post {
-	for i, el := range post.Vote {
-		if el.User == userID {
-			post.Vote = append(post.Vote[:i], post.Vote[i+1:]...)
-			return post
-		}
-	}
-	return itemdata.Post{}
-}
-
-func (cmServ CommentService) getPercentage(post itemdata.Post) int {
-	pos := 0
-	var i int
-	var el itemdata.Votes
-	for i, el = range post.Vote {
-		if el.Vote == 1 {
-			pos++
-		}
-	}
-	return (pos * 100) / (i + 1)
+func (cmServ *CommentService) UnvoteComment(postID, commentID, userID string) (itemdata.Post, error) {
+	return cmServ.dbItems.RemoveCommentVote(postID, commentID, userID)
 }
 
 func (cmServ CommentService) vote(postID, userID string, diff int) (itemdata.Post, error) {
-	post, err := cmServ.dbItems.GetPostID(postID)
-	if err != nil {
-		return itemdata.Post{}, err
-	}
-	old, ok := cmServ.findVote(post, userID)
-	if ok {
-		post = cmServ.deleteVote(post, userID)
-		post.Score -= old
-	}
-	post.Vote = append(post.Vote, itemdata.Votes{
-		User: userID,
-		Vote: diff,
-	})
-	post.Score += diff
-	post.UpvotePercentage = cmServ.getPercentage(post)
-	if err = cmServ.dbItems.SetPost(post); err != nil {
-		return post, err
-	}
-	return post, nil
+	return cmServ.dbItems.ApplyVote(postID, userID, diff)
 }
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"errors"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	"time"
+	"unicode/utf8"
+)
+
+const maxDescriptionLength = 500
+
+var _ Communities = (*CommunityService)(nil)
+
+type CommunityService struct {
+	dbCommunities communitydata.CommunityData
+}
+
+func NewCommunityService(dbCommunities communitydata.CommunityData) *CommunityService {
+	return &CommunityService{
+		dbCommunities: dbCommunities,
+	}
+}
+
+func (commServ *CommunityService) CreateCommunity(name, description, userID string) (communitydata.Community, error) {
+	if !communitydata.ValidName(name) {
+		return communitydata.Community{}, errors.New("invalid community name")
+	}
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return communitydata.Community{}, errors.New("description is too long")
+	}
+	community, err := commServ.dbCommunities.CreateCommunity(communitydata.Community{
+		Name:        name,
+		Description: description,
+		OwnerID:     userID,
+		Created:     time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
+	})
+	if err != nil {
+		return communitydata.Community{}, err
+	}
+	if err = commServ.dbCommunities.AddModerator(name, userID); err != nil {
+		return communitydata.Community{}, err
+	}
+	return community, commServ.dbCommunities.Subscribe(name, userID)
+}
+
+func (commServ *CommunityService) GetCommunity(name string) (communitydata.Community, error) {
+	return commServ.dbCommunities.GetCommunity(name)
+}
+
+func (commServ *CommunityService) GetCommunities() ([]communitydata.Community, error) {
+	return commServ.dbCommunities.GetCommunities()
+}
+
+func (commServ *CommunityService) EditCommunity(name, userID, description string) (communitydata.Community, error) {
+	community, err := commServ.dbCommunities.GetCommunity(name)
+	if err != nil {
+		return community, err
+	}
+	if community.OwnerID != userID {
+		return communitydata.Community{}, errors.New("invalid user id")
+	}
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return communitydata.Community{}, errors.New("description is too long")
+	}
+	if err = commServ.dbCommunities.UpdateDescription(name, description); err != nil {
+		return communitydata.Community{}, err
+	}
+	community.Description = description
+	return community, nil
+}
+
+func (commServ *CommunityService) Subscribe(name, userID string) (communitydata.Community, error) {
+	if err := commServ.dbCommunities.Subscribe(name, userID); err != nil {
+		return communitydata.Community{}, err
+	}
+	return commServ.dbCommunities.GetCommunity(name)
+}
+
+func (commServ *CommunityService) Unsubscribe(name, userID string) (communitydata.Community, error) {
+	if err := commServ.dbCommunities.Unsubscribe(name, userID); err != nil {
+		return communitydata.Community{}, err
+	}
+	return commServ.dbCommunities.GetCommunity(name)
+}
+
+func (commServ *CommunityService) Subscriptions(userID string) ([]string, error) {
+	return commServ.dbCommunities.Subscriptions(userID)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+)
+
+var _ Follows = (*FollowService)(nil)
+
+type FollowService struct {
+	dbUser userdata.UserData
+}
+
+func NewFollowService(dbUser userdata.UserData) *FollowService {
+	return &FollowService{dbUser: dbUser}
+}
+
+func (folServ *FollowService) FollowUser(userID, login string) error {
+	followedID, err := folServ.dbUser.CheckUser(login)
+	if err != nil || followedID == userID {
+		return errors.New("invalid user login")
+	}
+	following, err := folServ.dbUser.Following(userID)
+	if err != nil {
+		return err
+	}
+	for _, el := range following {
+		if el == followedID {
+			return nil
+		}
+	}
+	if len(following) >= userdata.MaxFollowing {
+		return errors.New("too many follows")
+	}
+	return folServ.dbUser.Follow(userID, followedID)
+}
+
+func (folServ *FollowService) UnfollowUser(userID, login string) error {
+	followedID, err := folServ.dbUser.CheckUser(login)
+	if err != nil {
+		return errors.New("invalid user login")
+	}
+	return folServ.dbUser.Unfollow(userID, followedID)
+}
+
+// Following lists the logins, the accounts deleted since are left out.
+func (folServ *FollowService) Following(userID string) ([]string, error) {
+	ids, err := folServ.dbUser.Following(userID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, 0, len(ids))
+	for _, el := range ids {
+		user, err := folServ.dbUser.GetUser(el)
+		if err != nil {
+			continue
+		}
+		res = append(res, user.Login)
+	}
+	return res, nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"gitlab.com/vk-go/lectures-2022-2/pkg/blob"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/imaging"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/utils"
+)
+
+// storeImage puts the image and its thumbnail under fresh random keys.
+func storeImage(blobs blob.BlobStore, img imaging.Image) (*itemdata.Image, error) {
+	base := utils.RandomHex()
+	original := base + img.Original.Ext
+	thumb := base + "_thumb" + img.Thumbnail.Ext
+	if err := blobs.Put(original, img.Original.Data); err != nil {
+		return nil, err
+	}
+	if err := blobs.Put(thumb, img.Thumbnail.Data); err != nil {
+		_ = blobs.Delete(original)
+		return nil, err
+	}
+	return &itemdata.Image{
+		URL:          blobs.URL(original),
+		ThumbnailURL: blobs.URL(thumb),
+		ContentType:  img.Original.ContentType,
+		Width:        img.Width,
+		Height:       img.Height,
+		Keys:         []string{original, thumb},
+	}, nil
+}
+
+func deleteImage(blobs blob.BlobStore, img *itemdata.Image) error {
+	if img == nil {
+		return nil
+	}
+	for _, key := range img.Keys {
+		if err := blobs.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
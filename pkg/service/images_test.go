@@ -0,0 +1,113 @@
+package service
+
+import (
+	"bytes"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/automod"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// memBlobs is a blob store in a map.
+type memBlobs map[string][]byte
+
+func (b memBlobs) Put(key string, data []byte) error {
+	b[key] = data
+	return nil
+}
+
+func (b memBlobs) Delete(key string) error {
+	delete(b, key)
+	return nil
+}
+
+func (b memBlobs) URL(key string) string {
+	return "/media/" + key
+}
+
+func (env *testEnv) addImagePost(t *testing.T, userID string) itemdata.Post {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	post, err := env.CreatePost(itemdata.CreatePost{Cat: "music", Title: "title", Type: "image", Image: buf.Bytes()}, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range post.Image.Keys {
+		if _, ok := env.blobs[key]; !ok {
+			t.Fatalf("blob %v was not stored", key)
+		}
+	}
+	return post
+}
+
+func TestRemovePost_DeletesImage(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	mod := env.addUser(t, "mod", userdata.RoleModerator)
+	admin := env.addUser(t, "admin", userdata.RoleAdmin)
+	reporter := env.addUser(t, "reporter", userdata.RoleUser)
+
+	testingTable := []struct {
+		name   string
+		remove func(post itemdata.Post) error
+	}{
+		{name: "author", remove: func(post itemdata.Post) error {
+			return env.DeletePost(post.ID, author)
+		}},
+		{name: "moderator", remove: func(post itemdata.Post) error {
+			return env.DeletePost(post.ID, mod)
+		}},
+		{name: "admin", remove: func(post itemdata.Post) error {
+			return env.RemovePost(admin, post.ID)
+		}},
+		{name: "report", remove: func(post itemdata.Post) error {
+			if _, err := env.ReportPost(post.ID, reporter, "spam"); err != nil {
+				return err
+			}
+			_, err := env.RemoveReported("music", mod, post.ID, "")
+			return err
+		}},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			post := env.addImagePost(t, author)
+			if err := testCase.remove(post); err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range post.Image.Keys {
+				if _, ok := env.blobs[key]; ok {
+					t.Errorf("blob %v of a removed post is still stored", key)
+				}
+			}
+		})
+	}
+}
+
+func TestCreatePost_RemovedImage(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	rules, err := automod.Parse([]byte(`{"rules": [{"name": "no music", "categories": ["music"], "action": "remove"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.Posts.(*PostService).automod = automod.NewStaticEngine(rules)
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	post, err := env.CreatePost(itemdata.CreatePost{Cat: "music", Title: "title", Type: "image", Image: buf.Bytes()}, author)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.Status != itemdata.StatusRemoved {
+		t.Errorf("results not match, want %v, have %v", itemdata.StatusRemoved, post.Status)
+	}
+	if post.Image != nil || len(env.blobs) != 0 {
+		t.Errorf("results not match, want %v, have %v", 0, len(env.blobs))
+	}
+}
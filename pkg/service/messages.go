@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	messagedata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/messageData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxConversation is how many of the last messages of a conversation are shown.
+const maxConversation = 500
+
+var _ Messages = (*MessageService)(nil)
+
+type MessageService struct {
+	dbUser     userdata.UserData
+	dbMessages messagedata.MessageData
+}
+
+func NewMessageService(dbUser userdata.UserData, dbMessages messagedata.MessageData) *MessageService {
+	return &MessageService{
+		dbUser:     dbUser,
+		dbMessages: dbMessages,
+	}
+}
+
+// SendMessage refuses the message when either user blocked the other one.
+func (msgServ *MessageService) SendMessage(userID, toLogin, body string) (messagedata.Message, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > messagedata.MaxBodyLength {
+		return messagedata.Message{}, errors.New("invalid message")
+	}
+	sender, err := msgServ.dbUser.GetUser(userID)
+	if err != nil {
+		return messagedata.Message{}, err
+	}
+	toID, err := msgServ.dbUser.CheckUser(toLogin)
+	if err != nil {
+		return messagedata.Message{}, errors.New("invalid user login")
+	}
+	if toID == userID {
+		return messagedata.Message{}, errors.New("invalid recipient")
+	}
+	blocked, err := msgServ.dbMessages.IsBlocked(toID, userID)
+	if err != nil {
+		return messagedata.Message{}, err
+	}
+	if blocked {
+		return messagedata.Message{}, errors.New("blocked by the recipient")
+	}
+	if blocked, err = msgServ.dbMessages.IsBlocked(userID, toID); err != nil {
+		return messagedata.Message{}, err
+	}
+	if blocked {
+		return messagedata.Message{}, errors.New("recipient is blocked")
+	}
+	return msgServ.dbMessages.AddMessage(messagedata.Message{
+		FromID:  userID,
+		From:    sender.Login,
+		ToID:    toID,
+		To:      toLogin,
+		Body:    body,
+		Created: time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+func (msgServ *MessageService) Inbox(userID string, limit int, unreadOnly bool) ([]messagedata.Message, error) {
+	if limit <= 0 || limit > itemdata.MaxPageSize {
+		limit = itemdata.DefaultPageSize
+	}
+	return msgServ.dbMessages.GetInbox(userID, limit, unreadOnly)
+}
+
+func (msgServ *MessageService) Sent(userID string, limit int) ([]messagedata.Message, error) {
+	if limit <= 0 || limit > itemdata.MaxPageSize {
+		limit = itemdata.DefaultPageSize
+	}
+	return msgServ.dbMessages.GetSent(userID, limit)
+}
+
+// Conversation marks the messages the user received in it as read.
+func (msgServ *MessageService) Conversation(conversationID, userID string) ([]messagedata.Message, error) {
+	res, err := msgServ.dbMessages.GetConversation(conversationID, userID, maxConversation)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, errors.New("invalid conversation")
+	}
+	return res, msgServ.dbMessages.MarkConversationRead(conversationID, userID)
+}
+
+func (msgServ *MessageService) BlockUser(userID, login string) error {
+	blockedID, err := msgServ.dbUser.CheckUser(login)
+	if err != nil {
+		return errors.New("invalid user login")
+	}
+	if blockedID == userID {
+		return errors.New("invalid user login")
+	}
+	return msgServ.dbMessages.Block(userID, blockedID)
+}
+
+func (msgServ *MessageService) UnblockUser(userID, login string) error {
+	blockedID, err := msgServ.dbUser.CheckUser(login)
+	if err != nil {
+		return errors.New("invalid user login")
+	}
+	return msgServ.dbMessages.Unblock(userID, blockedID)
+}
+
+// BlockedUsers lists the logins, the accounts deleted since are left out.
+func (msgServ *MessageService) BlockedUsers(userID string) ([]string, error) {
+	ids, err := msgServ.dbMessages.GetBlocked(userID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, 0, len(ids))
+	for _, el := range ids {
+		user, err := msgServ.dbUser.GetUser(el)
+		if err != nil {
+			continue
+		}
+		res = append(res, user.Login)
+	}
+	return res, nil
+}
@@ -8,8 +8,14 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	keyring "gitlab.com/vk-go/lectures-2022-2/pkg/keyring"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
 	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	messagedata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/messageData"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	reportdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData"
 	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	session "gitlab.com/vk-go/lectures-2022-2/pkg/session"
 )
 
 // MockAuthorization is a mock of Authorization interface.
@@ -50,33 +56,91 @@ func (mr *MockAuthorizationMockRecorder) CreateUser(user interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthorization)(nil).CreateUser), user)
 }
 
+// DeleteSession mocks base method.
+func (m *MockAuthorization) DeleteSession(userID, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSession", userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSession indicates an expected call of DeleteSession.
+func (mr *MockAuthorizationMockRecorder) DeleteSession(userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockAuthorization)(nil).DeleteSession), userID, sessionID)
+}
+
+// DeleteSessions mocks base method.
+func (m *MockAuthorization) DeleteSessions(userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSessions", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSessions indicates an expected call of DeleteSessions.
+func (mr *MockAuthorizationMockRecorder) DeleteSessions(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSessions", reflect.TypeOf((*MockAuthorization)(nil).DeleteSessions), userID)
+}
+
 // GenerateToken mocks base method.
-func (m *MockAuthorization) GenerateToken(login, password string) (string, error) {
+func (m *MockAuthorization) GenerateToken(login, password string, client session.Client) (session.TokenPair, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateToken", login, password)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GenerateToken", login, password, client)
+	ret0, _ := ret[0].(session.TokenPair)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateToken indicates an expected call of GenerateToken.
-func (mr *MockAuthorizationMockRecorder) GenerateToken(login, password interface{}) *gomock.Call {
+func (mr *MockAuthorizationMockRecorder) GenerateToken(login, password, client interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockAuthorization)(nil).GenerateToken), login, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockAuthorization)(nil).GenerateToken), login, password, client)
 }
 
-// GetHash mocks base method.
-func (m *MockAuthorization) GetHash(password string) string {
+// ListSessions mocks base method.
+func (m *MockAuthorization) ListSessions(userID, currentID string) ([]session.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetHash", password)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "ListSessions", userID, currentID)
+	ret0, _ := ret[0].([]session.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockAuthorizationMockRecorder) ListSessions(userID, currentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockAuthorization)(nil).ListSessions), userID, currentID)
+}
+
+// PublicKeys mocks base method.
+func (m *MockAuthorization) PublicKeys() keyring.JWKSet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicKeys")
+	ret0, _ := ret[0].(keyring.JWKSet)
 	return ret0
 }
 
-// GetHash indicates an expected call of GetHash.
-func (mr *MockAuthorizationMockRecorder) GetHash(password interface{}) *gomock.Call {
+// PublicKeys indicates an expected call of PublicKeys.
+func (mr *MockAuthorizationMockRecorder) PublicKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicKeys", reflect.TypeOf((*MockAuthorization)(nil).PublicKeys))
+}
+
+// RefreshToken mocks base method.
+func (m *MockAuthorization) RefreshToken(refresh string) (session.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", refresh)
+	ret0, _ := ret[0].(session.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockAuthorizationMockRecorder) RefreshToken(refresh interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHash", reflect.TypeOf((*MockAuthorization)(nil).GetHash), password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockAuthorization)(nil).RefreshToken), refresh)
 }
 
 // MockPosts is a mock of Posts interface.
@@ -131,64 +195,124 @@ func (mr *MockPostsMockRecorder) DeletePost(id, userID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockPosts)(nil).DeletePost), id, userID)
 }
 
+// EditPost mocks base method.
+func (m *MockPosts) EditPost(id, userID, text string) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditPost", id, userID, text)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditPost indicates an expected call of EditPost.
+func (mr *MockPostsMockRecorder) EditPost(id, userID, text interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditPost", reflect.TypeOf((*MockPosts)(nil).EditPost), id, userID, text)
+}
+
 // GetCategory mocks base method.
-func (m *MockPosts) GetCategory(category string) ([]itemdata.Post, error) {
+func (m *MockPosts) GetCategory(category string, opts itemdata.ListOptions) (itemdata.PostPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCategory", category)
-	ret0, _ := ret[0].([]itemdata.Post)
+	ret := m.ctrl.Call(m, "GetCategory", category, opts)
+	ret0, _ := ret[0].(itemdata.PostPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCategory indicates an expected call of GetCategory.
-func (mr *MockPostsMockRecorder) GetCategory(category interface{}) *gomock.Call {
+func (mr *MockPostsMockRecorder) GetCategory(category, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockPosts)(nil).GetCategory), category, opts)
+}
+
+// GetFeed mocks base method.
+func (m *MockPosts) GetFeed(opts itemdata.ListOptions) (itemdata.PostPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", opts)
+	ret0, _ := ret[0].(itemdata.PostPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockPostsMockRecorder) GetFeed(opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockPosts)(nil).GetCategory), category)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockPosts)(nil).GetFeed), opts)
 }
 
 // GetName mocks base method.
-func (m *MockPosts) GetName(login string) ([]itemdata.Post, error) {
+func (m *MockPosts) GetName(login string, opts itemdata.ListOptions) (itemdata.PostPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetName", login)
-	ret0, _ := ret[0].([]itemdata.Post)
+	ret := m.ctrl.Call(m, "GetName", login, opts)
+	ret0, _ := ret[0].(itemdata.PostPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetName indicates an expected call of GetName.
-func (mr *MockPostsMockRecorder) GetName(login interface{}) *gomock.Call {
+func (mr *MockPostsMockRecorder) GetName(login, opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetName", reflect.TypeOf((*MockPosts)(nil).GetName), login)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetName", reflect.TypeOf((*MockPosts)(nil).GetName), login, opts)
 }
 
 // GetPostID mocks base method.
-func (m *MockPosts) GetPostID(id string) (itemdata.Post, error) {
+func (m *MockPosts) GetPostID(id, order, viewerID string) (itemdata.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPostID", id)
+	ret := m.ctrl.Call(m, "GetPostID", id, order, viewerID)
 	ret0, _ := ret[0].(itemdata.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPostID indicates an expected call of GetPostID.
-func (mr *MockPostsMockRecorder) GetPostID(id interface{}) *gomock.Call {
+func (mr *MockPostsMockRecorder) GetPostID(id, order, viewerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostID", reflect.TypeOf((*MockPosts)(nil).GetPostID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostID", reflect.TypeOf((*MockPosts)(nil).GetPostID), id, order, viewerID)
 }
 
 // GetPosts mocks base method.
-func (m *MockPosts) GetPosts() ([]itemdata.Post, error) {
+func (m *MockPosts) GetPosts(opts itemdata.ListOptions) (itemdata.PostPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPosts")
-	ret0, _ := ret[0].([]itemdata.Post)
+	ret := m.ctrl.Call(m, "GetPosts", opts)
+	ret0, _ := ret[0].(itemdata.PostPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPosts indicates an expected call of GetPosts.
-func (mr *MockPostsMockRecorder) GetPosts() *gomock.Call {
+func (mr *MockPostsMockRecorder) GetPosts(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockPosts)(nil).GetPosts), opts)
+}
+
+// PostHistory mocks base method.
+func (m *MockPosts) PostHistory(id, userID string) ([]itemdata.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostHistory", id, userID)
+	ret0, _ := ret[0].([]itemdata.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostHistory indicates an expected call of PostHistory.
+func (mr *MockPostsMockRecorder) PostHistory(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostHistory", reflect.TypeOf((*MockPosts)(nil).PostHistory), id, userID)
+}
+
+// Search mocks base method.
+func (m *MockPosts) Search(query itemdata.SearchQuery) ([]itemdata.SearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", query)
+	ret0, _ := ret[0].([]itemdata.SearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockPostsMockRecorder) Search(query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockPosts)(nil).GetPosts))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockPosts)(nil).Search), query)
 }
 
 // MockComments is a mock of Comments interface.
@@ -214,19 +338,34 @@ func (m *MockComments) EXPECT() *MockCommentsMockRecorder {
 	return m.recorder
 }
 
+// CommentHistory mocks base method.
+func (m *MockComments) CommentHistory(postID, userID, commID string) ([]itemdata.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommentHistory", postID, userID, commID)
+	ret0, _ := ret[0].([]itemdata.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommentHistory indicates an expected call of CommentHistory.
+func (mr *MockCommentsMockRecorder) CommentHistory(postID, userID, commID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommentHistory", reflect.TypeOf((*MockComments)(nil).CommentHistory), postID, userID, commID)
+}
+
 // CreateComm mocks base method.
-func (m *MockComments) CreateComm(postID, userID, comment string) (itemdata.Post, error) {
+func (m *MockComments) CreateComm(postID, userID, parentID, comment string) (itemdata.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateComm", postID, userID, comment)
+	ret := m.ctrl.Call(m, "CreateComm", postID, userID, parentID, comment)
 	ret0, _ := ret[0].(itemdata.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateComm indicates an expected call of CreateComm.
-func (mr *MockCommentsMockRecorder) CreateComm(postID, userID, comment interface{}) *gomock.Call {
+func (mr *MockCommentsMockRecorder) CreateComm(postID, userID, parentID, comment interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComm", reflect.TypeOf((*MockComments)(nil).CreateComm), postID, userID, comment)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComm", reflect.TypeOf((*MockComments)(nil).CreateComm), postID, userID, parentID, comment)
 }
 
 // DeleteComm mocks base method.
@@ -259,6 +398,36 @@ func (mr *MockCommentsMockRecorder) Downvote(postID, userID interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Downvote", reflect.TypeOf((*MockComments)(nil).Downvote), postID, userID)
 }
 
+// DownvoteComment mocks base method.
+func (m *MockComments) DownvoteComment(postID, commentID, userID string) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownvoteComment", postID, commentID, userID)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownvoteComment indicates an expected call of DownvoteComment.
+func (mr *MockCommentsMockRecorder) DownvoteComment(postID, commentID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownvoteComment", reflect.TypeOf((*MockComments)(nil).DownvoteComment), postID, commentID, userID)
+}
+
+// EditComm mocks base method.
+func (m *MockComments) EditComm(postID, userID, commID, comment string) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditComm", postID, userID, commID, comment)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditComm indicates an expected call of EditComm.
+func (mr *MockCommentsMockRecorder) EditComm(postID, userID, commID, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditComm", reflect.TypeOf((*MockComments)(nil).EditComm), postID, userID, commID, comment)
+}
+
 // Unvote mocks base method.
 func (m *MockComments) Unvote(postID, userID string) (itemdata.Post, error) {
 	m.ctrl.T.Helper()
@@ -274,6 +443,21 @@ func (mr *MockCommentsMockRecorder) Unvote(postID, userID interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unvote", reflect.TypeOf((*MockComments)(nil).Unvote), postID, userID)
 }
 
+// UnvoteComment mocks base method.
+func (m *MockComments) UnvoteComment(postID, commentID, userID string) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnvoteComment", postID, commentID, userID)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnvoteComment indicates an expected call of UnvoteComment.
+func (mr *MockCommentsMockRecorder) UnvoteComment(postID, commentID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnvoteComment", reflect.TypeOf((*MockComments)(nil).UnvoteComment), postID, commentID, userID)
+}
+
 // Upvote mocks base method.
 func (m *MockComments) Upvote(postID, userID string) (itemdata.Post, error) {
 	m.ctrl.T.Helper()
@@ -288,3 +472,1007 @@ func (mr *MockCommentsMockRecorder) Upvote(postID, userID interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upvote", reflect.TypeOf((*MockComments)(nil).Upvote), postID, userID)
 }
+
+// UpvoteComment mocks base method.
+func (m *MockComments) UpvoteComment(postID, commentID, userID string) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpvoteComment", postID, commentID, userID)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpvoteComment indicates an expected call of UpvoteComment.
+func (mr *MockCommentsMockRecorder) UpvoteComment(postID, commentID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpvoteComment", reflect.TypeOf((*MockComments)(nil).UpvoteComment), postID, commentID, userID)
+}
+
+// MockCommunities is a mock of Communities interface.
+type MockCommunities struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommunitiesMockRecorder
+}
+
+// MockCommunitiesMockRecorder is the mock recorder for MockCommunities.
+type MockCommunitiesMockRecorder struct {
+	mock *MockCommunities
+}
+
+// NewMockCommunities creates a new mock instance.
+func NewMockCommunities(ctrl *gomock.Controller) *MockCommunities {
+	mock := &MockCommunities{ctrl: ctrl}
+	mock.recorder = &MockCommunitiesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommunities) EXPECT() *MockCommunitiesMockRecorder {
+	return m.recorder
+}
+
+// CreateCommunity mocks base method.
+func (m *MockCommunities) CreateCommunity(name, description, userID string) (communitydata.Community, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCommunity", name, description, userID)
+	ret0, _ := ret[0].(communitydata.Community)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCommunity indicates an expected call of CreateCommunity.
+func (mr *MockCommunitiesMockRecorder) CreateCommunity(name, description, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCommunity", reflect.TypeOf((*MockCommunities)(nil).CreateCommunity), name, description, userID)
+}
+
+// EditCommunity mocks base method.
+func (m *MockCommunities) EditCommunity(name, userID, description string) (communitydata.Community, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditCommunity", name, userID, description)
+	ret0, _ := ret[0].(communitydata.Community)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditCommunity indicates an expected call of EditCommunity.
+func (mr *MockCommunitiesMockRecorder) EditCommunity(name, userID, description interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditCommunity", reflect.TypeOf((*MockCommunities)(nil).EditCommunity), name, userID, description)
+}
+
+// GetCommunities mocks base method.
+func (m *MockCommunities) GetCommunities() ([]communitydata.Community, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommunities")
+	ret0, _ := ret[0].([]communitydata.Community)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommunities indicates an expected call of GetCommunities.
+func (mr *MockCommunitiesMockRecorder) GetCommunities() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommunities", reflect.TypeOf((*MockCommunities)(nil).GetCommunities))
+}
+
+// GetCommunity mocks base method.
+func (m *MockCommunities) GetCommunity(name string) (communitydata.Community, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommunity", name)
+	ret0, _ := ret[0].(communitydata.Community)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommunity indicates an expected call of GetCommunity.
+func (mr *MockCommunitiesMockRecorder) GetCommunity(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommunity", reflect.TypeOf((*MockCommunities)(nil).GetCommunity), name)
+}
+
+// Subscribe mocks base method.
+func (m *MockCommunities) Subscribe(name, userID string) (communitydata.Community, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", name, userID)
+	ret0, _ := ret[0].(communitydata.Community)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockCommunitiesMockRecorder) Subscribe(name, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockCommunities)(nil).Subscribe), name, userID)
+}
+
+// Subscriptions mocks base method.
+func (m *MockCommunities) Subscriptions(userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscriptions", userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscriptions indicates an expected call of Subscriptions.
+func (mr *MockCommunitiesMockRecorder) Subscriptions(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscriptions", reflect.TypeOf((*MockCommunities)(nil).Subscriptions), userID)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockCommunities) Unsubscribe(name, userID string) (communitydata.Community, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", name, userID)
+	ret0, _ := ret[0].(communitydata.Community)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockCommunitiesMockRecorder) Unsubscribe(name, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockCommunities)(nil).Unsubscribe), name, userID)
+}
+
+// MockModeration is a mock of Moderation interface.
+type MockModeration struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerationMockRecorder
+}
+
+// MockModerationMockRecorder is the mock recorder for MockModeration.
+type MockModerationMockRecorder struct {
+	mock *MockModeration
+}
+
+// NewMockModeration creates a new mock instance.
+func NewMockModeration(ctrl *gomock.Controller) *MockModeration {
+	mock := &MockModeration{ctrl: ctrl}
+	mock.recorder = &MockModerationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModeration) EXPECT() *MockModerationMockRecorder {
+	return m.recorder
+}
+
+// AddModerator mocks base method.
+func (m *MockModeration) AddModerator(name, userID, login string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddModerator", name, userID, login)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddModerator indicates an expected call of AddModerator.
+func (mr *MockModerationMockRecorder) AddModerator(name, userID, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddModerator", reflect.TypeOf((*MockModeration)(nil).AddModerator), name, userID, login)
+}
+
+// Approve mocks base method.
+func (m *MockModeration) Approve(postID, commentID, userID string) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", postID, commentID, userID)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockModerationMockRecorder) Approve(postID, commentID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockModeration)(nil).Approve), postID, commentID, userID)
+}
+
+// BanUser mocks base method.
+func (m *MockModeration) BanUser(name, userID, login, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BanUser", name, userID, login, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BanUser indicates an expected call of BanUser.
+func (mr *MockModerationMockRecorder) BanUser(name, userID, login, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BanUser", reflect.TypeOf((*MockModeration)(nil).BanUser), name, userID, login, reason)
+}
+
+// HeldItems mocks base method.
+func (m *MockModeration) HeldItems(name, userID string) ([]itemdata.HeldItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeldItems", name, userID)
+	ret0, _ := ret[0].([]itemdata.HeldItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeldItems indicates an expected call of HeldItems.
+func (mr *MockModerationMockRecorder) HeldItems(name, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeldItems", reflect.TypeOf((*MockModeration)(nil).HeldItems), name, userID)
+}
+
+// LockPost mocks base method.
+func (m *MockModeration) LockPost(postID, userID, reason string) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockPost", postID, userID, reason)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockPost indicates an expected call of LockPost.
+func (mr *MockModerationMockRecorder) LockPost(postID, userID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockPost", reflect.TypeOf((*MockModeration)(nil).LockPost), postID, userID, reason)
+}
+
+// ModLog mocks base method.
+func (m *MockModeration) ModLog(name string, limit int) ([]communitydata.ModAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModLog", name, limit)
+	ret0, _ := ret[0].([]communitydata.ModAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModLog indicates an expected call of ModLog.
+func (mr *MockModerationMockRecorder) ModLog(name, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModLog", reflect.TypeOf((*MockModeration)(nil).ModLog), name, limit)
+}
+
+// Moderators mocks base method.
+func (m *MockModeration) Moderators(name string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Moderators", name)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Moderators indicates an expected call of Moderators.
+func (mr *MockModerationMockRecorder) Moderators(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Moderators", reflect.TypeOf((*MockModeration)(nil).Moderators), name)
+}
+
+// PinPost mocks base method.
+func (m *MockModeration) PinPost(postID, userID, reason string) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinPost", postID, userID, reason)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PinPost indicates an expected call of PinPost.
+func (mr *MockModerationMockRecorder) PinPost(postID, userID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinPost", reflect.TypeOf((*MockModeration)(nil).PinPost), postID, userID, reason)
+}
+
+// RemoveModerator mocks base method.
+func (m *MockModeration) RemoveModerator(name, userID, login string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveModerator", name, userID, login)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveModerator indicates an expected call of RemoveModerator.
+func (mr *MockModerationMockRecorder) RemoveModerator(name, userID, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveModerator", reflect.TypeOf((*MockModeration)(nil).RemoveModerator), name, userID, login)
+}
+
+// UnbanUser mocks base method.
+func (m *MockModeration) UnbanUser(name, userID, login, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnbanUser", name, userID, login, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnbanUser indicates an expected call of UnbanUser.
+func (mr *MockModerationMockRecorder) UnbanUser(name, userID, login, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnbanUser", reflect.TypeOf((*MockModeration)(nil).UnbanUser), name, userID, login, reason)
+}
+
+// UnlockPost mocks base method.
+func (m *MockModeration) UnlockPost(postID, userID, reason string) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockPost", postID, userID, reason)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlockPost indicates an expected call of UnlockPost.
+func (mr *MockModerationMockRecorder) UnlockPost(postID, userID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockPost", reflect.TypeOf((*MockModeration)(nil).UnlockPost), postID, userID, reason)
+}
+
+// UnpinPost mocks base method.
+func (m *MockModeration) UnpinPost(postID, userID, reason string) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinPost", postID, userID, reason)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnpinPost indicates an expected call of UnpinPost.
+func (mr *MockModerationMockRecorder) UnpinPost(postID, userID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinPost", reflect.TypeOf((*MockModeration)(nil).UnpinPost), postID, userID, reason)
+}
+
+// MockAdmin is a mock of Admin interface.
+type MockAdmin struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminMockRecorder
+}
+
+// MockAdminMockRecorder is the mock recorder for MockAdmin.
+type MockAdminMockRecorder struct {
+	mock *MockAdmin
+}
+
+// NewMockAdmin creates a new mock instance.
+func NewMockAdmin(ctrl *gomock.Controller) *MockAdmin {
+	mock := &MockAdmin{ctrl: ctrl}
+	mock.recorder = &MockAdminMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdmin) EXPECT() *MockAdminMockRecorder {
+	return m.recorder
+}
+
+// ListUsers mocks base method.
+func (m *MockAdmin) ListUsers(limit, offset int) ([]userdata.UserInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", limit, offset)
+	ret0, _ := ret[0].([]userdata.UserInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockAdminMockRecorder) ListUsers(limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockAdmin)(nil).ListUsers), limit, offset)
+}
+
+// RemoveComment mocks base method.
+func (m *MockAdmin) RemoveComment(adminID, postID, commID string) (itemdata.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveComment", adminID, postID, commID)
+	ret0, _ := ret[0].(itemdata.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveComment indicates an expected call of RemoveComment.
+func (mr *MockAdminMockRecorder) RemoveComment(adminID, postID, commID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveComment", reflect.TypeOf((*MockAdmin)(nil).RemoveComment), adminID, postID, commID)
+}
+
+// RemovePost mocks base method.
+func (m *MockAdmin) RemovePost(adminID, postID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemovePost", adminID, postID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemovePost indicates an expected call of RemovePost.
+func (mr *MockAdminMockRecorder) RemovePost(adminID, postID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePost", reflect.TypeOf((*MockAdmin)(nil).RemovePost), adminID, postID)
+}
+
+// SetRole mocks base method.
+func (m *MockAdmin) SetRole(adminID, userID, role string) (userdata.UserInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRole", adminID, userID, role)
+	ret0, _ := ret[0].(userdata.UserInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetRole indicates an expected call of SetRole.
+func (mr *MockAdminMockRecorder) SetRole(adminID, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRole", reflect.TypeOf((*MockAdmin)(nil).SetRole), adminID, userID, role)
+}
+
+// SuspendUser mocks base method.
+func (m *MockAdmin) SuspendUser(adminID, userID string) (userdata.UserInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuspendUser", adminID, userID)
+	ret0, _ := ret[0].(userdata.UserInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuspendUser indicates an expected call of SuspendUser.
+func (mr *MockAdminMockRecorder) SuspendUser(adminID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuspendUser", reflect.TypeOf((*MockAdmin)(nil).SuspendUser), adminID, userID)
+}
+
+// UnsuspendUser mocks base method.
+func (m *MockAdmin) UnsuspendUser(adminID, userID string) (userdata.UserInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsuspendUser", adminID, userID)
+	ret0, _ := ret[0].(userdata.UserInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnsuspendUser indicates an expected call of UnsuspendUser.
+func (mr *MockAdminMockRecorder) UnsuspendUser(adminID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsuspendUser", reflect.TypeOf((*MockAdmin)(nil).UnsuspendUser), adminID, userID)
+}
+
+// MockReports is a mock of Reports interface.
+type MockReports struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportsMockRecorder
+}
+
+// MockReportsMockRecorder is the mock recorder for MockReports.
+type MockReportsMockRecorder struct {
+	mock *MockReports
+}
+
+// NewMockReports creates a new mock instance.
+func NewMockReports(ctrl *gomock.Controller) *MockReports {
+	mock := &MockReports{ctrl: ctrl}
+	mock.recorder = &MockReportsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReports) EXPECT() *MockReportsMockRecorder {
+	return m.recorder
+}
+
+// ApproveReported mocks base method.
+func (m *MockReports) ApproveReported(community, userID, postID, commentID string) ([]reportdata.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveReported", community, userID, postID, commentID)
+	ret0, _ := ret[0].([]reportdata.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveReported indicates an expected call of ApproveReported.
+func (mr *MockReportsMockRecorder) ApproveReported(community, userID, postID, commentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveReported", reflect.TypeOf((*MockReports)(nil).ApproveReported), community, userID, postID, commentID)
+}
+
+// MyReports mocks base method.
+func (m *MockReports) MyReports(userID string, limit int) ([]reportdata.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MyReports", userID, limit)
+	ret0, _ := ret[0].([]reportdata.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MyReports indicates an expected call of MyReports.
+func (mr *MockReportsMockRecorder) MyReports(userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MyReports", reflect.TypeOf((*MockReports)(nil).MyReports), userID, limit)
+}
+
+// RemoveReported mocks base method.
+func (m *MockReports) RemoveReported(community, userID, postID, commentID string) ([]reportdata.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReported", community, userID, postID, commentID)
+	ret0, _ := ret[0].([]reportdata.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveReported indicates an expected call of RemoveReported.
+func (mr *MockReportsMockRecorder) RemoveReported(community, userID, postID, commentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReported", reflect.TypeOf((*MockReports)(nil).RemoveReported), community, userID, postID, commentID)
+}
+
+// ReportComment mocks base method.
+func (m *MockReports) ReportComment(postID, commentID, userID, reason string) (reportdata.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportComment", postID, commentID, userID, reason)
+	ret0, _ := ret[0].(reportdata.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportComment indicates an expected call of ReportComment.
+func (mr *MockReportsMockRecorder) ReportComment(postID, commentID, userID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportComment", reflect.TypeOf((*MockReports)(nil).ReportComment), postID, commentID, userID, reason)
+}
+
+// ReportPost mocks base method.
+func (m *MockReports) ReportPost(postID, userID, reason string) (reportdata.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportPost", postID, userID, reason)
+	ret0, _ := ret[0].(reportdata.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportPost indicates an expected call of ReportPost.
+func (mr *MockReportsMockRecorder) ReportPost(postID, userID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportPost", reflect.TypeOf((*MockReports)(nil).ReportPost), postID, userID, reason)
+}
+
+// ReportQueue mocks base method.
+func (m *MockReports) ReportQueue(community, userID string, limit int) ([]reportdata.QueueItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportQueue", community, userID, limit)
+	ret0, _ := ret[0].([]reportdata.QueueItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportQueue indicates an expected call of ReportQueue.
+func (mr *MockReportsMockRecorder) ReportQueue(community, userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportQueue", reflect.TypeOf((*MockReports)(nil).ReportQueue), community, userID, limit)
+}
+
+// MockSaved is a mock of Saved interface.
+type MockSaved struct {
+	ctrl     *gomock.Controller
+	recorder *MockSavedMockRecorder
+}
+
+// MockSavedMockRecorder is the mock recorder for MockSaved.
+type MockSavedMockRecorder struct {
+	mock *MockSaved
+}
+
+// NewMockSaved creates a new mock instance.
+func NewMockSaved(ctrl *gomock.Controller) *MockSaved {
+	mock := &MockSaved{ctrl: ctrl}
+	mock.recorder = &MockSavedMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSaved) EXPECT() *MockSavedMockRecorder {
+	return m.recorder
+}
+
+// HideItem mocks base method.
+func (m *MockSaved) HideItem(postID, commentID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HideItem", postID, commentID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HideItem indicates an expected call of HideItem.
+func (mr *MockSavedMockRecorder) HideItem(postID, commentID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HideItem", reflect.TypeOf((*MockSaved)(nil).HideItem), postID, commentID, userID)
+}
+
+// SaveItem mocks base method.
+func (m *MockSaved) SaveItem(postID, commentID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveItem", postID, commentID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveItem indicates an expected call of SaveItem.
+func (mr *MockSavedMockRecorder) SaveItem(postID, commentID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveItem", reflect.TypeOf((*MockSaved)(nil).SaveItem), postID, commentID, userID)
+}
+
+// SavedItems mocks base method.
+func (m *MockSaved) SavedItems(login, userID string, limit int) ([]itemdata.SavedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavedItems", login, userID, limit)
+	ret0, _ := ret[0].([]itemdata.SavedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SavedItems indicates an expected call of SavedItems.
+func (mr *MockSavedMockRecorder) SavedItems(login, userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavedItems", reflect.TypeOf((*MockSaved)(nil).SavedItems), login, userID, limit)
+}
+
+// UnhideItem mocks base method.
+func (m *MockSaved) UnhideItem(postID, commentID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnhideItem", postID, commentID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnhideItem indicates an expected call of UnhideItem.
+func (mr *MockSavedMockRecorder) UnhideItem(postID, commentID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnhideItem", reflect.TypeOf((*MockSaved)(nil).UnhideItem), postID, commentID, userID)
+}
+
+// UnsaveItem mocks base method.
+func (m *MockSaved) UnsaveItem(postID, commentID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsaveItem", postID, commentID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsaveItem indicates an expected call of UnsaveItem.
+func (mr *MockSavedMockRecorder) UnsaveItem(postID, commentID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsaveItem", reflect.TypeOf((*MockSaved)(nil).UnsaveItem), postID, commentID, userID)
+}
+
+// MockProfiles is a mock of Profiles interface.
+type MockProfiles struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfilesMockRecorder
+}
+
+// MockProfilesMockRecorder is the mock recorder for MockProfiles.
+type MockProfilesMockRecorder struct {
+	mock *MockProfiles
+}
+
+// NewMockProfiles creates a new mock instance.
+func NewMockProfiles(ctrl *gomock.Controller) *MockProfiles {
+	mock := &MockProfiles{ctrl: ctrl}
+	mock.recorder = &MockProfilesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfiles) EXPECT() *MockProfilesMockRecorder {
+	return m.recorder
+}
+
+// Overview mocks base method.
+func (m *MockProfiles) Overview(login string, opts itemdata.ListOptions) (itemdata.ActivityPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Overview", login, opts)
+	ret0, _ := ret[0].(itemdata.ActivityPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Overview indicates an expected call of Overview.
+func (mr *MockProfilesMockRecorder) Overview(login, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Overview", reflect.TypeOf((*MockProfiles)(nil).Overview), login, opts)
+}
+
+// Profile mocks base method.
+func (m *MockProfiles) Profile(login string) (userdata.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Profile", login)
+	ret0, _ := ret[0].(userdata.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Profile indicates an expected call of Profile.
+func (mr *MockProfilesMockRecorder) Profile(login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Profile", reflect.TypeOf((*MockProfiles)(nil).Profile), login)
+}
+
+// UserComments mocks base method.
+func (m *MockProfiles) UserComments(login string, opts itemdata.ListOptions) (itemdata.ActivityPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserComments", login, opts)
+	ret0, _ := ret[0].(itemdata.ActivityPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserComments indicates an expected call of UserComments.
+func (mr *MockProfilesMockRecorder) UserComments(login, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserComments", reflect.TypeOf((*MockProfiles)(nil).UserComments), login, opts)
+}
+
+// MockNotifications is a mock of Notifications interface.
+type MockNotifications struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationsMockRecorder
+}
+
+// MockNotificationsMockRecorder is the mock recorder for MockNotifications.
+type MockNotificationsMockRecorder struct {
+	mock *MockNotifications
+}
+
+// NewMockNotifications creates a new mock instance.
+func NewMockNotifications(ctrl *gomock.Controller) *MockNotifications {
+	mock := &MockNotifications{ctrl: ctrl}
+	mock.recorder = &MockNotificationsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifications) EXPECT() *MockNotificationsMockRecorder {
+	return m.recorder
+}
+
+// ListNotifications mocks base method.
+func (m *MockNotifications) ListNotifications(userID string, limit int, unreadOnly bool) ([]notificationdata.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", userID, limit, unreadOnly)
+	ret0, _ := ret[0].([]notificationdata.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockNotificationsMockRecorder) ListNotifications(userID, limit, unreadOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockNotifications)(nil).ListNotifications), userID, limit, unreadOnly)
+}
+
+// MarkAllRead mocks base method.
+func (m *MockNotifications) MarkAllRead(userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllRead", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAllRead indicates an expected call of MarkAllRead.
+func (mr *MockNotificationsMockRecorder) MarkAllRead(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllRead", reflect.TypeOf((*MockNotifications)(nil).MarkAllRead), userID)
+}
+
+// MarkRead mocks base method.
+func (m *MockNotifications) MarkRead(userID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockNotificationsMockRecorder) MarkRead(userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockNotifications)(nil).MarkRead), userID, id)
+}
+
+// UnreadCount mocks base method.
+func (m *MockNotifications) UnreadCount(userID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnreadCount", userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnreadCount indicates an expected call of UnreadCount.
+func (mr *MockNotificationsMockRecorder) UnreadCount(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnreadCount", reflect.TypeOf((*MockNotifications)(nil).UnreadCount), userID)
+}
+
+// MockMessages is a mock of Messages interface.
+type MockMessages struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessagesMockRecorder
+}
+
+// MockMessagesMockRecorder is the mock recorder for MockMessages.
+type MockMessagesMockRecorder struct {
+	mock *MockMessages
+}
+
+// NewMockMessages creates a new mock instance.
+func NewMockMessages(ctrl *gomock.Controller) *MockMessages {
+	mock := &MockMessages{ctrl: ctrl}
+	mock.recorder = &MockMessagesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessages) EXPECT() *MockMessagesMockRecorder {
+	return m.recorder
+}
+
+// BlockUser mocks base method.
+func (m *MockMessages) BlockUser(userID, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUser", userID, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUser indicates an expected call of BlockUser.
+func (mr *MockMessagesMockRecorder) BlockUser(userID, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUser", reflect.TypeOf((*MockMessages)(nil).BlockUser), userID, login)
+}
+
+// BlockedUsers mocks base method.
+func (m *MockMessages) BlockedUsers(userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockedUsers", userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockedUsers indicates an expected call of BlockedUsers.
+func (mr *MockMessagesMockRecorder) BlockedUsers(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockedUsers", reflect.TypeOf((*MockMessages)(nil).BlockedUsers), userID)
+}
+
+// Conversation mocks base method.
+func (m *MockMessages) Conversation(conversationID, userID string) ([]messagedata.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Conversation", conversationID, userID)
+	ret0, _ := ret[0].([]messagedata.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Conversation indicates an expected call of Conversation.
+func (mr *MockMessagesMockRecorder) Conversation(conversationID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Conversation", reflect.TypeOf((*MockMessages)(nil).Conversation), conversationID, userID)
+}
+
+// Inbox mocks base method.
+func (m *MockMessages) Inbox(userID string, limit int, unreadOnly bool) ([]messagedata.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Inbox", userID, limit, unreadOnly)
+	ret0, _ := ret[0].([]messagedata.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Inbox indicates an expected call of Inbox.
+func (mr *MockMessagesMockRecorder) Inbox(userID, limit, unreadOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Inbox", reflect.TypeOf((*MockMessages)(nil).Inbox), userID, limit, unreadOnly)
+}
+
+// SendMessage mocks base method.
+func (m *MockMessages) SendMessage(userID, toLogin, body string) (messagedata.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessage", userID, toLogin, body)
+	ret0, _ := ret[0].(messagedata.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendMessage indicates an expected call of SendMessage.
+func (mr *MockMessagesMockRecorder) SendMessage(userID, toLogin, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockMessages)(nil).SendMessage), userID, toLogin, body)
+}
+
+// Sent mocks base method.
+func (m *MockMessages) Sent(userID string, limit int) ([]messagedata.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sent", userID, limit)
+	ret0, _ := ret[0].([]messagedata.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sent indicates an expected call of Sent.
+func (mr *MockMessagesMockRecorder) Sent(userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sent", reflect.TypeOf((*MockMessages)(nil).Sent), userID, limit)
+}
+
+// UnblockUser mocks base method.
+func (m *MockMessages) UnblockUser(userID, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnblockUser", userID, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnblockUser indicates an expected call of UnblockUser.
+func (mr *MockMessagesMockRecorder) UnblockUser(userID, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockUser", reflect.TypeOf((*MockMessages)(nil).UnblockUser), userID, login)
+}
+
+// MockFollows is a mock of Follows interface.
+type MockFollows struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowsMockRecorder
+}
+
+// MockFollowsMockRecorder is the mock recorder for MockFollows.
+type MockFollowsMockRecorder struct {
+	mock *MockFollows
+}
+
+// NewMockFollows creates a new mock instance.
+func NewMockFollows(ctrl *gomock.Controller) *MockFollows {
+	mock := &MockFollows{ctrl: ctrl}
+	mock.recorder = &MockFollowsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollows) EXPECT() *MockFollowsMockRecorder {
+	return m.recorder
+}
+
+// FollowUser mocks base method.
+func (m *MockFollows) FollowUser(userID, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowUser", userID, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FollowUser indicates an expected call of FollowUser.
+func (mr *MockFollowsMockRecorder) FollowUser(userID, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowUser", reflect.TypeOf((*MockFollows)(nil).FollowUser), userID, login)
+}
+
+// Following mocks base method.
+func (m *MockFollows) Following(userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Following", userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Following indicates an expected call of Following.
+func (mr *MockFollowsMockRecorder) Following(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Following", reflect.TypeOf((*MockFollows)(nil).Following), userID)
+}
+
+// UnfollowUser mocks base method.
+func (m *MockFollows) UnfollowUser(userID, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfollowUser", userID, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfollowUser indicates an expected call of UnfollowUser.
+func (mr *MockFollowsMockRecorder) UnfollowUser(userID, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfollowUser", reflect.TypeOf((*MockFollows)(nil).UnfollowUser), userID, login)
+}
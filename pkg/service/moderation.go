@@ -0,0 +1,359 @@
+package service
+
+import (
+	"errors"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	spamdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/spamData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"log"
+	"time"
+)
+
+var _ Moderation = (*ModerationService)(nil)
+
+type ModerationService struct {
+	dbUser        userdata.UserData
+	dbPosts       itemdata.ItemData
+	dbCommunities communitydata.CommunityData
+	dbSpam        spamdata.SpamData
+	dbNotes       notificationdata.NotificationData
+	logger        *log.Logger
+}
+
+func NewModerationService(dbUser userdata.UserData, dbPosts itemdata.ItemData,
+	dbCommunities communitydata.CommunityData, dbSpam spamdata.SpamData, dbNotes notificationdata.NotificationData,
+	logger *log.Logger) *ModerationService {
+	return &ModerationService{
+		dbUser:        dbUser,
+		dbPosts:       dbPosts,
+		dbCommunities: dbCommunities,
+		dbSpam:        dbSpam,
+		dbNotes:       dbNotes,
+		logger:        logger,
+	}
+}
+
+func (modServ *ModerationService) AddModerator(name, userID, login string) ([]string, error) {
+	return modServ.changeModerators(name, userID, login, communitydata.ActionAddModerator)
+}
+
+func (modServ *ModerationService) RemoveModerator(name, userID, login string) ([]string, error) {
+	return modServ.changeModerators(name, userID, login, communitydata.ActionRemoveModerator)
+}
+
+// changeModerators is allowed only to the owner, who can not be removed, and
+// to the site admins, the default communities have no owner.
+func (modServ *ModerationService) changeModerators(name, userID, login, action string) ([]string, error) {
+	community, err := modServ.dbCommunities.GetCommunity(name)
+	if err != nil {
+		return nil, err
+	}
+	if community.OwnerID != userID {
+		user, err := modServ.dbUser.GetUser(userID)
+		if err != nil {
+			return nil, err
+		}
+		if !userdata.HasRole(user.Role, userdata.RoleAdmin) {
+			return nil, errors.New("invalid user id")
+		}
+	}
+	targetID, err := modServ.dbUser.CheckUser(login)
+	if err != nil {
+		return nil, errors.New("invalid user login")
+	}
+	if action == communitydata.ActionAddModerator {
+		err = modServ.dbCommunities.AddModerator(name, targetID)
+	} else if targetID == community.OwnerID {
+		err = errors.New("owner can not be removed")
+	} else {
+		err = modServ.dbCommunities.RemoveModerator(name, targetID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = logModAction(modServ.dbUser, modServ.dbCommunities, name, userID, action, login, ""); err != nil {
+		return nil, err
+	}
+	return modServ.Moderators(name)
+}
+
+// Moderators returns the logins of the moderators of the community.
+func (modServ *ModerationService) Moderators(name string) ([]string, error) {
+	ids, err := modServ.dbCommunities.Moderators(name)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, 0, len(ids))
+	for _, id := range ids {
+		user, err := modServ.dbUser.GetUser(id)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, user.Login)
+	}
+	return res, nil
+}
+
+func (modServ *ModerationService) LockPost(postID, userID, reason string) (itemdata.Post, error) {
+	return modServ.changePost(postID, userID, reason, communitydata.ActionLock, func(post *itemdata.Post) error {
+		post.Locked = true
+		return nil
+	})
+}
+
+func (modServ *ModerationService) UnlockPost(postID, userID, reason string) (itemdata.Post, error) {
+	return modServ.changePost(postID, userID, reason, communitydata.ActionUnlock, func(post *itemdata.Post) error {
+		post.Locked = false
+		return nil
+	})
+}
+
+// PinPost takes a pin slot of the community before it marks the post. The
+// slots are taken atomically by the community store, the posts are written one
+// by one and could not keep two moderators from going over MaxPinned.
+func (modServ *ModerationService) PinPost(postID, userID, reason string) (itemdata.Post, error) {
+	post, err := modServ.dbPosts.GetPostID(postID)
+	if err != nil {
+		return itemdata.Post{}, err
+	}
+	if err = checkModerator(modServ.dbUser, modServ.dbCommunities, post.Cat, userID); err != nil {
+		return itemdata.Post{}, err
+	}
+	name := post.Cat
+	if err = modServ.addPin(name, postID); err != nil {
+		return itemdata.Post{}, err
+	}
+	post, err = modServ.changePost(postID, userID, reason, communitydata.ActionPin, func(post *itemdata.Post) error {
+		post.Pinned = true
+		return nil
+	})
+	if err != nil && !post.Pinned {
+		logFailure(modServ.logger, "pin release", modServ.dbCommunities.RemovePin(name, postID))
+	}
+	return post, err
+}
+
+// UnpinPost frees the pin slot even when the post is not marked, a slot left
+// by a failed pin is freed this way.
+func (modServ *ModerationService) UnpinPost(postID, userID, reason string) (itemdata.Post, error) {
+	post, err := modServ.changePost(postID, userID, reason, communitydata.ActionUnpin, func(post *itemdata.Post) error {
+		post.Pinned = false
+		return nil
+	})
+	if err != nil {
+		return itemdata.Post{}, err
+	}
+	return post, modServ.dbCommunities.RemovePin(post.Cat, post.ID)
+}
+
+// addPin frees the slots of deleted posts when the community has none left.
+func (modServ *ModerationService) addPin(name, postID string) error {
+	err := modServ.dbCommunities.AddPin(name, postID, itemdata.MaxPinned)
+	if !errors.Is(err, communitydata.ErrTooManyPinned) {
+		return err
+	}
+	pins, err := modServ.dbCommunities.Pins(name)
+	if err != nil {
+		return err
+	}
+	freed := false
+	for _, el := range pins {
+		_, err = modServ.dbPosts.GetPostID(el)
+		if !errors.Is(err, itemdata.ErrPostNotFound) {
+			continue
+		}
+		if err = modServ.dbCommunities.RemovePin(name, el); err != nil {
+			return err
+		}
+		freed = true
+	}
+	if !freed {
+		return communitydata.ErrTooManyPinned
+	}
+	return modServ.dbCommunities.AddPin(name, postID, itemdata.MaxPinned)
+}
+
+func (modServ *ModerationService) changePost(postID, userID, reason, action string,
+	change func(post *itemdata.Post) error) (itemdata.Post, error) {
+	var post itemdata.Post
+	err := retryOnConflict(func() error {
+		var err error
+		post, err = modServ.dbPosts.GetPostID(postID)
+		if err != nil {
+			return err
+		}
+		if err = checkModerator(modServ.dbUser, modServ.dbCommunities, post.Cat, userID); err != nil {
+			return err
+		}
+		if err = change(&post); err != nil {
+			return err
+		}
+		return modServ.dbPosts.SetPost(post)
+	})
+	if err != nil {
+		return itemdata.Post{}, err
+	}
+	return post, logModAction(modServ.dbUser, modServ.dbCommunities, post.Cat, userID, action, post.ID, reason)
+}
+
+// HeldItems lists what the automoderator held in the community, oldest first.
+func (modServ *ModerationService) HeldItems(name, userID string) ([]itemdata.HeldItem, error) {
+	if err := checkModerator(modServ.dbUser, modServ.dbCommunities, name, userID); err != nil {
+		return nil, err
+	}
+	posts, err := modServ.dbPosts.GetHeld(name)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]itemdata.HeldItem, 0, len(posts))
+	for _, el := range posts {
+		res = append(res, el.HeldItems()...)
+	}
+	return res, nil
+}
+
+// Approve makes a held or automatically removed post, or with commentID a comment, visible.
+// The replies and mentions that were not sent while it was held are sent now.
+func (modServ *ModerationService) Approve(postID, commentID, userID string) (itemdata.Post, error) {
+	var post itemdata.Post
+	err := retryOnConflict(func() error {
+		var err error
+		post, err = modServ.dbPosts.GetPostID(postID)
+		if err != nil {
+			return err
+		}
+		if err = checkModerator(modServ.dbUser, modServ.dbCommunities, post.Cat, userID); err != nil {
+			return err
+		}
+		if commentID == "" {
+			if post.Status == "" {
+				return errors.New("nothing to approve")
+			}
+			post.Status = ""
+			return modServ.dbPosts.SetPost(post)
+		}
+		comment, ok := post.FindComment(commentID)
+		if !ok || comment.Deleted {
+			return errors.New("invalid comment id")
+		}
+		if comment.Status == "" {
+			return errors.New("nothing to approve")
+		}
+		if err = post.SetCommentStatus(commentID, ""); err != nil {
+			return err
+		}
+		return modServ.dbPosts.SetPost(post)
+	})
+	if err != nil {
+		return itemdata.Post{}, err
+	}
+	target := postID
+	if commentID != "" {
+		target += "/" + commentID
+	}
+	if err = logModAction(modServ.dbUser, modServ.dbCommunities, post.Cat, userID, communitydata.ActionApprove,
+		target, ""); err != nil {
+		return itemdata.Post{}, err
+	}
+	if err = trainSpam(modServ.dbSpam, itemText(post, commentID), false); err != nil {
+		return itemdata.Post{}, err
+	}
+	if commentID == "" {
+		logFailure(modServ.logger, "post notification", notifyPost(modServ.dbNotes, modServ.dbUser, post))
+	} else if comment, ok := post.FindComment(commentID); ok {
+		logFailure(modServ.logger, "comment notification", notifyComment(modServ.dbNotes, modServ.dbUser, post, comment))
+	}
+	return post, nil
+}
+
+func (modServ *ModerationService) BanUser(name, userID, login, reason string) error {
+	if err := checkModerator(modServ.dbUser, modServ.dbCommunities, name, userID); err != nil {
+		return err
+	}
+	targetID, err := modServ.dbUser.CheckUser(login)
+	if err != nil {
+		return errors.New("invalid user login")
+	}
+	ok, err := modServ.dbCommunities.IsModerator(name, targetID)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return errors.New("moderator can not be banned")
+	}
+	if err = modServ.dbCommunities.Ban(name, targetID); err != nil {
+		return err
+	}
+	return logModAction(modServ.dbUser, modServ.dbCommunities, name, userID, communitydata.ActionBan, login, reason)
+}
+
+func (modServ *ModerationService) UnbanUser(name, userID, login, reason string) error {
+	if err := checkModerator(modServ.dbUser, modServ.dbCommunities, name, userID); err != nil {
+		return err
+	}
+	targetID, err := modServ.dbUser.CheckUser(login)
+	if err != nil {
+		return errors.New("invalid user login")
+	}
+	if err = modServ.dbCommunities.Unban(name, targetID); err != nil {
+		return err
+	}
+	return logModAction(modServ.dbUser, modServ.dbCommunities, name, userID, communitydata.ActionUnban, login, reason)
+}
+
+func (modServ *ModerationService) ModLog(name string, limit int) ([]communitydata.ModAction, error) {
+	if limit <= 0 || limit > itemdata.MaxPageSize {
+		limit = itemdata.DefaultPageSize
+	}
+	return modServ.dbCommunities.ModLog(name, limit)
+}
+
+// checkModerator lets through the moderators of the community and the site
+// moderators and admins, who moderate every community.
+func checkModerator(dbUser userdata.UserData, dbCommunities communitydata.CommunityData, name, userID string) error {
+	ok, err := dbCommunities.IsModerator(name, userID)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return nil
+	}
+	user, err := dbUser.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	if !userdata.HasRole(user.Role, userdata.RoleModerator) {
+		return errors.New("invalid user id")
+	}
+	_, err = dbCommunities.GetCommunity(name)
+	return err
+}
+
+func checkBanned(dbCommunities communitydata.CommunityData, name, userID string) error {
+	banned, err := dbCommunities.IsBanned(name, userID)
+	if err != nil {
+		return err
+	}
+	if banned {
+		return errors.New("user is banned")
+	}
+	return nil
+}
+
+func logModAction(dbUser userdata.UserData, dbCommunities communitydata.CommunityData,
+	name, userID, action, target, reason string) error {
+	moderator, err := dbUser.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	return dbCommunities.AddModAction(communitydata.ModAction{
+		Community: name,
+		Moderator: moderator.Login,
+		Action:    action,
+		Target:    target,
+		Reason:    reason,
+		Created:   time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"sync"
+	"testing"
+)
+
+func (env *testEnv) addPost(t *testing.T, userID, category, text string) itemdata.Post {
+	t.Helper()
+	post, err := env.CreatePost(itemdata.CreatePost{Cat: category, Title: "title", Type: "text", Text: text}, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return post
+}
+
+func TestCheckModerator_SiteRoles(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	post := env.addPost(t, author, "music", "hello")
+
+	testingTable := []struct {
+		name string
+		role string
+		ok   bool
+	}{
+		{name: "user", role: userdata.RoleUser, ok: false},
+		{name: "moderator", role: userdata.RoleModerator, ok: true},
+		{name: "admin", role: userdata.RoleAdmin, ok: true},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			id := env.addUser(t, testCase.name, testCase.role)
+			_, err := env.LockPost(post.ID, id, "")
+			if (err == nil) != testCase.ok {
+				t.Errorf("results not match, want %v, have %v", testCase.ok, err)
+			}
+			if _, err = env.HeldItems("music", id); (err == nil) != testCase.ok {
+				t.Errorf("results not match, want %v, have %v", testCase.ok, err)
+			}
+			if _, err = env.HeldItems("no-such-community", id); err == nil {
+				t.Errorf("moderated a community that does not exist")
+			}
+		})
+	}
+}
+
+func TestChangeModerators_Admin(t *testing.T) {
+	env := newTestEnv()
+	admin := env.addUser(t, "admin", userdata.RoleAdmin)
+	site := env.addUser(t, "site", userdata.RoleModerator)
+	env.addUser(t, "alice", userdata.RoleUser)
+	if _, err := env.AddModerator("music", site, "alice"); err == nil {
+		t.Errorf("site moderator added a moderator")
+	}
+	mods, err := env.AddModerator("music", admin, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mods) != 1 || mods[0] != "alice" {
+		t.Errorf("results not match, want %v, have %v", []string{"alice"}, mods)
+	}
+}
+
+type brokenModLog struct {
+	communitydata.CommunityData
+}
+
+func (brokenModLog) AddModAction(action communitydata.ModAction) error {
+	return errors.New("connection refused")
+}
+
+func TestLockPost_ModLogFailure(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	mod := env.addUser(t, "mod", userdata.RoleModerator)
+	post := env.addPost(t, author, "music", "hello")
+	moderation := NewModerationService(env.users, env.items, brokenModLog{env.communities}, env.spam, env.notes,
+		env.logger)
+	if _, err := moderation.LockPost(post.ID, mod, ""); err == nil {
+		t.Errorf("results not match, want %v, have %v", "error", err)
+	}
+}
+
+func TestPinPost_Limit(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	mod := env.addUser(t, "mod", userdata.RoleModerator)
+	posts := make([]itemdata.Post, 6)
+	for i := range posts {
+		posts[i] = env.addPost(t, author, "music", "hello")
+	}
+
+	var wg sync.WaitGroup
+	for _, el := range posts[:4] {
+		wg.Add(1)
+		go func(postID string) {
+			defer wg.Done()
+			_, _ = env.PinPost(postID, mod, "")
+		}(el.ID)
+	}
+	wg.Wait()
+	pinned, err := env.items.GetPinned("music")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pinned) != itemdata.MaxPinned {
+		t.Fatalf("results not match, want %v, have %v", itemdata.MaxPinned, len(pinned))
+	}
+
+	if _, err = env.PinPost(posts[4].ID, mod, ""); !errors.Is(err, communitydata.ErrTooManyPinned) {
+		t.Errorf("results not match, want %v, have %v", communitydata.ErrTooManyPinned, err)
+	}
+	if _, err = env.UnpinPost(pinned[0].ID, mod, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = env.PinPost(posts[4].ID, mod, ""); err != nil {
+		t.Errorf("slot of an unpinned post not freed: %v", err)
+	}
+	if err = env.DeletePost(pinned[1].ID, author); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = env.PinPost(posts[5].ID, mod, ""); err != nil {
+		t.Errorf("slot of a deleted post not freed: %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	reportdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxMentions        = 10
+	maxExcerptLength   = 200
+	removalNoticeBody  = "removed by the moderators"
+	approvalNoticeBody = "kept by the moderators"
+)
+
+var mentionRe = regexp.MustCompile(`(?:^|[^\w/])u/([\w-]+)`)
+
+var _ Notifications = (*NotificationService)(nil)
+
+type NotificationService struct {
+	dbNotes notificationdata.NotificationData
+}
+
+func NewNotificationService(dbNotes notificationdata.NotificationData) *NotificationService {
+	return &NotificationService{dbNotes: dbNotes}
+}
+
+func (noteServ *NotificationService) ListNotifications(userID string, limit int, unreadOnly bool) ([]notificationdata.Notification, error) {
+	if limit <= 0 || limit > itemdata.MaxPageSize {
+		limit = itemdata.DefaultPageSize
+	}
+	return noteServ.dbNotes.GetNotifications(userID, limit, unreadOnly)
+}
+
+func (noteServ *NotificationService) UnreadCount(userID string) (int, error) {
+	return noteServ.dbNotes.CountUnread(userID)
+}
+
+func (noteServ *NotificationService) MarkRead(userID, id string) error {
+	return noteServ.dbNotes.MarkRead(userID, id)
+}
+
+func (noteServ *NotificationService) MarkAllRead(userID string) error {
+	return noteServ.dbNotes.MarkAllRead(userID)
+}
+
+// notifyComment tells the author of the parent comment, or of the post for a
+// top level comment, about the reply and the users mentioned in it. Nobody
+// is told about their own comment or about one that is held.
+func notifyComment(dbNotes notificationdata.NotificationData, dbUser userdata.UserData,
+	post itemdata.Post, comm itemdata.Comment) error {
+	if comm.Status != "" {
+		return nil
+	}
+	recipient := post.Ath.ID
+	if parent, ok := post.FindComment(comm.ParentID); ok {
+		recipient = parent.Ath.ID
+	}
+	notified := map[string]bool{comm.Ath.ID: true}
+	if !notified[recipient] {
+		notified[recipient] = true
+		if err := addNotification(dbNotes, notificationdata.Notification{UserID: recipient,
+			Kind: notificationdata.KindReply, Actor: comm.Ath.Username, PostID: post.ID, CommentID: comm.ID,
+			Title: post.Title, Body: excerpt(comm.Body)}); err != nil {
+			return err
+		}
+	}
+	return notifyMentions(dbNotes, dbUser, notified, notificationdata.Notification{Actor: comm.Ath.Username,
+		PostID: post.ID, CommentID: comm.ID, Title: post.Title, Body: excerpt(comm.Body)}, comm.Body)
+}
+
+func notifyPost(dbNotes notificationdata.NotificationData, dbUser userdata.UserData, post itemdata.Post) error {
+	if post.Status != "" {
+		return nil
+	}
+	return notifyMentions(dbNotes, dbUser, map[string]bool{post.Ath.ID: true}, notificationdata.Notification{
+		Actor: post.Ath.Username, PostID: post.ID, Title: post.Title, Body: excerpt(post.Text)}, post.Text)
+}
+
+// notifyMentions sends the note to every known user mentioned as u/name in
+// the text who was not notified yet, unknown names are ignored.
+func notifyMentions(dbNotes notificationdata.NotificationData, dbUser userdata.UserData, notified map[string]bool,
+	note notificationdata.Notification, text string) error {
+	for _, match := range mentionRe.FindAllStringSubmatch(text, maxMentions) {
+		userID, err := dbUser.CheckUser(match[1])
+		if err != nil || notified[userID] {
+			continue
+		}
+		notified[userID] = true
+		note.UserID = userID
+		note.Kind = notificationdata.KindMention
+		if err = addNotification(dbNotes, note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyRemoval tells the author that a moderator removed the post or, with
+// comm set, the comment. The moderator is not named.
+func notifyRemoval(dbNotes notificationdata.NotificationData, post itemdata.Post, comm *itemdata.Comment, reason string) error {
+	if reason == "" {
+		reason = removalNoticeBody
+	}
+	note := notificationdata.Notification{UserID: post.Ath.ID, Kind: notificationdata.KindRemoval,
+		PostID: post.ID, Title: post.Title, Body: reason}
+	if comm != nil {
+		note.UserID, note.CommentID = comm.Ath.ID, comm.ID
+	}
+	if note.UserID == "" {
+		return nil
+	}
+	return addNotification(dbNotes, note)
+}
+
+// notifyReporters tells everybody who reported the content what the
+// moderators decided, once per reporter.
+func notifyReporters(dbNotes notificationdata.NotificationData, reports []reportdata.Report, title string) error {
+	notified := make(map[string]bool, len(reports))
+	for _, el := range reports {
+		if notified[el.ReporterID] {
+			continue
+		}
+		notified[el.ReporterID] = true
+		body := approvalNoticeBody
+		if el.Status == reportdata.StatusRemoved {
+			body = removalNoticeBody
+		}
+		if err := addNotification(dbNotes, notificationdata.Notification{UserID: el.ReporterID,
+			Kind: notificationdata.KindReport, PostID: el.PostID, CommentID: el.CommentID, Title: title,
+			Body: body}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addNotification(dbNotes notificationdata.NotificationData, note notificationdata.Notification) error {
+	note.Created = time.Now().UTC().Format("2006-01-02T15:04:05Z07:00")
+	_, err := dbNotes.AddNotification(note)
+	return err
+}
+
+func excerpt(text string) string {
+	if utf8.RuneCountInString(text) <= maxExcerptLength {
+		return text
+	}
+	return string([]rune(text)[:maxExcerptLength]) + "…"
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"testing"
+)
+
+type brokenNotes struct {
+	notificationdata.NotificationData
+}
+
+func (brokenNotes) AddNotification(note notificationdata.Notification) (notificationdata.Notification, error) {
+	return note, errors.New("connection refused")
+}
+
+func TestCreateComm_NotificationFailure(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	other := env.addUser(t, "other", userdata.RoleUser)
+	post := env.addPost(t, author, "music", "hello")
+	comments := NewCommentService(env.users, env.items, env.communities, nil, env.spam, brokenNotes{env.notes},
+		env.logger)
+	res, err := comments.CreateComm(post.ID, other, "", "hi u/author")
+	if err != nil {
+		t.Fatalf("results not match, want %v, have %v", nil, err)
+	}
+	if len(res.Comments) != 1 {
+		t.Errorf("results not match, want %v, have %v", 1, len(res.Comments))
+	}
+}
+
+func TestApprove_Notifies(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	other := env.addUser(t, "other", userdata.RoleUser)
+	friend := env.addUser(t, "friend", userdata.RoleUser)
+	mod := env.addUser(t, "mod", userdata.RoleModerator)
+	env.trainSpam(t)
+	post := env.addPost(t, author, "music", "my cat learned to open the door")
+	post, err := env.CreateComm(post.ID, other, "", spamText+" u/friend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	comm := post.Comments[0]
+	if comm.Status != itemdata.StatusHeld {
+		t.Fatalf("results not match, want %v, have %v", itemdata.StatusHeld, comm.Status)
+	}
+	if notes, _ := env.notes.GetNotifications(author, 10, false); len(notes) != 0 {
+		t.Fatalf("notified about a held comment: %v", notes)
+	}
+	if _, err = env.Approve(post.ID, comm.ID, mod); err != nil {
+		t.Fatal(err)
+	}
+
+	testingTable := []struct {
+		name   string
+		userID string
+		kind   string
+	}{
+		{name: "post author", userID: author, kind: notificationdata.KindReply},
+		{name: "mentioned", userID: friend, kind: notificationdata.KindMention},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			notes, err := env.notes.GetNotifications(testCase.userID, 10, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(notes) != 1 || notes[0].Kind != testCase.kind || notes[0].CommentID != comm.ID {
+				t.Errorf("results not match, want %v, have %v", testCase.kind, notes)
+			}
+		})
+	}
+}
@@ -2,22 +2,44 @@ package service
 
 import (
 	"errors"
+	"github.com/asaskevich/govalidator"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/automod"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/blob"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/imaging"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
 	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	spamdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/spamData"
 	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"log"
 	"time"
 )
 
 var _ Posts = (*PostService)(nil)
 
 type PostService struct {
-	dbUser  userdata.UserData
-	dbPosts itemdata.ItemData
+	dbUser        userdata.UserData
+	dbPosts       itemdata.ItemData
+	dbCommunities communitydata.CommunityData
+	automod       *automod.Engine
+	dbSpam        spamdata.SpamData
+	dbNotes       notificationdata.NotificationData
+	blobs         blob.BlobStore
+	logger        *log.Logger
 }
 
-func NewPostService(dbUser userdata.UserData, dbPosts itemdata.ItemData) *PostService {
+func NewPostService(dbUser userdata.UserData, dbPosts itemdata.ItemData, dbCommunities communitydata.CommunityData,
+	engine *automod.Engine, dbSpam spamdata.SpamData, dbNotes notificationdata.NotificationData,
+	blobs blob.BlobStore, logger *log.Logger) *PostService {
 	return &PostService{
-		dbUser:  dbUser,
-		dbPosts: dbPosts,
+		dbUser:        dbUser,
+		dbPosts:       dbPosts,
+		dbCommunities: dbCommunities,
+		automod:       engine,
+		dbSpam:        dbSpam,
+		dbNotes:       dbNotes,
+		blobs:         blobs,
+		logger:        logger,
 	}
 }
 
@@ -26,52 +48,283 @@ func (postServ *PostService) CreatePost(post itemdata.CreatePost, userID string)
 	if err != nil {
 		return itemdata.Post{}, err
 	}
+	if _, err = postServ.dbCommunities.GetCommunity(post.Cat); err != nil {
+		return itemdata.Post{}, errors.New("invalid category")
+	}
+	if err = checkBanned(postServ.dbCommunities, post.Cat, userID); err != nil {
+		return itemdata.Post{}, err
+	}
+	if (post.Type == "image") != (len(post.Image) > 0) {
+		return itemdata.Post{}, errors.New("invalid image")
+	}
+	var img imaging.Image
+	if post.Type == "image" {
+		if img, err = imaging.Process(post.Image); err != nil {
+			return itemdata.Post{}, err
+		}
+	}
 	resp := itemdata.Post{
 		Ath: itemdata.Author{
 			ID:       userID,
 			Username: us.Login,
 		},
-		Comments:         make([]itemdata.Comment, 0, 10),
-		Cat:              post.Cat,
-		Score:            1,
-		Type:             post.Type,
-		Title:            post.Title,
-		Created:          time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
-		UpvotePercentage: 100,
-		Views:            0,
-		Text:             post.Text,
-		Vote: []itemdata.Votes{
-			{
-				Vote: 1,
-				User: us.ID,
-			},
-		},
+		Comments: make([]itemdata.Comment, 0, 10),
+		Cat:      post.Cat,
+		Type:     post.Type,
+		Title:    post.Title,
+		Created:  time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
+		Views:    0,
+		Text:     post.Text,
+	}
+	decision, err := runAutomod(postServ.automod, postServ.dbPosts, us, automod.KindPost, post.Cat, post.Title, post.Text)
+	if err != nil {
+		return itemdata.Post{}, err
+	}
+	if err = checkSpam(postServ.dbSpam, &decision, post.Title+"\n"+post.Text); err != nil {
+		return itemdata.Post{}, err
 	}
-	return postServ.dbPosts.CreatePost(resp)
+	resp.Status = automodStatus(decision)
+	resp.Flair = decision.Flair
+	resp.Comments = append(resp.Comments, automodReplies(decision, "")...)
+	resp.SetVote(us.ID, 1)
+	// Nothing deletes the blobs of a removed post, so they are not stored.
+	// Approving such a post publishes it without the image.
+	if post.Type == "image" && resp.Status != itemdata.StatusRemoved {
+		if resp.Image, err = storeImage(postServ.blobs, img); err != nil {
+			return itemdata.Post{}, err
+		}
+	}
+	created, err := postServ.dbPosts.CreatePost(resp)
+	if err != nil {
+		_ = deleteImage(postServ.blobs, resp.Image)
+		return itemdata.Post{}, err
+	}
+	resp = created
+	if err = logAutomod(postServ.dbCommunities, post.Cat, resp.ID, communitydata.ActionRemovePost, decision); err != nil {
+		return itemdata.Post{}, err
+	}
+	logFailure(postServ.logger, "post notification", notifyPost(postServ.dbNotes, postServ.dbUser, resp))
+	return resp, nil
 }
 
-func (postServ *PostService) GetPosts() ([]itemdata.Post, error) {
-	return postServ.dbPosts.GetPosts()
+func (postServ *PostService) GetPosts(opts itemdata.ListOptions) (itemdata.PostPage, error) {
+	opts, err := postServ.withoutHidden(opts)
+	if err != nil {
+		return itemdata.PostPage{}, err
+	}
+	return page(postServ.dbPosts.GetPosts, opts)
+}
+
+// GetCategory puts the pinned posts of the community on top of the first page
+// and leaves them out of the listing itself.
+func (postServ *PostService) GetCategory(category string, opts itemdata.ListOptions) (itemdata.PostPage, error) {
+	first := opts.After.ID == ""
+	opts, err := postServ.withoutHidden(opts)
+	if err != nil {
+		return itemdata.PostPage{}, err
+	}
+	res, err := page(func(opts itemdata.ListOptions) ([]itemdata.Post, error) {
+		return postServ.dbPosts.GetCategory(category, opts)
+	}, opts)
+	if err != nil {
+		return res, err
+	}
+	pinned, err := postServ.dbPosts.GetPinned(category)
+	if err != nil {
+		return itemdata.PostPage{}, err
+	}
+	if len(pinned) == 0 {
+		return res, nil
+	}
+	posts := make([]itemdata.Post, 0, len(res.Posts)+len(pinned))
+	if first {
+		posts = append(posts, pinned...)
+	}
+	for _, el := range res.Posts {
+		if !el.Pinned {
+			posts = append(posts, el)
+		}
+	}
+	res.Posts = posts
+	return res, nil
 }
 
-func (postServ *PostService) GetCategory(category string) ([]itemdata.Post, error) {
-	return postServ.dbPosts.GetCategory(category)
+func (postServ *PostService) GetName(login string, opts itemdata.ListOptions) (itemdata.PostPage, error) {
+	return page(func(opts itemdata.ListOptions) ([]itemdata.Post, error) {
+		return postServ.dbPosts.GetName(login, opts)
+	}, opts)
 }
 
-func (postServ *PostService) GetName(login string) ([]itemdata.Post, error) {
-	return postServ.dbPosts.GetName(login)
+// GetFeed lists the posts of the communities the viewer subscribed to and of
+// the users they follow. Anonymous viewers and the ones that subscribed to
+// nothing and follow nobody get the global listing.
+func (postServ *PostService) GetFeed(opts itemdata.ListOptions) (itemdata.PostPage, error) {
+	if opts.Viewer == "" {
+		return postServ.GetPosts(opts)
+	}
+	categories, err := postServ.dbCommunities.Subscriptions(opts.Viewer)
+	if err != nil {
+		return itemdata.PostPage{}, err
+	}
+	authors, err := postServ.dbUser.Following(opts.Viewer)
+	if err != nil {
+		return itemdata.PostPage{}, err
+	}
+	if len(categories) == 0 && len(authors) == 0 {
+		return postServ.GetPosts(opts)
+	}
+	if opts, err = postServ.withoutHidden(opts); err != nil {
+		return itemdata.PostPage{}, err
+	}
+	return page(func(opts itemdata.ListOptions) ([]itemdata.Post, error) {
+		return postServ.dbPosts.GetFeed(categories, authors, opts)
+	}, opts)
 }
 
-func (postServ *PostService) GetPostID(id string) (itemdata.Post, error) {
-	post, err := postServ.dbPosts.GetPostID(id)
+// withoutHidden leaves the posts the viewer hid out of the listing.
+func (postServ *PostService) withoutHidden(opts itemdata.ListOptions) (itemdata.ListOptions, error) {
+	if opts.Viewer == "" {
+		return opts, nil
+	}
+	hidden, err := postServ.dbUser.Items(opts.Viewer, userdata.ListHidden, userdata.MaxHidden)
 	if err != nil {
-		return itemdata.Post{}, err
+		return opts, err
+	}
+	opts.Exclude = make(map[string]bool, len(hidden))
+	for _, el := range hidden {
+		if el.CommentID == "" {
+			opts.Exclude[el.PostID] = true
+		}
+	}
+	return opts, nil
+}
+
+// page asks for one post more than the limit to know whether there is a next page.
+func page(list func(opts itemdata.ListOptions) ([]itemdata.Post, error), opts itemdata.ListOptions) (itemdata.PostPage, error) {
+	if opts.Limit <= 0 || opts.Limit > itemdata.MaxPageSize {
+		opts.Limit = itemdata.DefaultPageSize
 	}
-	post.Views++
-	if err = postServ.dbPosts.SetPost(post); err != nil {
+	asOf := opts.After.AsOf
+	if asOf == "" {
+		asOf = time.Now().UTC().Format("2006-01-02T15:04:05Z07:00")
+		opts.After.AsOf = asOf
+	}
+	limit := opts.Limit
+	opts.Limit++
+	posts, err := list(opts)
+	if err != nil {
+		return itemdata.PostPage{}, err
+	}
+	if len(posts) <= limit {
+		return itemdata.PostPage{Posts: posts}, nil
+	}
+	posts = posts[:limit]
+	return itemdata.PostPage{
+		Posts: posts,
+		Next:  itemdata.CursorAfter(posts[limit-1], opts.Sort, asOf).Encode(),
+	}, nil
+}
+
+// GetPostID leaves out the comments the viewer hid, viewerID is empty for anonymous users.
+func (postServ *PostService) GetPostID(id, order, viewerID string) (itemdata.Post, error) {
+	var post itemdata.Post
+	err := retryOnConflict(func() error {
+		var err error
+		post, err = postServ.dbPosts.GetPostID(id)
+		if err != nil {
+			return err
+		}
+		if !postServ.visible(post, viewerID) {
+			return errors.New("invalid post id")
+		}
+		post.Views++
+		return postServ.dbPosts.SetPost(post)
+	})
+	if err != nil {
 		return post, err
 	}
-	return post, nil
+	// The stored post may share its comments with the backend, sort a copy.
+	comments := make([]itemdata.Comment, len(post.Comments))
+	copy(comments, post.Comments)
+	post.Comments = comments
+	if viewerID != "" {
+		hidden, err := postServ.dbUser.Items(viewerID, userdata.ListHidden, userdata.MaxHidden)
+		if err != nil {
+			return itemdata.Post{}, err
+		}
+		comments := make(map[string]bool)
+		for _, el := range hidden {
+			if el.PostID == post.ID && el.CommentID != "" {
+				comments[el.CommentID] = true
+			}
+		}
+		post.Comments = post.CommentsWithout(comments)
+	}
+	return post, itemdata.SortComments(post.Comments, order)
+}
+
+// visible hides a held or removed post from everybody but its author and the moderators.
+func (postServ *PostService) visible(post itemdata.Post, viewerID string) bool {
+	if post.Status == "" || viewerID != "" && post.Ath.ID == viewerID {
+		return true
+	}
+	return viewerID != "" && checkModerator(postServ.dbUser, postServ.dbCommunities, post.Cat, viewerID) == nil
+}
+
+// EditPost checks the new text like a new post, an edit can hold or remove a published post.
+func (postServ *PostService) EditPost(id, userID, text string) (itemdata.Post, error) {
+	us, err := postServ.dbUser.GetUser(userID)
+	if err != nil {
+		return itemdata.Post{}, err
+	}
+	karma, err := automodKarma(postServ.automod, postServ.dbPosts, userID)
+	if err != nil {
+		return itemdata.Post{}, err
+	}
+	var post itemdata.Post
+	var decision automod.Decision
+	err = retryOnConflict(func() error {
+		var err error
+		post, err = postServ.dbPosts.GetPostID(id)
+		if err != nil {
+			return err
+		}
+		if post.Ath.ID != userID {
+			return errors.New("invalid user id")
+		}
+		if post.Type == "link" && !govalidator.IsURL(text) {
+			return errors.New("invalid URL")
+		}
+		decision = automod.Decision{}
+		if post.Status == "" {
+			decision = evaluateAutomod(postServ.automod, us, karma, automod.KindPost, post.Cat, post.Title, text)
+			if err = checkSpam(postServ.dbSpam, &decision, post.Title+"\n"+text); err != nil {
+				return err
+			}
+			post.Status = automodStatus(decision)
+		}
+		post.Edit(text, time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"))
+		return postServ.dbPosts.SetPost(post)
+	})
+	if err != nil {
+		return itemdata.Post{}, err
+	}
+	return post, logAutomod(postServ.dbCommunities, post.Cat, post.ID, communitydata.ActionRemovePost, decision)
+}
+
+// PostHistory is shown to the author and to the moderators of the community,
+// the comment revisions are left to CommentHistory.
+func (postServ *PostService) PostHistory(id, userID string) ([]itemdata.Revision, error) {
+	post, err := postServ.dbPosts.GetPostID(id)
+	if err != nil {
+		return nil, err
+	}
+	if post.Ath.ID != userID {
+		if err = checkModerator(postServ.dbUser, postServ.dbCommunities, post.Cat, userID); err != nil {
+			return nil, err
+		}
+	}
+	return post.PostRevisions(), nil
 }
 
 func (postServ *PostService) DeletePost(id string, userID string) error {
@@ -79,8 +332,37 @@ func (postServ *PostService) DeletePost(id string, userID string) error {
 	if err != nil {
 		return err
 	}
-	if post.Ath.ID != userID {
-		return errors.New("invalid user id")
+	if post.Ath.ID == userID {
+		if err = postServ.dbPosts.DeletePost(id); err != nil {
+			return err
+		}
+		logFailure(postServ.logger, "image removal", deleteImage(postServ.blobs, post.Image))
+		return nil
+	}
+	if err = checkModerator(postServ.dbUser, postServ.dbCommunities, post.Cat, userID); err != nil {
+		return err
+	}
+	if err = postServ.dbPosts.DeletePost(id); err != nil {
+		return err
+	}
+	logFailure(postServ.logger, "image removal", deleteImage(postServ.blobs, post.Image))
+	if err = logModAction(postServ.dbUser, postServ.dbCommunities, post.Cat, userID,
+		communitydata.ActionRemovePost, post.ID, ""); err != nil {
+		return err
+	}
+	if err = trainSpam(postServ.dbSpam, postText(post), true); err != nil {
+		return err
+	}
+	logFailure(postServ.logger, "removal notification", notifyRemoval(postServ.dbNotes, post, nil, ""))
+	return nil
+}
+
+func (postServ *PostService) Search(query itemdata.SearchQuery) ([]itemdata.SearchHit, error) {
+	if len(query.Terms()) == 0 {
+		return nil, errors.New("invalid query")
+	}
+	if query.Limit <= 0 || query.Limit > itemdata.MaxPageSize {
+		query.Limit = itemdata.DefaultPageSize
 	}
-	return postServ.dbPosts.DeletePost(id)
+	return postServ.dbPosts.Search(query)
 }
@@ -0,0 +1,230 @@
+package service
+
+import (
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"strconv"
+	"testing"
+)
+
+func TestPostHistory(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	mod := env.addUser(t, "mod", userdata.RoleUser)
+	site := env.addUser(t, "site", userdata.RoleModerator)
+	other := env.addUser(t, "other", userdata.RoleUser)
+	if err := env.communities.AddModerator("music", mod); err != nil {
+		t.Fatal(err)
+	}
+	post := env.addPost(t, author, "music", "first")
+	if _, err := env.EditPost(post.ID, author, "second"); err != nil {
+		t.Fatal(err)
+	}
+	commenter := env.addUser(t, "commenter", userdata.RoleUser)
+	post, err := env.CreateComm(post.ID, commenter, "", "rude")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commID := post.Comments[len(post.Comments)-1].ID
+	if _, err = env.EditComm(post.ID, commenter, commID, "polite"); err != nil {
+		t.Fatal(err)
+	}
+
+	testingTable := []struct {
+		name   string
+		userID string
+		ok     bool
+	}{
+		{name: "author", userID: author, ok: true},
+		{name: "moderator", userID: mod, ok: true},
+		{name: "site moderator", userID: site, ok: true},
+		{name: "other user", userID: other, ok: false},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			history, err := env.PostHistory(post.ID, testCase.userID)
+			if !testCase.ok {
+				if err == nil {
+					t.Errorf("history shown to %v", testCase.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(history) != 1 || history[0].Body != "first" {
+				t.Errorf("results not match, want %v, have %v", "first", history)
+			}
+		})
+	}
+}
+
+func TestCommentHistory(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	commenter := env.addUser(t, "commenter", userdata.RoleUser)
+	mod := env.addUser(t, "mod", userdata.RoleUser)
+	if err := env.communities.AddModerator("music", mod); err != nil {
+		t.Fatal(err)
+	}
+	post := env.addPost(t, author, "music", "first")
+	post, err := env.CreateComm(post.ID, commenter, "", "rude")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commID := post.Comments[len(post.Comments)-1].ID
+	if _, err = env.EditComm(post.ID, commenter, commID, "polite"); err != nil {
+		t.Fatal(err)
+	}
+
+	testingTable := []struct {
+		name   string
+		userID string
+		ok     bool
+	}{
+		{name: "comment author", userID: commenter, ok: true},
+		{name: "moderator", userID: mod, ok: true},
+		{name: "post author", userID: author, ok: false},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			history, err := env.CommentHistory(post.ID, testCase.userID, commID)
+			if !testCase.ok {
+				if err == nil {
+					t.Errorf("history shown to %v", testCase.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(history) != 1 || history[0].Body != "rude" {
+				t.Errorf("results not match, want %v, have %v", "rude", history)
+			}
+		})
+	}
+}
+
+func (env *testEnv) setStatus(t *testing.T, postID, commentID, status string) {
+	t.Helper()
+	post, err := env.items.GetPostID(postID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commentID == "" {
+		post.Status = status
+	} else if err = post.SetCommentStatus(commentID, status); err != nil {
+		t.Fatal(err)
+	}
+	if err = env.items.SetPost(post); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetPostID_NotPublic(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	mod := env.addUser(t, "mod", userdata.RoleUser)
+	site := env.addUser(t, "site", userdata.RoleModerator)
+	other := env.addUser(t, "other", userdata.RoleUser)
+	if err := env.communities.AddModerator("music", mod); err != nil {
+		t.Fatal(err)
+	}
+	held := env.addPost(t, author, "music", "held")
+	env.setStatus(t, held.ID, "", itemdata.StatusHeld)
+	removed := env.addPost(t, author, "music", "removed")
+	env.setStatus(t, removed.ID, "", itemdata.StatusRemoved)
+
+	testingTable := []struct {
+		name     string
+		viewerID string
+		ok       bool
+	}{
+		{name: "anonymous", viewerID: "", ok: false},
+		{name: "other user", viewerID: other, ok: false},
+		{name: "author", viewerID: author, ok: true},
+		{name: "moderator", viewerID: mod, ok: true},
+		{name: "site moderator", viewerID: site, ok: true},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			for _, id := range []string{held.ID, removed.ID} {
+				post, err := env.GetPostID(id, "old", testCase.viewerID)
+				if !testCase.ok {
+					if err == nil || err.Error() != "invalid post id" {
+						t.Errorf("results not match, want %v, have %v", "invalid post id", err)
+					}
+					continue
+				}
+				if err != nil || post.ID != id {
+					t.Errorf("results not match, want %v, have %v %v", id, post.ID, err)
+				}
+			}
+		})
+	}
+	if post, _ := env.items.GetPostID(held.ID); post.Views != 3 {
+		t.Errorf("results not match, want %v, have %v", 3, post.Views)
+	}
+}
+
+func TestCreateComm_NotPublic(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	other := env.addUser(t, "other", userdata.RoleUser)
+	held := env.addPost(t, author, "music", "held")
+	env.setStatus(t, held.ID, "", itemdata.StatusHeld)
+	if _, err := env.CreateComm(held.ID, other, "", "hi"); err == nil || err.Error() != "invalid post id" {
+		t.Errorf("results not match, want %v, have %v", "invalid post id", err)
+	}
+
+	post := env.addPost(t, author, "music", "public")
+	post, err := env.CreateComm(post.ID, other, "", "parent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := post.Comments[0].ID
+	post, err = env.CreateComm(post.ID, author, parent, "reply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply := post.Comments[1].ID
+	env.setStatus(t, post.ID, parent, itemdata.StatusHeld)
+	for _, parentID := range []string{parent, reply} {
+		if _, err = env.CreateComm(post.ID, other, parentID, "hi"); err == nil || err.Error() != "invalid parent id" {
+			t.Errorf("results not match, want %v, have %v", "invalid parent id", err)
+		}
+	}
+	if _, err = env.CreateComm(post.ID, other, "", "top level"); err != nil {
+		t.Errorf("results not match, want %v, have %v", nil, err)
+	}
+}
+
+func TestHideItem_Limit(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	viewer := env.addUser(t, "viewer", userdata.RoleUser)
+	first := env.addPost(t, author, "music", "first")
+	post := env.addPost(t, author, "music", "second")
+	if err := env.HideItem(first.ID, "", viewer); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < userdata.MaxHidden; i++ {
+		ref := userdata.ItemRef{PostID: "gone" + strconv.Itoa(i), Created: "2022-11-04T17:55:14Z"}
+		if err := env.users.AddItem(viewer, userdata.ListHidden, ref); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := env.HideItem(post.ID, "", viewer); err == nil {
+		t.Errorf("results not match, want %v, have %v", "too many hidden items", err)
+	}
+	if err := env.HideItem(first.ID, "", viewer); err != nil {
+		t.Errorf("results not match, want %v, have %v", nil, err)
+	}
+	page, err := env.GetPosts(itemdata.ListOptions{Viewer: viewer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Posts) != 1 || page.Posts[0].ID != post.ID {
+		t.Errorf("results not match, want %v, have %v", []string{post.ID}, page.Posts)
+	}
+}
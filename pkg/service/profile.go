@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"sort"
+	"time"
+)
+
+var _ Profiles = (*ProfileService)(nil)
+
+type ProfileService struct {
+	dbUser  userdata.UserData
+	dbPosts itemdata.ItemData
+}
+
+func NewProfileService(dbUser userdata.UserData, dbPosts itemdata.ItemData) *ProfileService {
+	return &ProfileService{
+		dbUser:  dbUser,
+		dbPosts: dbPosts,
+	}
+}
+
+func (profServ *ProfileService) Profile(login string) (userdata.Profile, error) {
+	userID, err := profServ.dbUser.CheckUser(login)
+	if err != nil {
+		return userdata.Profile{}, errors.New("invalid user login")
+	}
+	user, err := profServ.dbUser.GetUser(userID)
+	if err != nil {
+		return userdata.Profile{}, err
+	}
+	karma, err := profServ.dbPosts.GetKarma(userID)
+	if err != nil {
+		return userdata.Profile{}, err
+	}
+	return userdata.Profile{
+		Login:        user.Login,
+		Created:      user.Created,
+		AgeDays:      int(user.Age(time.Now().UTC()).Hours() / 24),
+		PostKarma:    karma.Post,
+		CommentKarma: karma.Comment,
+		Karma:        karma.Total(),
+	}, nil
+}
+
+func (profServ *ProfileService) UserComments(login string, opts itemdata.ListOptions) (itemdata.ActivityPage, error) {
+	if _, err := profServ.dbUser.CheckUser(login); err != nil {
+		return itemdata.ActivityPage{}, errors.New("invalid user login")
+	}
+	return activityPage(func(opts itemdata.ListOptions) ([]itemdata.Activity, error) {
+		return profServ.dbPosts.GetUserComments(login, opts)
+	}, opts)
+}
+
+// Overview merges the posts and the comments of the user into one history, newest first.
+func (profServ *ProfileService) Overview(login string, opts itemdata.ListOptions) (itemdata.ActivityPage, error) {
+	if _, err := profServ.dbUser.CheckUser(login); err != nil {
+		return itemdata.ActivityPage{}, errors.New("invalid user login")
+	}
+	return activityPage(func(opts itemdata.ListOptions) ([]itemdata.Activity, error) {
+		posts, err := profServ.dbPosts.GetName(login, opts)
+		if err != nil {
+			return nil, err
+		}
+		res, err := profServ.dbPosts.GetUserComments(login, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, el := range posts {
+			res = append(res, el.Activity())
+		}
+		sort.Slice(res, func(i, j int) bool {
+			return itemdata.LessActivity(res[i], res[j])
+		})
+		if len(res) > opts.Limit {
+			res = res[:opts.Limit]
+		}
+		return res, nil
+	}, opts)
+}
+
+// activityPage is page for the histories, they are always sorted by new.
+func activityPage(list func(opts itemdata.ListOptions) ([]itemdata.Activity, error), opts itemdata.ListOptions) (itemdata.ActivityPage, error) {
+	opts.Sort = itemdata.SortNew
+	opts.Window = 0
+	if opts.Limit <= 0 || opts.Limit > itemdata.MaxPageSize {
+		opts.Limit = itemdata.DefaultPageSize
+	}
+	asOf := opts.After.AsOf
+	if asOf == "" {
+		asOf = time.Now().UTC().Format("2006-01-02T15:04:05Z07:00")
+		opts.After.AsOf = asOf
+	}
+	limit := opts.Limit
+	opts.Limit++
+	items, err := list(opts)
+	if err != nil {
+		return itemdata.ActivityPage{}, err
+	}
+	if len(items) <= limit {
+		return itemdata.ActivityPage{Items: items}, nil
+	}
+	items = items[:limit]
+	return itemdata.ActivityPage{
+		Items: items,
+		Next:  itemdata.CursorAfterActivity(items[limit-1], asOf).Encode(),
+	}, nil
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/blob"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	reportdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData"
+	spamdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/spamData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxReasonLength = 200
+
+var _ Reports = (*ReportService)(nil)
+
+type ReportService struct {
+	dbUser        userdata.UserData
+	dbPosts       itemdata.ItemData
+	dbCommunities communitydata.CommunityData
+	dbReports     reportdata.ReportData
+	dbSpam        spamdata.SpamData
+	dbNotes       notificationdata.NotificationData
+	blobs         blob.BlobStore
+	logger        *log.Logger
+}
+
+func NewReportService(dbUser userdata.UserData, dbPosts itemdata.ItemData, dbCommunities communitydata.CommunityData,
+	dbReports reportdata.ReportData, dbSpam spamdata.SpamData, dbNotes notificationdata.NotificationData,
+	blobs blob.BlobStore, logger *log.Logger) *ReportService {
+	return &ReportService{
+		dbUser:        dbUser,
+		dbPosts:       dbPosts,
+		dbCommunities: dbCommunities,
+		dbReports:     dbReports,
+		dbSpam:        dbSpam,
+		dbNotes:       dbNotes,
+		blobs:         blobs,
+		logger:        logger,
+	}
+}
+
+func (repServ *ReportService) ReportPost(postID, userID, reason string) (reportdata.Report, error) {
+	return repServ.report(postID, "", userID, reason)
+}
+
+func (repServ *ReportService) ReportComment(postID, commentID, userID, reason string) (reportdata.Report, error) {
+	return repServ.report(postID, commentID, userID, reason)
+}
+
+func (repServ *ReportService) report(postID, commentID, userID, reason string) (reportdata.Report, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxReasonLength {
+		return reportdata.Report{}, errors.New("invalid reason")
+	}
+	post, err := repServ.dbPosts.GetPostID(postID)
+	if err != nil {
+		return reportdata.Report{}, err
+	}
+	if commentID != "" {
+		comment, ok := post.FindComment(commentID)
+		if !ok || comment.Deleted {
+			return reportdata.Report{}, errors.New("invalid comment id")
+		}
+	}
+	return repServ.dbReports.AddReport(reportdata.Report{
+		PostID:     postID,
+		CommentID:  commentID,
+		Community:  post.Cat,
+		ReporterID: userID,
+		Reason:     reason,
+		Created:    time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+func (repServ *ReportService) ReportQueue(community, userID string, limit int) ([]reportdata.QueueItem, error) {
+	if err := checkModerator(repServ.dbUser, repServ.dbCommunities, community, userID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > itemdata.MaxPageSize {
+		limit = itemdata.DefaultPageSize
+	}
+	return repServ.dbReports.GetQueue(community, limit)
+}
+
+// ApproveReported keeps the content, clears its reports and tells the reporters.
+func (repServ *ReportService) ApproveReported(community, userID, postID, commentID string) ([]reportdata.Report, error) {
+	if err := checkModerator(repServ.dbUser, repServ.dbCommunities, community, userID); err != nil {
+		return nil, err
+	}
+	reports, err := repServ.dbReports.Resolve(community, postID, commentID, reportdata.StatusApproved,
+		time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"))
+	if err != nil {
+		return nil, err
+	}
+	text, title := "", ""
+	if post, err := repServ.dbPosts.GetPostID(postID); err == nil {
+		text, title = itemText(post, commentID), post.Title
+	}
+	if text != "" {
+		if err = trainSpam(repServ.dbSpam, text, false); err != nil {
+			return nil, err
+		}
+	}
+	logFailure(repServ.logger, "report notification", notifyReporters(repServ.dbNotes, reports, title))
+	return reports, nil
+}
+
+// RemoveReported removes the content, unless it is already gone, then clears
+// its reports and tells the reporters. The reports stay open when the removal
+// fails, so the moderator can repeat it.
+func (repServ *ReportService) RemoveReported(community, userID, postID, commentID string) ([]reportdata.Report, error) {
+	if err := checkModerator(repServ.dbUser, repServ.dbCommunities, community, userID); err != nil {
+		return nil, err
+	}
+	open, err := repServ.dbReports.HasOpen(community, postID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if !open {
+		return nil, errors.New("invalid report")
+	}
+	text := ""
+	post, err := repServ.dbPosts.GetPostID(postID)
+	if err == nil {
+		if post.Cat != community {
+			return nil, errors.New("invalid report")
+		}
+		text = itemText(post, commentID)
+	} else if !errors.Is(err, itemdata.ErrPostNotFound) {
+		return nil, err
+	}
+	action, target := communitydata.ActionRemovePost, postID
+	if commentID == "" {
+		err = repServ.removePost(postID)
+	} else {
+		action, target = communitydata.ActionRemoveComment, postID+"/"+commentID
+		err = repServ.removeComment(postID, commentID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = logModAction(repServ.dbUser, repServ.dbCommunities, community, userID, action, target,
+		"reported"); err != nil {
+		return nil, err
+	}
+	reports, err := repServ.dbReports.Resolve(community, postID, commentID, reportdata.StatusRemoved,
+		time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"))
+	if err != nil {
+		return nil, err
+	}
+	if text != "" {
+		if err = trainSpam(repServ.dbSpam, text, true); err != nil {
+			return nil, err
+		}
+		var comment *itemdata.Comment
+		if commentID != "" {
+			found, _ := post.FindComment(commentID)
+			comment = &found
+		}
+		logFailure(repServ.logger, "removal notification", notifyRemoval(repServ.dbNotes, post, comment, "reported"))
+	}
+	logFailure(repServ.logger, "report notification", notifyReporters(repServ.dbNotes, reports, post.Title))
+	return reports, nil
+}
+
+// removePost and removeComment take content that is already gone for removed.
+func (repServ *ReportService) removePost(postID string) error {
+	post, err := repServ.dbPosts.GetPostID(postID)
+	if errors.Is(err, itemdata.ErrPostNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err = repServ.dbPosts.DeletePost(postID); err != nil {
+		return err
+	}
+	logFailure(repServ.logger, "image removal", deleteImage(repServ.blobs, post.Image))
+	return nil
+}
+
+func (repServ *ReportService) removeComment(postID, commentID string) error {
+	return retryOnConflict(func() error {
+		post, err := repServ.dbPosts.GetPostID(postID)
+		if errors.Is(err, itemdata.ErrPostNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if comment, ok := post.FindComment(commentID); !ok || comment.Deleted {
+			return nil
+		}
+		post.DeleteComment(commentID)
+		return repServ.dbPosts.SetPost(post)
+	})
+}
+
+// MyReports shows a reporter what became of the reports.
+func (repServ *ReportService) MyReports(userID string, limit int) ([]reportdata.Report, error) {
+	if limit <= 0 || limit > itemdata.MaxPageSize {
+		limit = itemdata.DefaultPageSize
+	}
+	return repServ.dbReports.GetByReporter(userID, limit)
+}
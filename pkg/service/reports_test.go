@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"testing"
+)
+
+func TestResolveReported_NotifiesReporters(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	alice := env.addUser(t, "alice", userdata.RoleUser)
+	bob := env.addUser(t, "bob", userdata.RoleUser)
+	mod := env.addUser(t, "mod", userdata.RoleModerator)
+	kept := env.addPost(t, author, "music", "fine")
+	removed := env.addPost(t, author, "music", "spam")
+	for _, el := range []struct{ postID, userID, reason string }{
+		{kept.ID, alice, "rude"}, {removed.ID, alice, "spam"}, {removed.ID, bob, "ads"},
+	} {
+		if _, err := env.ReportPost(el.postID, el.userID, el.reason); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := env.ApproveReported("music", mod, kept.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.RemoveReported("music", mod, removed.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	testingTable := []struct {
+		name   string
+		userID string
+		want   []string
+	}{
+		{name: "alice", userID: alice, want: []string{removed.ID + " " + removalNoticeBody, kept.ID + " " + approvalNoticeBody}},
+		{name: "bob", userID: bob, want: []string{removed.ID + " " + removalNoticeBody}},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			notes, err := env.notes.GetNotifications(testCase.userID, 10, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			have := make([]string, 0, len(notes))
+			for _, el := range notes {
+				if el.Kind != notificationdata.KindReport || el.Title != "title" {
+					t.Errorf("unexpected notification %+v", el)
+				}
+				have = append(have, el.PostID+" "+el.Body)
+			}
+			if len(have) != len(testCase.want) {
+				t.Fatalf("results not match, want %v, have %v", testCase.want, have)
+			}
+			for i := range have {
+				if have[i] != testCase.want[i] {
+					t.Errorf("results not match, want %v, have %v", testCase.want, have)
+				}
+			}
+		})
+	}
+}
+
+type brokenItems struct {
+	itemdata.ItemData
+	getErr error
+}
+
+func (items brokenItems) GetPostID(id string) (itemdata.Post, error) {
+	if items.getErr != nil {
+		return itemdata.Post{}, items.getErr
+	}
+	return items.ItemData.GetPostID(id)
+}
+
+func (brokenItems) DeletePost(postID string) error {
+	return errors.New("connection refused")
+}
+
+func TestRemoveReported_RemovalFailure(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	alice := env.addUser(t, "alice", userdata.RoleUser)
+	mod := env.addUser(t, "mod", userdata.RoleModerator)
+	post := env.addPost(t, author, "music", "spam")
+	if _, err := env.ReportPost(post.ID, alice, "spam"); err != nil {
+		t.Fatal(err)
+	}
+
+	testingTable := []struct {
+		name  string
+		items brokenItems
+	}{
+		{name: "delete error", items: brokenItems{ItemData: env.items}},
+		{name: "read error", items: brokenItems{ItemData: env.items, getErr: errors.New("connection refused")}},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			reports := NewReportService(env.users, testCase.items, env.communities, env.reports, env.spam, env.notes,
+				env.blobs, env.logger)
+			if _, err := reports.RemoveReported("music", mod, post.ID, ""); err == nil {
+				t.Fatalf("results not match, want %v, have %v", "error", err)
+			}
+			if ok, _ := env.reports.HasOpen("music", post.ID, ""); !ok {
+				t.Errorf("reports closed while the post stayed")
+			}
+		})
+	}
+	if _, err := env.RemoveReported("music", mod, post.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.items.GetPostID(post.ID); !errors.Is(err, itemdata.ErrPostNotFound) {
+		t.Errorf("results not match, want %v, have %v", itemdata.ErrPostNotFound, err)
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"errors"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"log"
+)
+
+const maxConflictRetries = 5
+
+// retryOnConflict reruns a read-modify-write of a post while SetPost reports
+// that somebody else changed the post in between.
+func retryOnConflict(fn func() error) error {
+	var err error
+	for i := 0; i < maxConflictRetries; i++ {
+		err = fn()
+		var conflict *itemdata.ConflictError
+		if !errors.As(err, &conflict) {
+			return err
+		}
+	}
+	return err
+}
+
+// logFailure records a failed notification or file cleanup after a write that
+// already succeeded. Failing the request instead would make the client repeat
+// the write. The mod log is not one of them, an action must not go unlogged.
+func logFailure(logger *log.Logger, what string, err error) {
+	if err != nil {
+		logger.Printf("%s failed: %s\n", what, err)
+	}
+}
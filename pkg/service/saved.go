@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"time"
+)
+
+var _ Saved = (*SavedService)(nil)
+
+type SavedService struct {
+	dbUser  userdata.UserData
+	dbPosts itemdata.ItemData
+}
+
+func NewSavedService(dbUser userdata.UserData, dbPosts itemdata.ItemData) *SavedService {
+	return &SavedService{
+		dbUser:  dbUser,
+		dbPosts: dbPosts,
+	}
+}
+
+func (savServ *SavedService) SaveItem(postID, commentID, userID string) error {
+	return savServ.addItem(postID, commentID, userID, userdata.ListSaved)
+}
+
+func (savServ *SavedService) UnsaveItem(postID, commentID, userID string) error {
+	return savServ.dbUser.RemoveItem(userID, userdata.ListSaved, postID, commentID)
+}
+
+func (savServ *SavedService) HideItem(postID, commentID, userID string) error {
+	return savServ.addItem(postID, commentID, userID, userdata.ListHidden)
+}
+
+func (savServ *SavedService) UnhideItem(postID, commentID, userID string) error {
+	return savServ.dbUser.RemoveItem(userID, userdata.ListHidden, postID, commentID)
+}
+
+func (savServ *SavedService) addItem(postID, commentID, userID, list string) error {
+	post, err := savServ.dbPosts.GetPostID(postID)
+	if err != nil {
+		return err
+	}
+	if commentID != "" {
+		comment, ok := post.FindComment(commentID)
+		if !ok || comment.Deleted {
+			return errors.New("invalid comment id")
+		}
+	}
+	if list == userdata.ListHidden {
+		if err = savServ.checkHiddenLimit(postID, commentID, userID); err != nil {
+			return err
+		}
+	}
+	return savServ.dbUser.AddItem(userID, list, userdata.ItemRef{
+		PostID:    postID,
+		CommentID: commentID,
+		Created:   time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// checkHiddenLimit refuses to hide more than MaxHidden items, the listings
+// read only that many. Hiding an item again is allowed.
+func (savServ *SavedService) checkHiddenLimit(postID, commentID, userID string) error {
+	hidden, err := savServ.dbUser.Items(userID, userdata.ListHidden, userdata.MaxHidden)
+	if err != nil {
+		return err
+	}
+	if len(hidden) < userdata.MaxHidden {
+		return nil
+	}
+	for _, el := range hidden {
+		if el.PostID == postID && el.CommentID == commentID {
+			return nil
+		}
+	}
+	return errors.New("too many hidden items")
+}
+
+// SavedItems is shown only to the owner of the list, the most recently saved
+// items come first and the ones deleted since are left out.
+func (savServ *SavedService) SavedItems(login, userID string, limit int) ([]itemdata.SavedItem, error) {
+	ownerID, err := savServ.dbUser.CheckUser(login)
+	if err != nil {
+		return nil, errors.New("invalid user login")
+	}
+	if ownerID != userID {
+		return nil, errors.New("invalid user id")
+	}
+	if limit <= 0 || limit > itemdata.MaxPageSize {
+		limit = itemdata.DefaultPageSize
+	}
+	refs, err := savServ.dbUser.Items(userID, userdata.ListSaved, limit)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]itemdata.SavedItem, 0, len(refs))
+	for _, el := range refs {
+		post, err := savServ.dbPosts.GetPostID(el.PostID)
+		if err != nil {
+			continue
+		}
+		if item, ok := post.SavedItem(el.CommentID, el.Created); ok {
+			res = append(res, item)
+		}
+	}
+	return res, nil
+}
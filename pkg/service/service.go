@@ -1,46 +1,170 @@
 package service
 
 import (
+	"gitlab.com/vk-go/lectures-2022-2/pkg/automod"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/blob"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/hasher"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/keyring"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
 	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	messagedata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/messageData"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	reportdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData"
+	spamdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/spamData"
 	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
 	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"log"
 )
 
 //go:generate mockgen -source=service.go -destination=mocks/mock.go
 
 type Authorization interface {
 	CreateUser(user userdata.User) (userdata.User, error)
-	GenerateToken(login, password string) (string, error)
-	GetHash(password string) string
+	GenerateToken(login, password string, client session.Client) (session.TokenPair, error)
+	RefreshToken(refresh string) (session.TokenPair, error)
+	PublicKeys() keyring.JWKSet
+	ListSessions(userID, currentID string) ([]session.Session, error)
+	DeleteSession(userID, sessionID string) error
+	DeleteSessions(userID string) error
 }
 
 type Posts interface {
 	CreatePost(post itemdata.CreatePost, userID string) (itemdata.Post, error)
-	GetPosts() ([]itemdata.Post, error)
-	GetCategory(category string) ([]itemdata.Post, error)
-	GetName(login string) ([]itemdata.Post, error)
-	GetPostID(id string) (itemdata.Post, error)
+	GetPosts(opts itemdata.ListOptions) (itemdata.PostPage, error)
+	GetCategory(category string, opts itemdata.ListOptions) (itemdata.PostPage, error)
+	GetName(login string, opts itemdata.ListOptions) (itemdata.PostPage, error)
+	GetFeed(opts itemdata.ListOptions) (itemdata.PostPage, error)
+	GetPostID(id, order, viewerID string) (itemdata.Post, error)
+	EditPost(id, userID, text string) (itemdata.Post, error)
+	PostHistory(id, userID string) ([]itemdata.Revision, error)
 	DeletePost(id string, userID string) error
+	Search(query itemdata.SearchQuery) ([]itemdata.SearchHit, error)
 }
 
 type Comments interface {
-	CreateComm(postID, userID, comment string) (itemdata.Post, error)
+	CreateComm(postID, userID, parentID, comment string) (itemdata.Post, error)
+	EditComm(postID, userID, commID, comment string) (itemdata.Post, error)
+	CommentHistory(postID, userID, commID string) ([]itemdata.Revision, error)
 	DeleteComm(postID, userID, commID string) (itemdata.Post, error)
 	Upvote(postID, userID string) (itemdata.Post, error)
 	Downvote(postID, userID string) (itemdata.Post, error)
 	Unvote(postID, userID string) (itemdata.Post, error)
+	UpvoteComment(postID, commentID, userID string) (itemdata.Post, error)
+	DownvoteComment(postID, commentID, userID string) (itemdata.Post, error)
+	UnvoteComment(postID, commentID, userID string) (itemdata.Post, error)
+}
+
+type Communities interface {
+	CreateCommunity(name, description, userID string) (communitydata.Community, error)
+	GetCommunity(name string) (communitydata.Community, error)
+	GetCommunities() ([]communitydata.Community, error)
+	EditCommunity(name, userID, description string) (communitydata.Community, error)
+	Subscribe(name, userID string) (communitydata.Community, error)
+	Unsubscribe(name, userID string) (communitydata.Community, error)
+	Subscriptions(userID string) ([]string, error)
+}
+
+type Moderation interface {
+	AddModerator(name, userID, login string) ([]string, error)
+	RemoveModerator(name, userID, login string) ([]string, error)
+	Moderators(name string) ([]string, error)
+	LockPost(postID, userID, reason string) (itemdata.Post, error)
+	UnlockPost(postID, userID, reason string) (itemdata.Post, error)
+	PinPost(postID, userID, reason string) (itemdata.Post, error)
+	UnpinPost(postID, userID, reason string) (itemdata.Post, error)
+	BanUser(name, userID, login, reason string) error
+	UnbanUser(name, userID, login, reason string) error
+	ModLog(name string, limit int) ([]communitydata.ModAction, error)
+	HeldItems(name, userID string) ([]itemdata.HeldItem, error)
+	Approve(postID, commentID, userID string) (itemdata.Post, error)
+}
+
+type Admin interface {
+	ListUsers(limit, offset int) ([]userdata.UserInfo, error)
+	SetRole(adminID, userID, role string) (userdata.UserInfo, error)
+	SuspendUser(adminID, userID string) (userdata.UserInfo, error)
+	UnsuspendUser(adminID, userID string) (userdata.UserInfo, error)
+	RemovePost(adminID, postID string) error
+	RemoveComment(adminID, postID, commID string) (itemdata.Post, error)
+}
+
+type Reports interface {
+	ReportPost(postID, userID, reason string) (reportdata.Report, error)
+	ReportComment(postID, commentID, userID, reason string) (reportdata.Report, error)
+	ReportQueue(community, userID string, limit int) ([]reportdata.QueueItem, error)
+	ApproveReported(community, userID, postID, commentID string) ([]reportdata.Report, error)
+	RemoveReported(community, userID, postID, commentID string) ([]reportdata.Report, error)
+	MyReports(userID string, limit int) ([]reportdata.Report, error)
+}
+
+type Saved interface {
+	SaveItem(postID, commentID, userID string) error
+	UnsaveItem(postID, commentID, userID string) error
+	HideItem(postID, commentID, userID string) error
+	UnhideItem(postID, commentID, userID string) error
+	SavedItems(login, userID string, limit int) ([]itemdata.SavedItem, error)
+}
+
+type Profiles interface {
+	Profile(login string) (userdata.Profile, error)
+	UserComments(login string, opts itemdata.ListOptions) (itemdata.ActivityPage, error)
+	Overview(login string, opts itemdata.ListOptions) (itemdata.ActivityPage, error)
+}
+
+type Notifications interface {
+	ListNotifications(userID string, limit int, unreadOnly bool) ([]notificationdata.Notification, error)
+	UnreadCount(userID string) (int, error)
+	MarkRead(userID, id string) error
+	MarkAllRead(userID string) error
+}
+
+type Messages interface {
+	SendMessage(userID, toLogin, body string) (messagedata.Message, error)
+	Inbox(userID string, limit int, unreadOnly bool) ([]messagedata.Message, error)
+	Sent(userID string, limit int) ([]messagedata.Message, error)
+	Conversation(conversationID, userID string) ([]messagedata.Message, error)
+	BlockUser(userID, login string) error
+	UnblockUser(userID, login string) error
+	BlockedUsers(userID string) ([]string, error)
+}
+
+type Follows interface {
+	FollowUser(userID, login string) error
+	UnfollowUser(userID, login string) error
+	Following(userID string) ([]string, error)
 }
 
 type Service struct {
 	Authorization
 	Posts
 	Comments
+	Communities
+	Moderation
+	Admin
+	Reports
+	Saved
+	Profiles
+	Notifications
+	Messages
+	Follows
 }
 
-func NewService(userDat userdata.UserData, itemDat itemdata.ItemData, sessionManager session.SesManager, key []byte) *Service {
+func NewService(userDat userdata.UserData, itemDat itemdata.ItemData, communityDat communitydata.CommunityData,
+	reportDat reportdata.ReportData, spamDat spamdata.SpamData, notificationDat notificationdata.NotificationData,
+	messageDat messagedata.MessageData, sessionManager session.SesManager, passHasher hasher.PasswordHasher,
+	keys *keyring.KeyRing, rules *automod.Engine, blobs blob.BlobStore, logger *log.Logger) *Service {
 	return &Service{
-		Authorization: NewAuthService(userDat, sessionManager, key),
-		Posts:         NewPostService(userDat, itemDat),
-		Comments:      NewCommentService(userDat, itemDat),
+		Authorization: NewAuthService(userDat, sessionManager, passHasher, keys),
+		Posts:         NewPostService(userDat, itemDat, communityDat, rules, spamDat, notificationDat, blobs, logger),
+		Comments:      NewCommentService(userDat, itemDat, communityDat, rules, spamDat, notificationDat, logger),
+		Communities:   NewCommunityService(communityDat),
+		Moderation:    NewModerationService(userDat, itemDat, communityDat, spamDat, notificationDat, logger),
+		Admin:         NewAdminService(userDat, itemDat, communityDat, spamDat, notificationDat, sessionManager, blobs, logger),
+		Reports:       NewReportService(userDat, itemDat, communityDat, reportDat, spamDat, notificationDat, blobs, logger),
+		Saved:         NewSavedService(userDat, itemDat),
+		Profiles:      NewProfileService(userDat, itemDat),
+		Notifications: NewNotificationService(notificationDat),
+		Messages:      NewMessageService(userDat, messageDat),
+		Follows:       NewFollowService(userDat),
 	}
 }
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"gitlab.com/vk-go/lectures-2022-2/pkg/hasher"
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData/communityDataMap"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData/itemDataMap"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/repository/messageData/messageDataMap"
+	notificationdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/repository/notificationData/notificationDataMap"
+	reportdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/repository/reportData/reportDataMap"
+	spamdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/spamData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/repository/spamData/spamDataMap"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData/userDataMap"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
+	"log"
+	"os"
+	"testing"
+)
+
+// testEnv is a service over the in-memory backends.
+type testEnv struct {
+	*Service
+	users       userdata.UserData
+	items       itemdata.ItemData
+	communities communitydata.CommunityData
+	reports     reportdata.ReportData
+	notes       notificationdata.NotificationData
+	spam        spamdata.SpamData
+	blobs       memBlobs
+	logger      *log.Logger
+}
+
+func newTestEnv() *testEnv {
+	env := &testEnv{
+		users:       userdatamap.NewUserDataMap(),
+		items:       itemdatamap.NewItemDataMap(),
+		communities: communitydatamap.NewCommunityDataMap(),
+		reports:     reportdatamap.NewReportDataMap(),
+		notes:       notificationdatamap.NewNotificationDataMap(),
+		spam:        spamdatamap.NewSpamDataMap(),
+		blobs:       memBlobs{},
+		logger:      log.New(os.Stdout, "STD ", log.LUTC|log.Lshortfile),
+	}
+	env.Service = NewService(env.users, env.items, env.communities, env.reports, env.spam,
+		env.notes, messagedatamap.NewMessageDataMap(), nil, hasher.NewMD5(), nil, nil, env.blobs, env.logger)
+	return env
+}
+
+func (env *testEnv) addUser(t *testing.T, login, role string) string {
+	t.Helper()
+	user, err := env.users.InsertUser(userdata.User{Login: login, Password: "12345678", Role: role})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user.ID
+}
+
+func TestAdminLogins(t *testing.T) {
+	env := newTestEnv()
+	id := env.addUser(t, "root", userdata.RoleUser)
+	if err := GrantAdmins(env.users, []string{"root", "boss"}); err != nil {
+		t.Fatal(err)
+	}
+	if user, _ := env.users.GetUser(id); user.Role != userdata.RoleAdmin {
+		t.Errorf("results not match, want %v, have %v", userdata.RoleAdmin, user.Role)
+	}
+	boss, err := env.CreateUser(userdata.User{Login: "boss", Password: "12345678"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user, _ := env.users.GetUser(boss.ID); user.Role != userdata.RoleUser {
+		t.Errorf("results not match, want %v, have %v", userdata.RoleUser, user.Role)
+	}
+}
+
+// revokedSessions records whose sessions were deleted.
+type revokedSessions struct {
+	session.SesManager
+	users []string
+}
+
+func (s *revokedSessions) DeleteAllForUser(userID string) error {
+	s.users = append(s.users, userID)
+	return nil
+}
+
+func TestSetRole_RevokesSessions(t *testing.T) {
+	env := newTestEnv()
+	admin := env.addUser(t, "root", userdata.RoleAdmin)
+	mod := env.addUser(t, "mod", userdata.RoleModerator)
+	sessions := &revokedSessions{}
+	admServ := NewAdminService(env.users, env.items, env.communities, env.spam, env.notes, sessions, env.blobs, env.logger)
+	info, err := admServ.SetRole(admin, mod, userdata.RoleUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Role != userdata.RoleUser {
+		t.Errorf("results not match, want %v, have %v", userdata.RoleUser, info.Role)
+	}
+	if len(sessions.users) != 1 || sessions.users[0] != mod {
+		t.Errorf("results not match, want %v, have %v", []string{mod}, sessions.users)
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"fmt"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/automod"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	spamdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/spamData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/spam"
+)
+
+// checkSpam holds the content when the filter scores it as spam, unless the
+// rules already removed or held it.
+func checkSpam(dbSpam spamdata.SpamData, decision *automod.Decision, text string) error {
+	if decision.Remove || decision.Hold {
+		return nil
+	}
+	tokens := spam.Tokens(text)
+	counts, err := dbSpam.Counts(tokens)
+	if err != nil {
+		return err
+	}
+	if p := spam.Probability(tokens, counts); p >= spam.Threshold {
+		decision.Hold = true
+		decision.Reason = fmt.Sprintf("spam score %.2f", p)
+	}
+	return nil
+}
+
+// trainSpam learns from a moderator decision: removed content is spam,
+// approved content is not.
+func trainSpam(dbSpam spamdata.SpamData, text string, isSpam bool) error {
+	return dbSpam.Train(spam.Tokens(text), isSpam)
+}
+
+func postText(post itemdata.Post) string {
+	return post.Title + "\n" + post.Text
+}
+
+// itemText is the text of the post or, with commentID, of the comment, empty if it is gone.
+func itemText(post itemdata.Post, commentID string) string {
+	if commentID == "" {
+		return postText(post)
+	}
+	comment, ok := post.FindComment(commentID)
+	if !ok || comment.Deleted {
+		return ""
+	}
+	return comment.Body
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	communitydata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/communityData"
+	itemdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/itemData"
+	userdata "gitlab.com/vk-go/lectures-2022-2/pkg/repository/userData"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/spam"
+	"testing"
+)
+
+const spamText = "buy cheap pills now https://bit.ly/pills"
+
+func (env *testEnv) trainSpam(t *testing.T) {
+	t.Helper()
+	for i := 0; i < spam.MinTrained; i++ {
+		for _, el := range []struct {
+			text   string
+			isSpam bool
+		}{{spamText, true}, {"how do generics work in go", false}, {"my cat learned to open the door", false}} {
+			if err := trainSpam(env.spam, el.text, el.isSpam); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestEdit_Rescored(t *testing.T) {
+	env := newTestEnv()
+	author := env.addUser(t, "author", userdata.RoleUser)
+	other := env.addUser(t, "other", userdata.RoleUser)
+	env.trainSpam(t)
+	post := env.addPost(t, author, "music", "my cat learned to open the door")
+	if post.Status != "" {
+		t.Fatalf("clean post held: %v", post.Status)
+	}
+	post, err := env.CreateComm(post.ID, author, "", "how do generics work in go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commID := post.Comments[0].ID
+
+	edited, err := env.EditPost(post.ID, author, spamText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited.Status != itemdata.StatusHeld {
+		t.Errorf("results not match, want %v, have %v", itemdata.StatusHeld, edited.Status)
+	}
+	if _, err = env.GetPostID(post.ID, "old", other); err == nil {
+		t.Errorf("held post shown after the edit")
+	}
+
+	edited, err = env.EditComm(post.ID, author, commID, spamText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comm, _ := edited.FindComment(commID); comm.Status != itemdata.StatusHeld {
+		t.Errorf("results not match, want %v, have %v", itemdata.StatusHeld, comm.Status)
+	}
+
+	log, err := env.communities.ModLog("music", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	holds := 0
+	for _, el := range log {
+		if el.Action == communitydata.ActionHold && el.Moderator == AutoModerator {
+			holds++
+		}
+	}
+	if holds != 2 {
+		t.Errorf("results not match, want %v, have %v", 2, holds)
+	}
+}
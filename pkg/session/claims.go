@@ -0,0 +1,33 @@
+package session
+
+import (
+	"context"
+	"github.com/dgrijalva/jwt-go"
+)
+
+type User struct {
+	ID    string `json:"id"`
+	Login string `json:"username"`
+	Role  string `json:"role,omitempty"`
+}
+
+type Claims struct {
+	User User `json:"user"`
+	jwt.StandardClaims
+}
+
+type ctxKey int
+
+const claimsKey ctxKey = iota
+
+func ContextWithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*Claims)
+	if !ok || claims == nil {
+		return nil, false
+	}
+	return claims, true
+}
@@ -0,0 +1,37 @@
+package session
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidRefresh = errors.New("invalid refresh token")
+	ErrRefreshReused  = errors.New("refresh token reuse detected")
+)
+
+type TokenPair struct {
+	Access  string `json:"token"`
+	Refresh string `json:"refresh_token"`
+}
+
+// RefreshToken is a single-use credential. All refresh tokens issued for one
+// session form a family: presenting an already used one revokes the session.
+type RefreshToken struct {
+	TokenHash string
+	SessionID string
+	UserID    string
+	Created   time.Time
+	Expires   time.Time
+	Used      bool
+}
+
+func NewRefreshToken(token, sessionID, userID string, expires time.Time) *RefreshToken {
+	return &RefreshToken{
+		TokenHash: HashToken(token),
+		SessionID: sessionID,
+		UserID:    userID,
+		Created:   time.Now().UTC(),
+		Expires:   expires.UTC(),
+	}
+}
@@ -1,13 +1,59 @@
 package session
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
 type Session struct {
-	Token  string
-	UserID string
+	ID        string    `json:"id"`
+	UserID    string    `json:"-"`
+	TokenHash string    `json:"-"`
+	Created   time.Time `json:"created"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Expires   time.Time `json:"expires"`
+	UserAgent string    `json:"userAgent"`
+	IP        string    `json:"ip"`
+	Current   bool      `json:"current"`
 }
 
-func NewSession(token, userID string) *Session {
+// MaxUserAgent is the length of the user_agent column, a longer one would fail the insert.
+const MaxUserAgent = 512
+
+type Client struct {
+	UserAgent string
+	IP        string
+}
+
+func NewSession(id, token, userID string, client Client, expires time.Time) *Session {
+	now := time.Now().UTC()
 	return &Session{
-		UserID: userID,
-		Token:  token,
+		ID:        id,
+		UserID:    userID,
+		TokenHash: HashToken(token),
+		Created:   now,
+		LastSeen:  now,
+		Expires:   expires.UTC(),
+		UserAgent: truncate(client.UserAgent, MaxUserAgent),
+		IP:        client.IP,
 	}
 }
+
+// truncate cuts s to at most n runes.
+func truncate(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
+
+// HashToken is what gets stored instead of the token itself, so a leaked
+// sessions table cannot be replayed.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,6 +1,16 @@
 package session
 
+import (
+	"time"
+)
+
 type SesManager interface {
 	Check(token string) (string, error)
-	Create(token, userID string) error
+	Create(session *Session) error
+	Rotate(sessionID, token string, expires time.Time) error
+	Delete(sessionID, userID string) error
+	DeleteAllForUser(userID string) error
+	ListForUser(userID string) ([]Session, error)
+	CreateRefresh(token *RefreshToken) error
+	UseRefresh(token string) (RefreshToken, error)
 }
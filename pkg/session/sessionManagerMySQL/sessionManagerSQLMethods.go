@@ -1,19 +1,127 @@
 package sessionmanagermysql
 
 import (
+	"database/sql"
+	"errors"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
 	"strconv"
+	"time"
 )
 
-func (manager *SessionManagerMySQL) Create(token, userID string) error {
-	_, err := manager.db.Exec("UPDATE userDB SET token=? WHERE user_id=?", token, userID)
+func (manager *SessionManagerMySQL) Create(ses *session.Session) error {
+	_, err := manager.db.Exec("INSERT INTO sessions (session_id, user_id, token_hash, created, last_seen, expires, "+
+		"user_agent, ip) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", ses.ID, ses.UserID, ses.TokenHash, ses.Created,
+		ses.LastSeen, ses.Expires, ses.UserAgent, ses.IP)
 	return err
 }
 
 func (manager *SessionManagerMySQL) Check(token string) (string, error) {
+	var sessionID string
 	var userID int
-	res := manager.db.QueryRow("SELECT user_id FROM userDB WHERE token=?", token)
-	if err := res.Scan(&userID); err != nil {
+	now := time.Now().UTC()
+	res := manager.db.QueryRow("SELECT session_id, user_id FROM sessions WHERE token_hash = ? AND expires > ?",
+		session.HashToken(token), now)
+	if err := res.Scan(&sessionID, &userID); err != nil {
+		return "", err
+	}
+	if _, err := manager.db.Exec("UPDATE sessions SET last_seen = ? WHERE session_id = ?", now, sessionID); err != nil {
 		return "", err
 	}
 	return strconv.Itoa(userID), nil
 }
+
+func (manager *SessionManagerMySQL) Delete(sessionID, userID string) error {
+	res, err := manager.db.Exec("DELETE FROM sessions WHERE session_id = ? AND user_id = ?", sessionID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("invalid session id")
+	}
+	return nil
+}
+
+func (manager *SessionManagerMySQL) DeleteAllForUser(userID string) error {
+	_, err := manager.db.Exec("DELETE FROM sessions WHERE user_id = ?", userID)
+	return err
+}
+
+func (manager *SessionManagerMySQL) ListForUser(userID string) ([]session.Session, error) {
+	rows, err := manager.db.Query("SELECT session_id, created, last_seen, expires, user_agent, ip FROM sessions "+
+		"WHERE user_id = ? AND expires > ? ORDER BY last_seen DESC", userID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make([]session.Session, 0, 5)
+	for rows.Next() {
+		ses := session.Session{UserID: userID}
+		if err = rows.Scan(&ses.ID, &ses.Created, &ses.LastSeen, &ses.Expires, &ses.UserAgent, &ses.IP); err != nil {
+			return nil, err
+		}
+		res = append(res, ses)
+	}
+	return res, rows.Err()
+}
+
+func (manager *SessionManagerMySQL) Rotate(sessionID, token string, expires time.Time) error {
+	res, err := manager.db.Exec("UPDATE sessions SET token_hash = ?, last_seen = ?, expires = ? WHERE session_id = ?",
+		session.HashToken(token), time.Now().UTC(), expires.UTC(), sessionID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("invalid session id")
+	}
+	return nil
+}
+
+func (manager *SessionManagerMySQL) CreateRefresh(token *session.RefreshToken) error {
+	_, err := manager.db.Exec("INSERT INTO refresh_tokens (token_hash, session_id, user_id, created, expires, used) "+
+		"VALUES (?, ?, ?, ?, ?, ?)", token.TokenHash, token.SessionID, token.UserID, token.Created, token.Expires,
+		token.Used)
+	return err
+}
+
+// UseRefresh marks the token as used inside a transaction, so two concurrent
+// refreshes with the same token cannot both succeed.
+func (manager *SessionManagerMySQL) UseRefresh(token string) (session.RefreshToken, error) {
+	res := session.RefreshToken{TokenHash: session.HashToken(token)}
+	tx, err := manager.db.Begin()
+	if err != nil {
+		return res, err
+	}
+	defer tx.Rollback()
+	var userID int
+	row := tx.QueryRow("SELECT session_id, user_id, created, expires, used FROM refresh_tokens "+
+		"WHERE token_hash = ? FOR UPDATE", res.TokenHash)
+	if err = row.Scan(&res.SessionID, &userID, &res.Created, &res.Expires, &res.Used); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return res, session.ErrInvalidRefresh
+		}
+		return res, err
+	}
+	res.UserID = strconv.Itoa(userID)
+	if res.Used {
+		return res, session.ErrRefreshReused
+	}
+	if !res.Expires.After(time.Now()) {
+		return res, session.ErrInvalidRefresh
+	}
+	if _, err = tx.Exec("UPDATE refresh_tokens SET used = TRUE WHERE token_hash = ?", res.TokenHash); err != nil {
+		return res, err
+	}
+	if err = tx.Commit(); err != nil {
+		return res, err
+	}
+	res.Used = true
+	return res, nil
+}
@@ -2,9 +2,11 @@ package sessionmanagermysql
 
 import (
 	"errors"
+	"gitlab.com/vk-go/lectures-2022-2/pkg/session"
 	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestSession_Create(t *testing.T) {
@@ -15,42 +17,42 @@ func TestSession_Create(t *testing.T) {
 	defer db.Close()
 
 	repo := NewSessionManagerMySQL(db)
-	type mockBehaviour func(token, userID string)
+	type mockBehaviour func(ses *session.Session)
 	testTable := []struct {
 		name          string
 		mockBehaviour mockBehaviour
-		token         string
-		userID        string
+		session       *session.Session
 		err           error
 	}{
 		{
 			name: "ok",
-			mockBehaviour: func(token, userID string) {
-				mock.ExpectExec("UPDATE userDB").
-					WithArgs(token, userID).
-					WillReturnResult(sqlmock.NewErrorResult(nil))
+			mockBehaviour: func(ses *session.Session) {
+				mock.ExpectExec("INSERT INTO sessions").
+					WithArgs(ses.ID, ses.UserID, ses.TokenHash, ses.Created, ses.LastSeen, ses.Expires,
+						ses.UserAgent, ses.IP).
+					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
-			token:  "111",
-			userID: "1",
-			err:    nil,
+			session: session.NewSession("abc", "111", "1", session.Client{UserAgent: "curl", IP: "127.0.0.1"},
+				time.Now().Add(time.Hour)),
+			err: nil,
 		},
 		{
 			name: "invalid user id",
-			mockBehaviour: func(token, userID string) {
-				mock.ExpectExec("UPDATE userDB").
-					WithArgs(token, userID).
+			mockBehaviour: func(ses *session.Session) {
+				mock.ExpectExec("INSERT INTO sessions").
+					WithArgs(ses.ID, ses.UserID, ses.TokenHash, ses.Created, ses.LastSeen, ses.Expires,
+						ses.UserAgent, ses.IP).
 					WillReturnError(errors.New("invalid user id"))
 			},
-			token:  "111",
-			userID: "1",
-			err:    errors.New("invalid user id"),
+			session: session.NewSession("abc", "111", "1", session.Client{}, time.Now().Add(time.Hour)),
+			err:     errors.New("invalid user id"),
 		},
 	}
 
 	for _, testCase := range testTable {
 		t.Run(testCase.name, func(t *testing.T) {
-			testCase.mockBehaviour(testCase.token, testCase.userID)
-			err := repo.Create(testCase.token, testCase.userID)
+			testCase.mockBehaviour(testCase.session)
+			err := repo.Create(testCase.session)
 			if testCase.err != nil {
 				if !reflect.DeepEqual(err.Error(), testCase.err.Error()) {
 					t.Errorf("results not match, want %v, have %v", testCase.err.Error(), err.Error())
@@ -89,9 +91,12 @@ func TestSession_Check(t *testing.T) {
 		{
 			name: "ok",
 			mockBehaviour: func(token, userID string) {
-				mock.ExpectQuery("SELECT user_id FROM userDB").
-					WithArgs(token).
-					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
+				mock.ExpectQuery("SELECT session_id, user_id FROM sessions").
+					WithArgs(session.HashToken(token), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"session_id", "user_id"}).AddRow("abc", userID))
+				mock.ExpectExec("UPDATE sessions SET last_seen").
+					WithArgs(sqlmock.AnyArg(), "abc").
+					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			token:  "111",
 			userID: "1",
@@ -100,8 +105,8 @@ func TestSession_Check(t *testing.T) {
 		{
 			name: "invalid token",
 			mockBehaviour: func(token, userID string) {
-				mock.ExpectQuery("SELECT user_id FROM userDB").
-					WithArgs(token).
+				mock.ExpectQuery("SELECT session_id, user_id FROM sessions").
+					WithArgs(session.HashToken(token), sqlmock.AnyArg()).
 					WillReturnError(errors.New("invalid token"))
 			},
 			token:  "111",
@@ -138,3 +143,202 @@ func TestSession_Check(t *testing.T) {
 	}
 
 }
+
+func TestSession_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewSessionManagerMySQL(db)
+	type mockBehaviour func(sessionID, userID string)
+	testTable := []struct {
+		name          string
+		mockBehaviour mockBehaviour
+		sessionID     string
+		userID        string
+		err           error
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func(sessionID, userID string) {
+				mock.ExpectExec("DELETE FROM sessions WHERE session_id").
+					WithArgs(sessionID, userID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			sessionID: "abc",
+			userID:    "1",
+			err:       nil,
+		},
+		{
+			name: "foreign session",
+			mockBehaviour: func(sessionID, userID string) {
+				mock.ExpectExec("DELETE FROM sessions WHERE session_id").
+					WithArgs(sessionID, userID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			sessionID: "abc",
+			userID:    "2",
+			err:       errors.New("invalid session id"),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehaviour(testCase.sessionID, testCase.userID)
+			err := repo.Delete(testCase.sessionID, testCase.userID)
+			if testCase.err != nil {
+				if err == nil || !reflect.DeepEqual(err.Error(), testCase.err.Error()) {
+					t.Errorf("results not match, want %v, have %v", testCase.err, err)
+					return
+				}
+			} else {
+				if err != nil {
+					t.Errorf("unexpected err: %s", err)
+					return
+				}
+				if err = mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("there were unfulfilled expectations: %s", err)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestSession_ListForUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewSessionManagerMySQL(db)
+	now := time.Date(2022, 11, 4, 17, 55, 14, 0, time.UTC)
+	mock.ExpectQuery("SELECT session_id, created, last_seen, expires, user_agent, ip FROM sessions").
+		WithArgs("1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"session_id", "created", "last_seen", "expires", "user_agent", "ip"}).
+			AddRow("abc", now, now, now.Add(time.Hour), "curl", "127.0.0.1").
+			AddRow("def", now, now, now.Add(time.Hour), "firefox", "10.0.0.1"))
+	res, err := repo.ListForUser("1")
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+	want := []session.Session{
+		{ID: "abc", UserID: "1", Created: now, LastSeen: now, Expires: now.Add(time.Hour), UserAgent: "curl", IP: "127.0.0.1"},
+		{ID: "def", UserID: "1", Created: now, LastSeen: now, Expires: now.Add(time.Hour), UserAgent: "firefox", IP: "10.0.0.1"},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("results not match, want %v, have %v", want, res)
+	}
+}
+
+func TestSession_UseRefresh(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewSessionManagerMySQL(db)
+	now := time.Now().UTC()
+	columns := []string{"session_id", "user_id", "created", "expires", "used"}
+	type mockBehaviour func(token string)
+	testTable := []struct {
+		name          string
+		mockBehaviour mockBehaviour
+		token         string
+		want          session.RefreshToken
+		err           error
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func(token string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT session_id, user_id, created, expires, used FROM refresh_tokens").
+					WithArgs(session.HashToken(token)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("abc", 1, now, now.Add(time.Hour), false))
+				mock.ExpectExec("UPDATE refresh_tokens SET used").
+					WithArgs(session.HashToken(token)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			token: "111",
+			want: session.RefreshToken{
+				TokenHash: session.HashToken("111"),
+				SessionID: "abc",
+				UserID:    "1",
+				Created:   now,
+				Expires:   now.Add(time.Hour),
+				Used:      true,
+			},
+			err: nil,
+		},
+		{
+			name: "reused token",
+			mockBehaviour: func(token string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT session_id, user_id, created, expires, used FROM refresh_tokens").
+					WithArgs(session.HashToken(token)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("abc", 1, now, now.Add(time.Hour), true))
+				mock.ExpectRollback()
+			},
+			token: "111",
+			want: session.RefreshToken{
+				TokenHash: session.HashToken("111"),
+				SessionID: "abc",
+				UserID:    "1",
+				Created:   now,
+				Expires:   now.Add(time.Hour),
+				Used:      true,
+			},
+			err: session.ErrRefreshReused,
+		},
+		{
+			name: "expired token",
+			mockBehaviour: func(token string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT session_id, user_id, created, expires, used FROM refresh_tokens").
+					WithArgs(session.HashToken(token)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("abc", 1, now, now.Add(-time.Hour), false))
+				mock.ExpectRollback()
+			},
+			token: "111",
+			err:   session.ErrInvalidRefresh,
+		},
+		{
+			name: "unknown token",
+			mockBehaviour: func(token string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT session_id, user_id, created, expires, used FROM refresh_tokens").
+					WithArgs(session.HashToken(token)).
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectRollback()
+			},
+			token: "111",
+			err:   session.ErrInvalidRefresh,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehaviour(testCase.token)
+			res, err := repo.UseRefresh(testCase.token)
+			if !errors.Is(err, testCase.err) {
+				t.Errorf("results not match, want %v, have %v", testCase.err, err)
+				return
+			}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+				return
+			}
+			if testCase.want.SessionID != "" && !reflect.DeepEqual(res, testCase.want) {
+				t.Errorf("results not match, want %v, have %v", testCase.want, res)
+			}
+		})
+	}
+}
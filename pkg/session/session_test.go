@@ -0,0 +1,28 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestNewSession_UserAgent(t *testing.T) {
+	testingTable := []struct {
+		name string
+		ua   string
+		want int
+	}{
+		{name: "short", ua: "curl/7.0", want: 8},
+		{name: "long", ua: strings.Repeat("a", MaxUserAgent+10), want: MaxUserAgent},
+		{name: "multibyte", ua: strings.Repeat("я", MaxUserAgent+1), want: MaxUserAgent},
+	}
+	for _, testCase := range testingTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			sess := NewSession("1", "token", "1", Client{UserAgent: testCase.ua}, time.Now())
+			if have := utf8.RuneCountInString(sess.UserAgent); have != testCase.want {
+				t.Errorf("results not match, want %v, have %v", testCase.want, have)
+			}
+		})
+	}
+}
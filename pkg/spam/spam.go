@@ -0,0 +1,76 @@
+package spam
+
+import (
+	"gitlab.com/vk-go/lectures-2022-2/pkg/search"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Threshold is the probability from which new content is held for review.
+	Threshold = 0.9
+	// MinTrained is how many spam and how many ham documents the filter needs
+	// to have seen before it scores anything.
+	MinTrained = 10
+
+	maxTokens      = 200
+	maxTokenLength = 64
+)
+
+// TokenCount is the number of spam and ham documents a token was found in.
+type TokenCount struct {
+	Spam int
+	Ham  int
+}
+
+// Counts is the part of the trained model needed to score one document.
+type Counts struct {
+	SpamDocs int
+	HamDocs  int
+	Tokens   map[string]TokenCount
+}
+
+var linkRe = regexp.MustCompile(`https?://[^\s/?#"'<>]+`)
+
+// Tokens returns the distinct words of the text and a "host:" token for every
+// linked host, spam is often recognised by where it links to.
+func Tokens(text string) []string {
+	seen := make(map[string]bool)
+	res := make([]string, 0, 16)
+	add := func(token string) {
+		if len(res) >= maxTokens || len(token) > maxTokenLength || seen[token] {
+			return
+		}
+		seen[token] = true
+		res = append(res, token)
+	}
+	for _, link := range linkRe.FindAllString(text, -1) {
+		if u, err := url.Parse(link); err == nil && u.Hostname() != "" {
+			add("host:" + strings.TrimPrefix(strings.ToLower(u.Hostname()), "www."))
+		}
+	}
+	for _, el := range search.Tokenize(text) {
+		add(el)
+	}
+	return res
+}
+
+// Probability that a document with the tokens is spam, by naive Bayes over
+// the documents the tokens were seen in with add-one smoothing. It is 0 while
+// the filter is not trained enough.
+func Probability(tokens []string, c Counts) float64 {
+	if c.SpamDocs < MinTrained || c.HamDocs < MinTrained {
+		return 0
+	}
+	spamDocs, hamDocs := float64(c.SpamDocs), float64(c.HamDocs)
+	logSpam := math.Log(spamDocs / (spamDocs + hamDocs))
+	logHam := math.Log(hamDocs / (spamDocs + hamDocs))
+	for _, el := range tokens {
+		count := c.Tokens[el]
+		logSpam += math.Log((float64(count.Spam) + 1) / (spamDocs + 2))
+		logHam += math.Log((float64(count.Ham) + 1) / (hamDocs + 2))
+	}
+	return 1 / (1 + math.Exp(logHam-logSpam))
+}
@@ -0,0 +1,50 @@
+package spam
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	want := []string{"host:bit.ly", "cheap", "pills", "https", "www", "bit", "ly", "x"}
+	if res := Tokens("Cheap pills, cheap! https://www.bit.ly/x"); !reflect.DeepEqual(res, want) {
+		t.Errorf("results not match, want %v, have %v", want, res)
+	}
+}
+
+func TestProbability(t *testing.T) {
+	c := Counts{Tokens: make(map[string]TokenCount)}
+	train := func(text string, spam bool) {
+		for _, el := range Tokens(text) {
+			count := c.Tokens[el]
+			if spam {
+				count.Spam++
+			} else {
+				count.Ham++
+			}
+			c.Tokens[el] = count
+		}
+		if spam {
+			c.SpamDocs++
+		} else {
+			c.HamDocs++
+		}
+	}
+	for i := 0; i < MinTrained; i++ {
+		train("buy cheap pills now https://bit.ly/pills", true)
+		train("casino bonus free spins https://spam.example", true)
+	}
+	if p := Probability(Tokens("cheap pills"), c); p != 0 {
+		t.Errorf("untrained filter scored %v", p)
+	}
+	for i := 0; i < MinTrained; i++ {
+		train("how do generics work in go", false)
+		train("my cat learned to open the door", false)
+	}
+	if p := Probability(Tokens("Cheap pills here: https://bit.ly/more"), c); p < Threshold {
+		t.Errorf("spam scored %v", p)
+	}
+	if p := Probability(Tokens("generics in go are great"), c); p > 0.1 {
+		t.Errorf("ham scored %v", p)
+	}
+}
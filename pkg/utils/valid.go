@@ -35,6 +35,7 @@ func UnmarshalCreatPost(buf []byte, post *itemdata.CreatePost) error {
 }
 
 func MarshalPost(post itemdata.Post) ([]byte, error) {
+	post.Comments = itemdata.CommentTree(post.PublicComments())
 	var resPost interface{}
 	if post.Type == "link" {
 		resPost = struct {
@@ -76,3 +77,17 @@ func MarshalSlice(posts []itemdata.Post) ([]byte, error) {
 	res = append(res, []byte("]")...)
 	return res, nil
 }
+
+func MarshalPage(page itemdata.PostPage) ([]byte, error) {
+	posts, err := MarshalSlice(page.Posts)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Posts json.RawMessage `json:"posts"`
+		Next  string          `json:"next,omitempty"`
+	}{
+		Posts: posts,
+		Next:  page.Next,
+	})
+}
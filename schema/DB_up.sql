@@ -1,10 +1,219 @@
 SET NAMES utf8;
 
+DROP TABLE IF EXISTS follows;
+DROP TABLE IF EXISTS blocks;
+DROP TABLE IF EXISTS messages;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS spam_tokens;
+DROP TABLE IF EXISTS spam_docs;
+DROP TABLE IF EXISTS user_items;
+DROP TABLE IF EXISTS reports;
+DROP TABLE IF EXISTS modlog;
+DROP TABLE IF EXISTS pins;
+DROP TABLE IF EXISTS bans;
+DROP TABLE IF EXISTS moderators;
+DROP TABLE IF EXISTS subscriptions;
+DROP TABLE IF EXISTS communities;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS sessions;
 DROP TABLE IF EXISTS userDB;
 CREATE TABLE userDB(
     user_id INT AUTO_INCREMENT NOT NULL,
     login VARCHAR(255) NOT NULL,
     password VARCHAR(255) NOT NULL,
-    token VARCHAR(255),
+    role VARCHAR(16) NOT NULL DEFAULT 'user',
+    suspended BOOLEAN NOT NULL DEFAULT FALSE,
+    created DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     PRIMARY KEY (user_id)
-);
\ No newline at end of file
+);
+
+CREATE TABLE sessions(
+    session_id VARCHAR(64) NOT NULL,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    created DATETIME NOT NULL,
+    last_seen DATETIME NOT NULL,
+    expires DATETIME NOT NULL,
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    PRIMARY KEY (session_id),
+    UNIQUE KEY (token_hash),
+    KEY (user_id),
+    FOREIGN KEY (user_id) REFERENCES userDB(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE refresh_tokens(
+    token_hash CHAR(64) NOT NULL,
+    session_id VARCHAR(64) NOT NULL,
+    user_id INT NOT NULL,
+    created DATETIME NOT NULL,
+    expires DATETIME NOT NULL,
+    used BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (token_hash),
+    KEY (session_id),
+    FOREIGN KEY (session_id) REFERENCES sessions(session_id) ON DELETE CASCADE
+);
+
+CREATE TABLE communities(
+    name VARCHAR(21) NOT NULL,
+    description TEXT NOT NULL,
+    owner_id INT NULL,
+    created DATETIME NOT NULL,
+    PRIMARY KEY (name),
+    FOREIGN KEY (owner_id) REFERENCES userDB(user_id) ON DELETE SET NULL
+);
+
+INSERT INTO communities (name, description, created) VALUES
+    ('music', '', NOW()),
+    ('funny', '', NOW()),
+    ('videos', '', NOW()),
+    ('programming', '', NOW()),
+    ('news', '', NOW()),
+    ('fashion', '', NOW());
+
+CREATE TABLE subscriptions(
+    name VARCHAR(21) NOT NULL,
+    user_id INT NOT NULL,
+    PRIMARY KEY (name, user_id),
+    KEY (user_id),
+    FOREIGN KEY (name) REFERENCES communities(name) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES userDB(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE moderators(
+    name VARCHAR(21) NOT NULL,
+    user_id INT NOT NULL,
+    PRIMARY KEY (name, user_id),
+    KEY (user_id),
+    FOREIGN KEY (name) REFERENCES communities(name) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES userDB(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE bans(
+    name VARCHAR(21) NOT NULL,
+    user_id INT NOT NULL,
+    created DATETIME NOT NULL,
+    PRIMARY KEY (name, user_id),
+    FOREIGN KEY (name) REFERENCES communities(name) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES userDB(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE pins(
+    name VARCHAR(21) NOT NULL,
+    slot TINYINT NOT NULL,
+    post_id VARCHAR(64) NOT NULL,
+    PRIMARY KEY (name, slot),
+    UNIQUE KEY (name, post_id),
+    FOREIGN KEY (name) REFERENCES communities(name) ON DELETE CASCADE
+);
+
+CREATE TABLE modlog(
+    id INT AUTO_INCREMENT NOT NULL,
+    name VARCHAR(21) NOT NULL,
+    moderator VARCHAR(255) NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    target VARCHAR(255) NOT NULL,
+    reason VARCHAR(512) NOT NULL DEFAULT '',
+    created DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY (name, id),
+    FOREIGN KEY (name) REFERENCES communities(name) ON DELETE CASCADE
+);
+
+CREATE TABLE reports(
+    id INT AUTO_INCREMENT NOT NULL,
+    post_id VARCHAR(64) NOT NULL,
+    comment_id VARCHAR(64) NOT NULL DEFAULT '',
+    community VARCHAR(21) NOT NULL,
+    reporter_id INT NOT NULL,
+    reason VARCHAR(512) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'open',
+    created DATETIME NOT NULL,
+    resolved DATETIME NULL,
+    PRIMARY KEY (id),
+    KEY (community, status),
+    KEY (post_id, comment_id, status),
+    KEY (reporter_id),
+    FOREIGN KEY (community) REFERENCES communities(name) ON DELETE CASCADE,
+    FOREIGN KEY (reporter_id) REFERENCES userDB(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE spam_docs(
+    id TINYINT NOT NULL,
+    spam INT NOT NULL DEFAULT 0,
+    ham INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (id)
+);
+INSERT INTO spam_docs (id) VALUES (1);
+
+CREATE TABLE spam_tokens(
+    token VARCHAR(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,
+    spam INT NOT NULL DEFAULT 0,
+    ham INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (token)
+);
+
+CREATE TABLE user_items(
+    id INT AUTO_INCREMENT NOT NULL,
+    user_id INT NOT NULL,
+    list VARCHAR(16) NOT NULL,
+    post_id VARCHAR(64) NOT NULL,
+    comment_id VARCHAR(64) NOT NULL DEFAULT '',
+    created DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY (user_id, list, post_id, comment_id),
+    KEY (user_id, list, created),
+    FOREIGN KEY (user_id) REFERENCES userDB(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE notifications(
+    id INT AUTO_INCREMENT NOT NULL,
+    user_id INT NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    post_id VARCHAR(64) NOT NULL,
+    comment_id VARCHAR(64) NOT NULL DEFAULT '',
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    is_read BOOLEAN NOT NULL DEFAULT FALSE,
+    created DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY (user_id, is_read),
+    FOREIGN KEY (user_id) REFERENCES userDB(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE messages(
+    id INT AUTO_INCREMENT NOT NULL,
+    conversation VARCHAR(64) NOT NULL,
+    from_id INT NOT NULL,
+    from_login VARCHAR(255) NOT NULL,
+    to_id INT NOT NULL,
+    to_login VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    is_read BOOLEAN NOT NULL DEFAULT FALSE,
+    created DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY (to_id, is_read),
+    KEY (from_id),
+    KEY (conversation),
+    FOREIGN KEY (from_id) REFERENCES userDB(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (to_id) REFERENCES userDB(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE blocks(
+    user_id INT NOT NULL,
+    blocked_id INT NOT NULL,
+    created DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, blocked_id),
+    FOREIGN KEY (user_id) REFERENCES userDB(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (blocked_id) REFERENCES userDB(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE follows(
+    user_id INT NOT NULL,
+    followed_id INT NOT NULL,
+    created DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, followed_id),
+    FOREIGN KEY (user_id) REFERENCES userDB(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (followed_id) REFERENCES userDB(user_id) ON DELETE CASCADE
+);